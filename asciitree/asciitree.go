@@ -123,6 +123,17 @@ func (t *Object) Tree(w io.Writer, name string, desc string, indent string, flag
 
 //CreateType : convert OAS Schema to internal ProtoType
 func CreateType(schema *oasmodel.Schema) ProtoType {
+	return createType(schema, make(map[*oasmodel.Schema]bool))
+}
+
+// createType : visiting holds schemas being converted, recursive schemas are drawn once
+func createType(schema *oasmodel.Schema, visiting map[*oasmodel.Schema]bool) ProtoType {
+	if visiting[schema] {
+		return &TypeName{AscTypeObject}
+	}
+	visiting[schema] = true
+	defer delete(visiting, schema)
+
 	if schema.AllOf != nil {
 		node := Object{nil}
 		// parse all allOf members
@@ -130,7 +141,7 @@ func CreateType(schema *oasmodel.Schema) ProtoType {
 			current := schema.AllOf[i].Schema()
 			for m := range current.Properties {
 				prop := current.Properties[m].Schema()
-				f := ObjectMembers{CreateType(prop), m, prop.Description}
+				f := ObjectMembers{createType(prop, visiting), m, prop.Description}
 				node.body = append(node.body, f)
 			}
 		}
@@ -140,40 +151,40 @@ func CreateType(schema *oasmodel.Schema) ProtoType {
 	// Case AdditionnalProperties
 	if schema.AdditionalProperties != nil {
 		// MUST be type object
-		if schema.Type != "object" {
+		if schema.TypeName() != "object" {
 			fmt.Fprintf(os.Stderr, "Schema with Additional Properties MUST be an object\n")
 		}
-		objType := CreateType(schema.AdditionalProperties.Schema.Schema())
+		objType := createType(schema.AdditionalProperties.Schema.Schema(), visiting)
 		node := Map{"string", objType}
 		return &node
 	}
 
-	if schema.Type == "object" {
+	if schema.TypeName() == "object" {
 		// otherwise
 		node := Object{nil}
 		for m := range schema.Properties {
 			prop := schema.Properties[m].Schema()
-			f := ObjectMembers{CreateType(prop), m, prop.Description}
+			f := ObjectMembers{createType(prop, visiting), m, prop.Description}
 			node.body = append(node.body, f)
 		}
 		return &node
 	}
 
-	if schema.Type == "array" {
-		t := CreateType(schema.Items.Schema())
+	if schema.TypeName() == "array" {
+		t := createType(schema.Items.Schema(), visiting)
 		return &Array{t}
 	}
 
-	if schema.Type == "boolean" {
+	if schema.TypeName() == "boolean" {
 		return &TypeName{AscTypeBool}
 	}
 
-	if schema.Type == "integer" {
+	if schema.TypeName() == "integer" {
 		return &TypeName{AscTypeInt}
 	}
 
 	// Enums
-	if schema.Type == "string" && len(schema.Enum) > 0 {
+	if schema.TypeName() == "string" && len(schema.Enum) > 0 {
 		node := Enum{nil}
 		for i := range schema.Enum {
 			node.values = append(node.values, schema.Enum[i])
@@ -184,8 +195,11 @@ func CreateType(schema *oasmodel.Schema) ProtoType {
 }
 
 //Components2Proto : generate proto file from Parsed OpenAPI definition
-func Components2AscTree(oa *oasmodel.OpenAPI, f io.Writer, root string) {
-	oa.ResolveRefs()
+func Components2AscTree(oa *oasmodel.OpenAPI, f io.Writer, root string) error {
+	err := oa.ResolveRefs()
+	if err != nil {
+		return err
+	}
 	// create first level Nodes
 	for k, v := range oa.Components.Schemas {
 		if k == root {
@@ -193,4 +207,5 @@ func Components2AscTree(oa *oasmodel.OpenAPI, f io.Writer, root string) {
 			node.Tree(f, k, v.Schema().Description, "", FlagFirst)
 		}
 	}
+	return nil
 }
@@ -1,19 +1,18 @@
 package main
 
 import (
-	"bytes"
 	"flag"
 	"fmt"
 	"io/ioutil"
 	"log"
 	"os"
-	"os/exec"
 	"path/filepath"
 	"runtime/debug"
 	"strings"
 
 	"github.com/Axili39/oastools/oasmodel"
 	"github.com/Axili39/oastools/protobuf"
+	"google.golang.org/protobuf/types/descriptorpb"
 )
 
 // Multiples file in command lines
@@ -28,37 +27,38 @@ func (i *stringList) Set(value string) error {
 	return nil
 }
 
-func compileProto(protofilename string, directory string) {
-	protoPath := filepath.Dir(protofilename)
-	cmd := exec.Command("protoc", "--go_out="+directory, "--proto_path="+protoPath, protofilename)
-	var out bytes.Buffer
-	cmd.Stdout = &out
-	cmd.Stderr = os.Stdout
-	err := cmd.Run()
-	if err != nil {
-		fmt.Fprintf(os.Stderr, "error %v running protoc command: %v\n", err, cmd)
-		os.Exit(1)
-	}
-}
-
 func main() {
-	build := flag.String("build", "", "build with protoc")
-	file := flag.String("f", "", "yaml file to parse")
+	build := flag.String("build", "", "generate Go code in this directory with protoc-gen-go, descriptors are built without protoc")
+	file := flag.String("f", "", "yaml or json file to parse")
+	dump := flag.String("dump", "", "write the loaded spec in yaml or json instead of .proto")
 	out := flag.String("o", "", "output file")
+	outDir := flag.String("out-dir", "", "write a .proto per document referenced by the spec in this directory")
 	verbose := flag.Bool("verbose", false, "show log")
 	AddEnumPrefix := flag.Bool("add-enum-prefix", false, "Auto add prefix on Enums")
 	NoMsgPrefix := flag.Bool("no-msg-prefix", false, "Do not add Prefix to nested message type")
 	packageName := flag.String("p", "", "package name eg: foo.bar")
 	showversion := flag.Bool("v", false, "show version")
+	services := flag.Bool("services", false, "generate a service per tag with an rpc per operation")
+	serviceName := flag.String("service", "", "generate a single service with this name")
+	httpAnnotations := flag.Bool("http-annotations", false, "add google.api.http options to rpcs")
+	presence := flag.String("presence", "", "presence of singular fields : none, optional (proto3 optional for non required scalars) or wrappers (wrapper types for nullable scalars)")
+	validateRules := flag.String("validate", "", "add validation rules from schema constraints : none, pgv (protoc-gen-validate) or buf (protovalidate)")
+	enumUnspecified := flag.Bool("enum-unspecified", false, "add <ENUM>_UNSPECIFIED = 0 to string enums")
+	enumValueOption := flag.Bool("enum-value-option", false, "record values of renamed enum constants with the (json_value) option")
+	requiredOption := flag.Bool("required-option", false, "mark required properties with the (required) field option")
+	checkCompat := flag.String("check-compat", "", "report wire incompatible changes from this previous version of the spec, instead of generating")
+	lockFile := flag.String("lock", "", "lock file keeping field numbers across generations, eg: spec.protolock.json")
 	var options stringList
 	flag.Var(&options, "option", "add directive option in .proto file (multi)")
 	var filteredNodes stringList
 	flag.Var(&filteredNodes, "node", "select component (multi)")
 	var packageNameMap stringList
 	flag.Var(&packageNameMap, "rename-package", "rename package imports")
+	var typeMap stringList
+	flag.Var(&typeMap, "type-map", "map a format to a proto type, eg: string/date=google.type.Date:google/type/date.proto (multi)")
 	flag.Parse()
 
-	genOpts := protobuf.GenerationOptions{AddEnumPrefix: *AddEnumPrefix, Imports: make(map[string]bool), PackageNames: map[string]string{}, AddMsgPrefix: !(*NoMsgPrefix)}
+	genOpts := protobuf.GenerationOptions{AddEnumPrefix: *AddEnumPrefix, Imports: make(map[string]bool), PackageNames: map[string]string{}, AddMsgPrefix: !(*NoMsgPrefix), Services: *services, ServiceName: *serviceName, HTTPAnnotations: *httpAnnotations, RequiredOption: *requiredOption, EnumUnspecified: *enumUnspecified, EnumValueOption: *enumValueOption}
 
 	if *showversion {
 		if info, available := debug.ReadBuildInfo(); available {
@@ -100,22 +100,147 @@ func main() {
 		os.Exit(1)
 	}
 
+	if *dump != "" {
+		format, err := oasmodel.ParseFormat(*dump)
+		if err == nil {
+			err = oa.Write(output, format)
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "error writing %s : %v\n", *file, err)
+			os.Exit(1)
+		}
+		return
+	}
+
 	// load package Map
 	for _, p := range packageNameMap {
 		pair := strings.Split(p, ":")
 		if len(pair) != 2 {
-			fmt.Fprintf(os.Stderr, "error bad package rename :", p)
+			fmt.Fprintf(os.Stderr, "error bad package rename : %s\n", p)
 		}
 		genOpts.PackageNames[pair[0]] = pair[1]
 	}
 
-	err = protobuf.Components2Proto(&oa, output, *packageName, genOpts, filteredNodes, options...)
+	genOpts.TypeMapping = protobuf.DefaultTypeMapping()
+	for _, definition := range typeMap {
+		if err := genOpts.TypeMapping.Set(definition); err != nil {
+			fmt.Fprintf(os.Stderr, "%v\n", err)
+			os.Exit(1)
+		}
+	}
+
+	genOpts.ValidateRules, err = protobuf.ParseValidateRules(*validateRules)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		os.Exit(1)
+	}
+
+	genOpts.Presence, err = protobuf.ParsePresence(*presence)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		os.Exit(1)
+	}
+
+	if *lockFile != "" {
+		genOpts.Lock, err = protobuf.LoadLock(*lockFile)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%v\n", err)
+			os.Exit(1)
+		}
+	}
+
+	if *checkCompat != "" {
+		os.Exit(checkCompatibility(*checkCompat, &oa, genOpts, filteredNodes))
+	}
+
+	if *outDir != "" {
+		if *file == "" {
+			fmt.Fprintf(os.Stderr, "error -out-dir requires -f\n")
+			os.Exit(1)
+		}
+		files, err := protobuf.Spec2ProtoTree(*file, *outDir, *packageName, genOpts, options...)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "error parsing %s : %v\n", *file, err)
+			os.Exit(1)
+		}
+		saveLock(genOpts.Lock, *lockFile)
+		if *build != "" {
+			compileProto(files, *build)
+		}
+		return
+	}
+
+	// the file is named as protoc would, relative to its directory
+	var name string
+	if *out != "" {
+		name = filepath.Base(*out)
+	}
+	descriptor, err := protobuf.CreateFile(&oa, name, *packageName, genOpts, filteredNodes, options...)
+	if err == nil {
+		err = protobuf.Print(output, descriptor)
+	}
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "error parsing %s : %v", *file, err)
 		os.Exit(1)
 	}
 
+	saveLock(genOpts.Lock, *lockFile)
+
 	if *build != "" && *out != "" {
-		compileProto(*out, *build)
+		compileProto([]*descriptorpb.FileDescriptorProto{descriptor}, *build)
+	}
+}
+
+func compileProto(files []*descriptorpb.FileDescriptorProto, directory string) {
+	err := buildGo(files, directory)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error building Go code : %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func saveLock(lock *protobuf.Lock, lockFile string) {
+	if lock == nil {
+		return
+	}
+	if err := lock.Save(lockFile); err != nil {
+		fmt.Fprintf(os.Stderr, "error writing %s : %v\n", lockFile, err)
+		os.Exit(1)
+	}
+}
+
+// checkCompatibility compares generations of the previous and current spec, the lock file isn't updated.
+// The exit code is 1 if breaking changes are found.
+func checkCompatibility(oldFile string, oa *oasmodel.OpenAPI, genOpts protobuf.GenerationOptions, filteredNodes []string) int {
+	old := oasmodel.OpenAPI{}
+	err := old.Load(oldFile)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error loading %s : %v\n", oldFile, err)
+		return 1
+	}
+	lock := genOpts.Lock
+	if lock != nil {
+		genOpts.Lock = lock.Clone()
+	}
+	oldTypes, _, err := protobuf.CreateTypes(&old, genOpts, filteredNodes)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error parsing %s : %v\n", oldFile, err)
+		return 1
+	}
+	if lock != nil {
+		genOpts.Lock = lock.Clone()
+	}
+	newTypes, _, err := protobuf.CreateTypes(oa, genOpts, filteredNodes)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error parsing spec : %v\n", err)
+		return 1
+	}
+	changes := protobuf.Compare(oldTypes, newTypes)
+	for _, c := range changes {
+		fmt.Println(c)
+	}
+	if len(changes) > 0 {
+		return 1
 	}
+	return 0
 }
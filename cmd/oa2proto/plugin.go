@@ -0,0 +1,103 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+
+	"github.com/Axili39/oastools/protobuf"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/pluginpb"
+)
+
+// goPlugin generates Go code from descriptors, it is run as protoc would
+const goPlugin = "protoc-gen-go"
+
+// buildGo writes Go code of files in directory, descriptors are built in-process instead of by protoc
+func buildGo(files []*descriptorpb.FileDescriptorProto, directory string) error {
+	request, err := codeGeneratorRequest(files)
+	if err != nil {
+		return err
+	}
+	input, err := proto.Marshal(request)
+	if err != nil {
+		return err
+	}
+	plugin, err := exec.LookPath(goPlugin)
+	if err != nil {
+		return fmt.Errorf("%v, install it with : go install google.golang.org/protobuf/cmd/protoc-gen-go@latest", err)
+	}
+	cmd := exec.Command(plugin)
+	cmd.Stdin = bytes.NewReader(input)
+	cmd.Stderr = os.Stderr
+	output, err := cmd.Output()
+	if err != nil {
+		return fmt.Errorf("error running %s : %v", goPlugin, err)
+	}
+	response := &pluginpb.CodeGeneratorResponse{}
+	err = proto.Unmarshal(output, response)
+	if err != nil {
+		return fmt.Errorf("bad %s response : %v", goPlugin, err)
+	}
+	if response.Error != nil {
+		return fmt.Errorf("%s : %s", goPlugin, response.GetError())
+	}
+	for _, f := range response.File {
+		filename := filepath.Join(directory, filepath.FromSlash(f.GetName()))
+		err = os.MkdirAll(filepath.Dir(filename), 0755)
+		if err != nil {
+			return err
+		}
+		err = ioutil.WriteFile(filename, []byte(f.GetContent()), 0644)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// codeGeneratorRequest : files to generate, with all their imports in topological order as sent by protoc
+func codeGeneratorRequest(files []*descriptorpb.FileDescriptorProto) (*pluginpb.CodeGeneratorRequest, error) {
+	registry, err := protobuf.NewFiles(files)
+	if err != nil {
+		return nil, err
+	}
+	request := &pluginpb.CodeGeneratorRequest{}
+	added := make(map[string]bool)
+	var add func(fd protoreflect.FileDescriptor) error
+	add = func(fd protoreflect.FileDescriptor) error {
+		if added[fd.Path()] {
+			return nil
+		}
+		added[fd.Path()] = true
+		imports := fd.Imports()
+		for i := 0; i < imports.Len(); i++ {
+			imported := imports.Get(i)
+			if imported.IsPlaceholder() {
+				return fmt.Errorf("%s : import %s isn't available in-process", fd.Path(), imported.Path())
+			}
+			if err := add(imported.FileDescriptor); err != nil {
+				return err
+			}
+		}
+		request.ProtoFile = append(request.ProtoFile, protodesc.ToFileDescriptorProto(fd))
+		return nil
+	}
+	for _, f := range files {
+		fd, err := registry.FindFileByPath(f.GetName())
+		if err != nil {
+			return nil, err
+		}
+		if err := add(fd); err != nil {
+			return nil, err
+		}
+		request.FileToGenerate = append(request.FileToGenerate, f.GetName())
+	}
+	return request, nil
+}
@@ -0,0 +1,72 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+
+	"github.com/Axili39/oastools/oasmodel"
+)
+
+// exit codes
+const (
+	exitOK      = 0
+	exitInvalid = 1
+	exitError   = 2
+)
+
+func main() {
+	file := flag.String("f", "", "yaml or json file to check")
+	format := flag.String("format", "text", "report format : text or json")
+	strict := flag.Bool("strict", false, "fail on warnings")
+	verbose := flag.Bool("verbose", false, "show log")
+	flag.Parse()
+
+	if !*verbose {
+		log.SetFlags(0)
+		log.SetOutput(ioutil.Discard)
+	}
+	if *file == "" {
+		fmt.Fprintf(os.Stderr, "usage: oalint -f FILE [-format text|json] [-strict]\n")
+		os.Exit(exitError)
+	}
+
+	// external references are followed by the loader
+	oa, err := oasmodel.NewLoader().Load(*file)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error loading %s : %v\n", *file, err)
+		os.Exit(exitError)
+	}
+	diags := oa.Validate()
+
+	switch *format {
+	case "text":
+		for _, d := range diags {
+			fmt.Println(d)
+		}
+	case "json":
+		if diags == nil {
+			diags = []oasmodel.Diagnostic{}
+		}
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		err = enc.Encode(diags)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "error : %v\n", err)
+			os.Exit(exitError)
+		}
+	default:
+		fmt.Fprintf(os.Stderr, "unknown format %s\n", *format)
+		os.Exit(exitError)
+	}
+
+	for _, d := range diags {
+		if d.Severity == oasmodel.SeverityError || *strict {
+			os.Exit(exitInvalid)
+		}
+	}
+	os.Exit(exitOK)
+}
@@ -12,7 +12,7 @@ import (
 
 func main() {
 	var root = flag.String("r", "root", "root node")
-	var file = flag.String("f", "test.yaml", "file")
+	var file = flag.String("f", "test.yaml", "yaml or json file")
 	flag.Parse()
 	//Test Function (to be removed)
 	w := new(tabwriter.Writer)
@@ -22,11 +22,15 @@ func main() {
 
 	defer w.Flush()
 
-	oa := oasmodel.OpenAPI{}
-	err := oa.Load(*file)
+	// external references are followed by the loader
+	oa, err := oasmodel.NewLoader().Load(*file)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "error loading %s : %v", *file, err)
 		os.Exit(1)
 	}
-	asciitree.Components2AscTree(&oa, w, *root)
+	err = asciitree.Components2AscTree(oa, w, *root)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error : %v\n", err)
+		os.Exit(1)
+	}
 }
@@ -0,0 +1,91 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+	"sort"
+
+	"github.com/Axili39/oastools/oasmodel"
+	"github.com/Axili39/oastools/oatool"
+	"github.com/Axili39/oastools/protobuf"
+	"github.com/Axili39/oastools/validate"
+)
+
+// listComponents prints components of the spec, candidates for -c
+func listComponents(oa *oasmodel.OpenAPI) {
+	names := make([]string, 0, len(oa.Components.Schemas))
+	for k := range oa.Components.Schemas {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+	for _, k := range names {
+		fmt.Fprintln(os.Stderr, "\t", k)
+	}
+}
+
+func main() {
+	var specFile = flag.String("spec", "", "oas file, documents it references are followed")
+	var component = flag.String("c", "", "component name in spec file")
+	var input = flag.String("if", "", "input file .json/.yaml/.bin")
+	var output = flag.String("of", "", "<file>.json|yaml|bin")
+	var toformat = flag.String("tofmt", "", "json|yaml|bin force output format")
+	var validateOnly = flag.Bool("validate", false, "validate input against the schema and exit")
+	var verbose = flag.Bool("verbose", false, "show log")
+	flag.Parse()
+
+	if !*verbose {
+		log.SetFlags(0)
+		log.SetOutput(ioutil.Discard)
+	}
+
+	if *specFile == "" {
+		fmt.Fprintln(os.Stderr, "missing spec file")
+		os.Exit(1)
+	}
+
+	// external references are followed by the loader
+	oa, err := oasmodel.NewLoader().Load(*specFile)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error loading %s : %v\n", *specFile, err)
+		os.Exit(1)
+	}
+	schema := oa.Components.Schemas[*component]
+	if schema == nil {
+		fmt.Fprintf(os.Stderr, "component %s doesn't exists, candidate are :\n", *component)
+		listComponents(oa)
+		os.Exit(1)
+	}
+
+	// the message type is built at runtime, no generated code is needed
+	mt, err := protobuf.MessageType(*specFile, *component, protobuf.GenerationOptions{})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error building %s : %v\n", *component, err)
+		os.Exit(1)
+	}
+	obj := mt.New().Interface()
+
+	if *validateOnly {
+		value, err := oatool.LoadInstance(*input, obj, schema)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "error Loading file: %v\n", err)
+			os.Exit(1)
+		}
+		violations := validate.Validate(schema, value)
+		for _, v := range violations {
+			fmt.Fprintf(os.Stderr, "%s: %s\n", *input, v)
+		}
+		if len(violations) > 0 {
+			os.Exit(1)
+		}
+		return
+	}
+
+	err = oatool.Convert(*input, *output, *toformat, obj)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		os.Exit(1)
+	}
+}
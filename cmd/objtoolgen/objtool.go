@@ -1,18 +1,20 @@
 //go:generate res2go -package main -prefix Rsrc -o resources.go resources/*.template
 package main
 
-// TODO : embed schema
 // TODO : add option in generated tool to dump schema
 
 import (
 	"bytes"
 	"flag"
 	"fmt"
-	"html/template"
+	"go/format"
 	"io"
+	"io/ioutil"
 	"os"
 	"os/exec"
+	"path/filepath"
 	"strings"
+	"text/template"
 
 	"github.com/Axili39/oastools/oasmodel"
 	"github.com/Axili39/oastools/protobuf"
@@ -20,33 +22,33 @@ import (
 
 type genCtx struct {
 	Package   string
-	Component string
+	Schema    string            // component name in spec file
+	Documents map[string]string // documents of the spec embedded by relative path, the message is built from them at runtime
+	Root      string            // path of the spec file in Documents
 }
 
 func (g *genCtx) generate(wr io.Writer) error {
 	fileTemplate := template.Must(template.New("").Parse(string(RsrcFiles["resources/objtool.go.template"])))
 
-	err := fileTemplate.Execute(wr, g)
+	var buf bytes.Buffer
+	err := fileTemplate.Execute(&buf, g)
 	if err != nil {
 		fmt.Fprintln(os.Stderr, "Error executing template", err)
+		return err
 	}
-	// TODO format generated code with go/format
-	return err
-}
-
-func genProto(file string, protofilename string, component string) {
-
-	// Step 1: Generate .proto with oa2proto
-	w, err := os.Create(protofilename)
+	code, err := format.Source(buf.Bytes())
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "error %v\n", err)
-		os.Exit(1)
+		return err
 	}
-	defer w.Close()
+	_, err = wr.Write(code)
+	return err
+}
 
-	oa := oasmodel.OpenAPI{}
-	genOpts := protobuf.GenerationOptions{AddEnumPrefix: false}
-	err = oa.Load(file)
+// checkComponent builds the component in-process, as the generated tool does, and returns the documents of the spec
+func checkComponent(file string, component string) []string {
+	// external references are followed by the loader
+	loader := oasmodel.NewLoader()
+	oa, err := loader.Load(file)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "error loading %s : %v", file, err)
 		os.Exit(1)
@@ -59,78 +61,59 @@ func genProto(file string, protofilename string, component string) {
 		os.Exit(1)
 	}
 
-	err = protobuf.Components2Proto(&oa, w, "foo.bar", genOpts, nil, "go_package=\".;main\"")
+	_, err = protobuf.MessageType(file, component, protobuf.GenerationOptions{})
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "error parsing %s : %v", file, err)
 		os.Exit(1)
 	}
+	return loader.Documents()
 }
 
-func compileProto(protofilename string, directory string) {
-	cmd := exec.Command("protoc", "--go_out="+directory, protofilename)
-	var out bytes.Buffer
-	cmd.Stdout = &out
-	cmd.Stderr = os.Stdout
-	err := cmd.Run()
+// embedDocuments reads documents, keyed by their path relative to the directory holding all of them
+func embedDocuments(file string, documents []string) (map[string]string, string, error) {
+	root, err := filepath.Abs(file)
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "error running protoc command %v\n", err)
-		os.Exit(1)
+		return nil, "", err
 	}
-}
-
-// from "google.golang.org/protobuf/internal/strs"
-func isASCIILower(c byte) bool {
-	return 'a' <= c && c <= 'z'
-}
-func isASCIIDigit(c byte) bool {
-	return '0' <= c && c <= '9'
-}
-func goCamelCase(s string) string {
-	// Invariant: if the next letter is lower case, it must be converted
-	// to upper case.
-	// That is, we process a word at a time, where words are marked by _ or
-	// upper case letter. Digits are treated as words.
-	var b []byte
-	for i := 0; i < len(s); i++ {
-		c := s[i]
-		switch {
-		case c == '.' && i+1 < len(s) && isASCIILower(s[i+1]):
-			// Skip over '.' in ".{{lowercase}}".
-		case c == '.':
-			b = append(b, '_') // convert '.' to '_'
-		case c == '_' && (i == 0 || s[i-1] == '.'):
-			// Convert initial '_' to ensure we start with a capital letter.
-			// Do the same for '_' after '.' to match historic behavior.
-			b = append(b, 'X') // convert '_' to 'X'
-		case c == '_' && i+1 < len(s) && isASCIILower(s[i+1]):
-			// Skip over '_' in "_{{lowercase}}".
-		case isASCIIDigit(c):
-			b = append(b, c)
-		default:
-			// Assume we have a letter now - if not, it's a bogus identifier.
-			// The next word is a sequence of characters that must start upper case.
-			if isASCIILower(c) {
-				c -= 'a' - 'A' // convert lowercase to uppercase
-			}
-			b = append(b, c)
-
-			// Accept lower case sequence that follows.
-			for ; i+1 < len(s) && isASCIILower(s[i+1]); i++ {
-				b = append(b, s[i+1])
-			}
+	base := filepath.Dir(root)
+	for _, document := range documents {
+		for !isUnder(base, document) && filepath.Dir(base) != base {
+			base = filepath.Dir(base)
+		}
+	}
+	embedded := make(map[string]string)
+	for _, document := range documents {
+		if !isUnder(base, document) {
+			return nil, "", fmt.Errorf("%s and %s have no common directory", file, document)
+		}
+		content, err := ioutil.ReadFile(document)
+		if err != nil {
+			return nil, "", err
 		}
+		rel, _ := filepath.Rel(base, document)
+		embedded[filepath.ToSlash(rel)] = string(content)
 	}
-	return string(b)
+	rel, _ := filepath.Rel(base, root)
+	return embedded, filepath.ToSlash(rel), nil
+}
+
+func isUnder(directory string, path string) bool {
+	rel, err := filepath.Rel(directory, path)
+	return err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
 }
 
-func genCfgTool(directory string, component string) {
+func genCfgTool(directory string, file string, component string, documents []string) {
 	wr, err := os.Create(directory + "/main.go")
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "error %v\n", err)
 	}
 	defer wr.Close()
-	// Verify that component exists
-	g := genCtx{"main", goCamelCase(component)}
+	embedded, root, err := embedDocuments(file, documents)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error %v\n", err)
+		os.Exit(1)
+	}
+	g := genCtx{"main", component, embedded, root}
 
 	err = g.generate(wr)
 	if err != nil {
@@ -178,7 +161,6 @@ func main() {
 	} else {
 		output = *outputfile
 	}
-	protofilename := output + "/" + output + ".proto"
 
 	// create directory
 	err := os.MkdirAll(output, 0750)
@@ -187,16 +169,13 @@ func main() {
 		os.Exit(1)
 	}
 
-	// Step 1: gen .proto file
-	genProto(*file, protofilename, *component)
-
-	// Step 2: Generate package with protoc
-	compileProto(protofilename, output)
+	// Step 1: check the message can be built from the spec
+	documents := checkComponent(*file, *component)
 
-	// Step 3: Generate filetoolcmd for package
-	genCfgTool(output, *component)
+	// Step 2: Generate filetoolcmd for the component
+	genCfgTool(output, *file, *component, documents)
 
-	// Step 4: Build if requested
+	// Step 3: Build if requested
 	if *build {
 		buildCfgTool(output)
 	}
@@ -7,47 +7,97 @@ func RsrcInit() {
 	RsrcFiles = make(map[string][]byte)
 	RsrcFiles["resources/objtool.go.template"] = []byte{
 		0x70, 0x61, 0x63, 0x6b, 0x61, 0x67, 0x65, 0x20, 0x6d, 0x61, 0x69, 0x6e, 0x0a, 0x0a, 0x69, 0x6d, 0x70, 0x6f, 0x72, 0x74, 0x20, 0x28, 0x0a, 0x09, 0x22, 0x66, 0x6c, 0x61, 0x67, 0x22, 0x0a, 0x09, 0x22, 0x66, 0x6d, 0x74, 0x22, 0x0a, 0x09, 0x22,
-		0x6f, 0x73, 0x22, 0x0a, 0x09, 0x22, 0x73, 0x74, 0x72, 0x69, 0x6e, 0x67, 0x73, 0x22, 0x0a, 0x0a, 0x09, 0x22, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x41, 0x78, 0x69, 0x6c, 0x69, 0x33, 0x39, 0x2f, 0x65, 0x6e, 0x63,
-		0x6f, 0x64, 0x69, 0x6e, 0x67, 0x74, 0x6f, 0x6f, 0x6c, 0x73, 0x22, 0x0a, 0x09, 0x22, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x67, 0x6f, 0x6c, 0x61, 0x6e, 0x67, 0x2e, 0x6f, 0x72, 0x67, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66,
-		0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0x0a, 0x29, 0x0a, 0x0a, 0x2f, 0x2f, 0x20, 0x6d, 0x61, 0x69, 0x6e, 0x4f, 0x62, 0x6a, 0x54, 0x6f, 0x6f, 0x6c, 0x20, 0x67, 0x65, 0x6e, 0x65, 0x72, 0x69, 0x63, 0x20, 0x6d, 0x61, 0x69, 0x6e, 0x20, 0x66,
-		0x75, 0x6e, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x20, 0x66, 0x6f, 0x72, 0x20, 0x74, 0x6f, 0x6f, 0x6c, 0x20, 0x67, 0x65, 0x6e, 0x65, 0x72, 0x61, 0x74, 0x65, 0x64, 0x20, 0x62, 0x79, 0x20, 0x6f, 0x61, 0x74, 0x6f, 0x6f, 0x6c, 0x67, 0x65, 0x6e, 0x0a,
-		0x66, 0x75, 0x6e, 0x63, 0x20, 0x6d, 0x61, 0x69, 0x6e, 0x4f, 0x62, 0x6a, 0x54, 0x6f, 0x6f, 0x6c, 0x28, 0x6f, 0x62, 0x6a, 0x20, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x29, 0x20, 0x7b, 0x0a, 0x09, 0x76,
+		0x69, 0x6f, 0x2f, 0x69, 0x6f, 0x75, 0x74, 0x69, 0x6c, 0x22, 0x0a, 0x09, 0x22, 0x6c, 0x6f, 0x67, 0x22, 0x0a, 0x09, 0x22, 0x6f, 0x73, 0x22, 0x0a, 0x09, 0x22, 0x70, 0x61, 0x74, 0x68, 0x2f, 0x66, 0x69, 0x6c, 0x65, 0x70, 0x61, 0x74, 0x68, 0x22,
+		0x0a, 0x0a, 0x09, 0x22, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x41, 0x78, 0x69, 0x6c, 0x69, 0x33, 0x39, 0x2f, 0x6f, 0x61, 0x73, 0x74, 0x6f, 0x6f, 0x6c, 0x73, 0x2f, 0x6f, 0x61, 0x73, 0x6d, 0x6f, 0x64, 0x65, 0x6c,
+		0x22, 0x0a, 0x09, 0x22, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x41, 0x78, 0x69, 0x6c, 0x69, 0x33, 0x39, 0x2f, 0x6f, 0x61, 0x73, 0x74, 0x6f, 0x6f, 0x6c, 0x73, 0x2f, 0x6f, 0x61, 0x74, 0x6f, 0x6f, 0x6c, 0x22, 0x0a,
+		0x09, 0x22, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x41, 0x78, 0x69, 0x6c, 0x69, 0x33, 0x39, 0x2f, 0x6f, 0x61, 0x73, 0x74, 0x6f, 0x6f, 0x6c, 0x73, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x22, 0x0a,
+		0x09, 0x22, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x41, 0x78, 0x69, 0x6c, 0x69, 0x33, 0x39, 0x2f, 0x6f, 0x61, 0x73, 0x74, 0x6f, 0x6f, 0x6c, 0x73, 0x2f, 0x76, 0x61, 0x6c, 0x69, 0x64, 0x61, 0x74, 0x65, 0x22, 0x0a,
+		0x09, 0x22, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x67, 0x6f, 0x6c, 0x61, 0x6e, 0x67, 0x2e, 0x6f, 0x72, 0x67, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0x0a, 0x29, 0x0a, 0x0a, 0x2f,
+		0x2f, 0x20, 0x64, 0x6f, 0x63, 0x75, 0x6d, 0x65, 0x6e, 0x74, 0x73, 0x20, 0x6f, 0x66, 0x20, 0x74, 0x68, 0x65, 0x20, 0x73, 0x70, 0x65, 0x63, 0x69, 0x66, 0x69, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x20, 0x74, 0x68, 0x65, 0x20, 0x74, 0x6f, 0x6f,
+		0x6c, 0x20, 0x77, 0x61, 0x73, 0x20, 0x67, 0x65, 0x6e, 0x65, 0x72, 0x61, 0x74, 0x65, 0x64, 0x20, 0x66, 0x72, 0x6f, 0x6d, 0x2c, 0x20, 0x62, 0x79, 0x20, 0x70, 0x61, 0x74, 0x68, 0x20, 0x72, 0x65, 0x6c, 0x61, 0x74, 0x69, 0x76, 0x65, 0x20, 0x74,
+		0x6f, 0x20, 0x74, 0x68, 0x65, 0x69, 0x72, 0x20, 0x63, 0x6f, 0x6d, 0x6d, 0x6f, 0x6e, 0x20, 0x64, 0x69, 0x72, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x79, 0x0a, 0x76, 0x61, 0x72, 0x20, 0x64, 0x6f, 0x63, 0x75, 0x6d, 0x65, 0x6e, 0x74, 0x73, 0x20, 0x3d,
+		0x20, 0x6d, 0x61, 0x70, 0x5b, 0x73, 0x74, 0x72, 0x69, 0x6e, 0x67, 0x5d, 0x73, 0x74, 0x72, 0x69, 0x6e, 0x67, 0x7b, 0x0a, 0x7b, 0x7b, 0x2d, 0x20, 0x72, 0x61, 0x6e, 0x67, 0x65, 0x20, 0x24, 0x70, 0x61, 0x74, 0x68, 0x2c, 0x20, 0x24, 0x63, 0x6f,
+		0x6e, 0x74, 0x65, 0x6e, 0x74, 0x20, 0x3a, 0x3d, 0x20, 0x2e, 0x44, 0x6f, 0x63, 0x75, 0x6d, 0x65, 0x6e, 0x74, 0x73, 0x7d, 0x7d, 0x0a, 0x09, 0x7b, 0x7b, 0x70, 0x72, 0x69, 0x6e, 0x74, 0x66, 0x20, 0x22, 0x25, 0x71, 0x22, 0x20, 0x24, 0x70, 0x61,
+		0x74, 0x68, 0x7d, 0x7d, 0x3a, 0x20, 0x7b, 0x7b, 0x70, 0x72, 0x69, 0x6e, 0x74, 0x66, 0x20, 0x22, 0x25, 0x71, 0x22, 0x20, 0x24, 0x63, 0x6f, 0x6e, 0x74, 0x65, 0x6e, 0x74, 0x7d, 0x7d, 0x2c, 0x0a, 0x7b, 0x7b, 0x2d, 0x20, 0x65, 0x6e, 0x64, 0x7d,
+		0x7d, 0x0a, 0x7d, 0x0a, 0x0a, 0x2f, 0x2f, 0x20, 0x72, 0x6f, 0x6f, 0x74, 0x20, 0x69, 0x73, 0x20, 0x74, 0x68, 0x65, 0x20, 0x70, 0x61, 0x74, 0x68, 0x20, 0x6f, 0x66, 0x20, 0x74, 0x68, 0x65, 0x20, 0x73, 0x70, 0x65, 0x63, 0x20, 0x66, 0x69, 0x6c,
+		0x65, 0x20, 0x69, 0x6e, 0x20, 0x64, 0x6f, 0x63, 0x75, 0x6d, 0x65, 0x6e, 0x74, 0x73, 0x0a, 0x63, 0x6f, 0x6e, 0x73, 0x74, 0x20, 0x72, 0x6f, 0x6f, 0x74, 0x20, 0x3d, 0x20, 0x7b, 0x7b, 0x70, 0x72, 0x69, 0x6e, 0x74, 0x66, 0x20, 0x22, 0x25, 0x71,
+		0x22, 0x20, 0x2e, 0x52, 0x6f, 0x6f, 0x74, 0x7d, 0x7d, 0x0a, 0x0a, 0x2f, 0x2f, 0x20, 0x65, 0x78, 0x74, 0x72, 0x61, 0x63, 0x74, 0x53, 0x70, 0x65, 0x63, 0x20, 0x77, 0x72, 0x69, 0x74, 0x65, 0x73, 0x20, 0x74, 0x68, 0x65, 0x20, 0x65, 0x6d, 0x62,
+		0x65, 0x64, 0x64, 0x65, 0x64, 0x20, 0x64, 0x6f, 0x63, 0x75, 0x6d, 0x65, 0x6e, 0x74, 0x73, 0x20, 0x69, 0x6e, 0x20, 0x61, 0x20, 0x74, 0x65, 0x6d, 0x70, 0x6f, 0x72, 0x61, 0x72, 0x79, 0x20, 0x64, 0x69, 0x72, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x79,
+		0x2c, 0x20, 0x72, 0x65, 0x66, 0x65, 0x72, 0x65, 0x6e, 0x63, 0x65, 0x73, 0x20, 0x62, 0x65, 0x74, 0x77, 0x65, 0x65, 0x6e, 0x20, 0x74, 0x68, 0x65, 0x6d, 0x20, 0x61, 0x72, 0x65, 0x20, 0x6b, 0x65, 0x70, 0x74, 0x0a, 0x66, 0x75, 0x6e, 0x63, 0x20,
+		0x65, 0x78, 0x74, 0x72, 0x61, 0x63, 0x74, 0x53, 0x70, 0x65, 0x63, 0x28, 0x29, 0x20, 0x28, 0x73, 0x74, 0x72, 0x69, 0x6e, 0x67, 0x2c, 0x20, 0x73, 0x74, 0x72, 0x69, 0x6e, 0x67, 0x29, 0x20, 0x7b, 0x0a, 0x09, 0x64, 0x69, 0x72, 0x65, 0x63, 0x74,
+		0x6f, 0x72, 0x79, 0x2c, 0x20, 0x65, 0x72, 0x72, 0x20, 0x3a, 0x3d, 0x20, 0x69, 0x6f, 0x75, 0x74, 0x69, 0x6c, 0x2e, 0x54, 0x65, 0x6d, 0x70, 0x44, 0x69, 0x72, 0x28, 0x22, 0x22, 0x2c, 0x20, 0x22, 0x7b, 0x7b, 0x2e, 0x53, 0x63, 0x68, 0x65, 0x6d,
+		0x61, 0x7d, 0x7d, 0x22, 0x29, 0x0a, 0x09, 0x69, 0x66, 0x20, 0x65, 0x72, 0x72, 0x20, 0x21, 0x3d, 0x20, 0x6e, 0x69, 0x6c, 0x20, 0x7b, 0x0a, 0x09, 0x09, 0x66, 0x6d, 0x74, 0x2e, 0x46, 0x70, 0x72, 0x69, 0x6e, 0x74, 0x66, 0x28, 0x6f, 0x73, 0x2e,
+		0x53, 0x74, 0x64, 0x65, 0x72, 0x72, 0x2c, 0x20, 0x22, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x20, 0x65, 0x78, 0x74, 0x72, 0x61, 0x63, 0x74, 0x69, 0x6e, 0x67, 0x20, 0x73, 0x70, 0x65, 0x63, 0x3a, 0x20, 0x25, 0x76, 0x5c, 0x6e, 0x22, 0x2c, 0x20, 0x65,
+		0x72, 0x72, 0x29, 0x0a, 0x09, 0x09, 0x6f, 0x73, 0x2e, 0x45, 0x78, 0x69, 0x74, 0x28, 0x31, 0x29, 0x0a, 0x09, 0x7d, 0x0a, 0x09, 0x66, 0x6f, 0x72, 0x20, 0x70, 0x61, 0x74, 0x68, 0x2c, 0x20, 0x63, 0x6f, 0x6e, 0x74, 0x65, 0x6e, 0x74, 0x20, 0x3a,
+		0x3d, 0x20, 0x72, 0x61, 0x6e, 0x67, 0x65, 0x20, 0x64, 0x6f, 0x63, 0x75, 0x6d, 0x65, 0x6e, 0x74, 0x73, 0x20, 0x7b, 0x0a, 0x09, 0x09, 0x66, 0x69, 0x6c, 0x65, 0x6e, 0x61, 0x6d, 0x65, 0x20, 0x3a, 0x3d, 0x20, 0x66, 0x69, 0x6c, 0x65, 0x70, 0x61,
+		0x74, 0x68, 0x2e, 0x4a, 0x6f, 0x69, 0x6e, 0x28, 0x64, 0x69, 0x72, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x79, 0x2c, 0x20, 0x66, 0x69, 0x6c, 0x65, 0x70, 0x61, 0x74, 0x68, 0x2e, 0x46, 0x72, 0x6f, 0x6d, 0x53, 0x6c, 0x61, 0x73, 0x68, 0x28, 0x70, 0x61,
+		0x74, 0x68, 0x29, 0x29, 0x0a, 0x09, 0x09, 0x65, 0x72, 0x72, 0x20, 0x3d, 0x20, 0x6f, 0x73, 0x2e, 0x4d, 0x6b, 0x64, 0x69, 0x72, 0x41, 0x6c, 0x6c, 0x28, 0x66, 0x69, 0x6c, 0x65, 0x70, 0x61, 0x74, 0x68, 0x2e, 0x44, 0x69, 0x72, 0x28, 0x66, 0x69,
+		0x6c, 0x65, 0x6e, 0x61, 0x6d, 0x65, 0x29, 0x2c, 0x20, 0x30, 0x37, 0x35, 0x35, 0x29, 0x0a, 0x09, 0x09, 0x69, 0x66, 0x20, 0x65, 0x72, 0x72, 0x20, 0x3d, 0x3d, 0x20, 0x6e, 0x69, 0x6c, 0x20, 0x7b, 0x0a, 0x09, 0x09, 0x09, 0x65, 0x72, 0x72, 0x20,
+		0x3d, 0x20, 0x69, 0x6f, 0x75, 0x74, 0x69, 0x6c, 0x2e, 0x57, 0x72, 0x69, 0x74, 0x65, 0x46, 0x69, 0x6c, 0x65, 0x28, 0x66, 0x69, 0x6c, 0x65, 0x6e, 0x61, 0x6d, 0x65, 0x2c, 0x20, 0x5b, 0x5d, 0x62, 0x79, 0x74, 0x65, 0x28, 0x63, 0x6f, 0x6e, 0x74,
+		0x65, 0x6e, 0x74, 0x29, 0x2c, 0x20, 0x30, 0x36, 0x34, 0x34, 0x29, 0x0a, 0x09, 0x09, 0x7d, 0x0a, 0x09, 0x09, 0x69, 0x66, 0x20, 0x65, 0x72, 0x72, 0x20, 0x21, 0x3d, 0x20, 0x6e, 0x69, 0x6c, 0x20, 0x7b, 0x0a, 0x09, 0x09, 0x09, 0x6f, 0x73, 0x2e,
+		0x52, 0x65, 0x6d, 0x6f, 0x76, 0x65, 0x41, 0x6c, 0x6c, 0x28, 0x64, 0x69, 0x72, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x79, 0x29, 0x0a, 0x09, 0x09, 0x09, 0x66, 0x6d, 0x74, 0x2e, 0x46, 0x70, 0x72, 0x69, 0x6e, 0x74, 0x66, 0x28, 0x6f, 0x73, 0x2e, 0x53,
+		0x74, 0x64, 0x65, 0x72, 0x72, 0x2c, 0x20, 0x22, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x20, 0x65, 0x78, 0x74, 0x72, 0x61, 0x63, 0x74, 0x69, 0x6e, 0x67, 0x20, 0x73, 0x70, 0x65, 0x63, 0x3a, 0x20, 0x25, 0x76, 0x5c, 0x6e, 0x22, 0x2c, 0x20, 0x65, 0x72,
+		0x72, 0x29, 0x0a, 0x09, 0x09, 0x09, 0x6f, 0x73, 0x2e, 0x45, 0x78, 0x69, 0x74, 0x28, 0x31, 0x29, 0x0a, 0x09, 0x09, 0x7d, 0x0a, 0x09, 0x7d, 0x0a, 0x09, 0x72, 0x65, 0x74, 0x75, 0x72, 0x6e, 0x20, 0x64, 0x69, 0x72, 0x65, 0x63, 0x74, 0x6f, 0x72,
+		0x79, 0x2c, 0x20, 0x66, 0x69, 0x6c, 0x65, 0x70, 0x61, 0x74, 0x68, 0x2e, 0x4a, 0x6f, 0x69, 0x6e, 0x28, 0x64, 0x69, 0x72, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x79, 0x2c, 0x20, 0x66, 0x69, 0x6c, 0x65, 0x70, 0x61, 0x74, 0x68, 0x2e, 0x46, 0x72, 0x6f,
+		0x6d, 0x53, 0x6c, 0x61, 0x73, 0x68, 0x28, 0x72, 0x6f, 0x6f, 0x74, 0x29, 0x29, 0x0a, 0x7d, 0x0a, 0x0a, 0x2f, 0x2f, 0x20, 0x6c, 0x6f, 0x61, 0x64, 0x20, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x73, 0x20, 0x74, 0x68, 0x65, 0x20, 0x7b, 0x7b, 0x2e, 0x53,
+		0x63, 0x68, 0x65, 0x6d, 0x61, 0x7d, 0x7d, 0x20, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x20, 0x69, 0x6e, 0x2d, 0x70, 0x72, 0x6f, 0x63, 0x65, 0x73, 0x73, 0x20, 0x61, 0x6e, 0x64, 0x20, 0x6c, 0x6f, 0x61, 0x64, 0x73, 0x20, 0x69, 0x74, 0x73,
+		0x20, 0x73, 0x63, 0x68, 0x65, 0x6d, 0x61, 0x2c, 0x20, 0x66, 0x72, 0x6f, 0x6d, 0x20, 0x73, 0x70, 0x65, 0x63, 0x46, 0x69, 0x6c, 0x65, 0x20, 0x61, 0x6e, 0x64, 0x20, 0x74, 0x68, 0x65, 0x20, 0x64, 0x6f, 0x63, 0x75, 0x6d, 0x65, 0x6e, 0x74, 0x73,
+		0x20, 0x69, 0x74, 0x20, 0x72, 0x65, 0x66, 0x65, 0x72, 0x65, 0x6e, 0x63, 0x65, 0x73, 0x0a, 0x66, 0x75, 0x6e, 0x63, 0x20, 0x6c, 0x6f, 0x61, 0x64, 0x28, 0x73, 0x70, 0x65, 0x63, 0x46, 0x69, 0x6c, 0x65, 0x20, 0x73, 0x74, 0x72, 0x69, 0x6e, 0x67,
+		0x29, 0x20, 0x28, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x2c, 0x20, 0x2a, 0x6f, 0x61, 0x73, 0x6d, 0x6f, 0x64, 0x65, 0x6c, 0x2e, 0x53, 0x63, 0x68, 0x65, 0x6d, 0x61, 0x4f, 0x72, 0x52, 0x65, 0x66, 0x2c,
+		0x20, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x29, 0x20, 0x7b, 0x0a, 0x09, 0x6d, 0x74, 0x2c, 0x20, 0x65, 0x72, 0x72, 0x20, 0x3a, 0x3d, 0x20, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x54, 0x79,
+		0x70, 0x65, 0x28, 0x73, 0x70, 0x65, 0x63, 0x46, 0x69, 0x6c, 0x65, 0x2c, 0x20, 0x22, 0x7b, 0x7b, 0x2e, 0x53, 0x63, 0x68, 0x65, 0x6d, 0x61, 0x7d, 0x7d, 0x22, 0x2c, 0x20, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x47, 0x65, 0x6e,
+		0x65, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x4f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x7b, 0x7d, 0x29, 0x0a, 0x09, 0x69, 0x66, 0x20, 0x65, 0x72, 0x72, 0x20, 0x21, 0x3d, 0x20, 0x6e, 0x69, 0x6c, 0x20, 0x7b, 0x0a, 0x09, 0x09, 0x72, 0x65, 0x74,
+		0x75, 0x72, 0x6e, 0x20, 0x6e, 0x69, 0x6c, 0x2c, 0x20, 0x6e, 0x69, 0x6c, 0x2c, 0x20, 0x66, 0x6d, 0x74, 0x2e, 0x45, 0x72, 0x72, 0x6f, 0x72, 0x66, 0x28, 0x22, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x20, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x69, 0x6e, 0x67,
+		0x20, 0x7b, 0x7b, 0x2e, 0x53, 0x63, 0x68, 0x65, 0x6d, 0x61, 0x7d, 0x7d, 0x3a, 0x20, 0x25, 0x76, 0x22, 0x2c, 0x20, 0x65, 0x72, 0x72, 0x29, 0x0a, 0x09, 0x7d, 0x0a, 0x09, 0x6f, 0x61, 0x2c, 0x20, 0x65, 0x72, 0x72, 0x20, 0x3a, 0x3d, 0x20, 0x6f,
+		0x61, 0x73, 0x6d, 0x6f, 0x64, 0x65, 0x6c, 0x2e, 0x4e, 0x65, 0x77, 0x4c, 0x6f, 0x61, 0x64, 0x65, 0x72, 0x28, 0x29, 0x2e, 0x4c, 0x6f, 0x61, 0x64, 0x28, 0x73, 0x70, 0x65, 0x63, 0x46, 0x69, 0x6c, 0x65, 0x29, 0x0a, 0x09, 0x69, 0x66, 0x20, 0x65,
+		0x72, 0x72, 0x20, 0x21, 0x3d, 0x20, 0x6e, 0x69, 0x6c, 0x20, 0x7b, 0x0a, 0x09, 0x09, 0x72, 0x65, 0x74, 0x75, 0x72, 0x6e, 0x20, 0x6e, 0x69, 0x6c, 0x2c, 0x20, 0x6e, 0x69, 0x6c, 0x2c, 0x20, 0x66, 0x6d, 0x74, 0x2e, 0x45, 0x72, 0x72, 0x6f, 0x72,
+		0x66, 0x28, 0x22, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x20, 0x6c, 0x6f, 0x61, 0x64, 0x69, 0x6e, 0x67, 0x20, 0x73, 0x70, 0x65, 0x63, 0x3a, 0x20, 0x25, 0x76, 0x22, 0x2c, 0x20, 0x65, 0x72, 0x72, 0x29, 0x0a, 0x09, 0x7d, 0x0a, 0x09, 0x73, 0x63, 0x68,
+		0x65, 0x6d, 0x61, 0x20, 0x3a, 0x3d, 0x20, 0x6f, 0x61, 0x2e, 0x43, 0x6f, 0x6d, 0x70, 0x6f, 0x6e, 0x65, 0x6e, 0x74, 0x73, 0x2e, 0x53, 0x63, 0x68, 0x65, 0x6d, 0x61, 0x73, 0x5b, 0x22, 0x7b, 0x7b, 0x2e, 0x53, 0x63, 0x68, 0x65, 0x6d, 0x61, 0x7d,
+		0x7d, 0x22, 0x5d, 0x0a, 0x09, 0x69, 0x66, 0x20, 0x73, 0x63, 0x68, 0x65, 0x6d, 0x61, 0x20, 0x3d, 0x3d, 0x20, 0x6e, 0x69, 0x6c, 0x20, 0x7b, 0x0a, 0x09, 0x09, 0x72, 0x65, 0x74, 0x75, 0x72, 0x6e, 0x20, 0x6e, 0x69, 0x6c, 0x2c, 0x20, 0x6e, 0x69,
+		0x6c, 0x2c, 0x20, 0x66, 0x6d, 0x74, 0x2e, 0x45, 0x72, 0x72, 0x6f, 0x72, 0x66, 0x28, 0x22, 0x63, 0x6f, 0x6d, 0x70, 0x6f, 0x6e, 0x65, 0x6e, 0x74, 0x20, 0x7b, 0x7b, 0x2e, 0x53, 0x63, 0x68, 0x65, 0x6d, 0x61, 0x7d, 0x7d, 0x20, 0x64, 0x6f, 0x65,
+		0x73, 0x6e, 0x27, 0x74, 0x20, 0x65, 0x78, 0x69, 0x73, 0x74, 0x73, 0x22, 0x29, 0x0a, 0x09, 0x7d, 0x0a, 0x09, 0x72, 0x65, 0x74, 0x75, 0x72, 0x6e, 0x20, 0x6d, 0x74, 0x2e, 0x4e, 0x65, 0x77, 0x28, 0x29, 0x2e, 0x49, 0x6e, 0x74, 0x65, 0x72, 0x66,
+		0x61, 0x63, 0x65, 0x28, 0x29, 0x2c, 0x20, 0x73, 0x63, 0x68, 0x65, 0x6d, 0x61, 0x2c, 0x20, 0x6e, 0x69, 0x6c, 0x0a, 0x7d, 0x0a, 0x0a, 0x2f, 0x2f, 0x20, 0x76, 0x61, 0x6c, 0x69, 0x64, 0x61, 0x74, 0x65, 0x49, 0x6e, 0x70, 0x75, 0x74, 0x20, 0x63,
+		0x68, 0x65, 0x63, 0x6b, 0x73, 0x20, 0x69, 0x6e, 0x70, 0x75, 0x74, 0x20, 0x61, 0x67, 0x61, 0x69, 0x6e, 0x73, 0x74, 0x20, 0x74, 0x68, 0x65, 0x20, 0x7b, 0x7b, 0x2e, 0x53, 0x63, 0x68, 0x65, 0x6d, 0x61, 0x7d, 0x7d, 0x20, 0x73, 0x63, 0x68, 0x65,
+		0x6d, 0x61, 0x2c, 0x20, 0x72, 0x65, 0x74, 0x75, 0x72, 0x6e, 0x73, 0x20, 0x74, 0x68, 0x65, 0x20, 0x6e, 0x75, 0x6d, 0x62, 0x65, 0x72, 0x20, 0x6f, 0x66, 0x20, 0x76, 0x69, 0x6f, 0x6c, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x0a, 0x66, 0x75, 0x6e,
+		0x63, 0x20, 0x76, 0x61, 0x6c, 0x69, 0x64, 0x61, 0x74, 0x65, 0x49, 0x6e, 0x70, 0x75, 0x74, 0x28, 0x69, 0x6e, 0x70, 0x75, 0x74, 0x20, 0x73, 0x74, 0x72, 0x69, 0x6e, 0x67, 0x2c, 0x20, 0x6f, 0x62, 0x6a, 0x20, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e,
+		0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x2c, 0x20, 0x73, 0x63, 0x68, 0x65, 0x6d, 0x61, 0x20, 0x2a, 0x6f, 0x61, 0x73, 0x6d, 0x6f, 0x64, 0x65, 0x6c, 0x2e, 0x53, 0x63, 0x68, 0x65, 0x6d, 0x61, 0x4f, 0x72, 0x52, 0x65, 0x66, 0x29, 0x20, 0x69,
+		0x6e, 0x74, 0x20, 0x7b, 0x0a, 0x09, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x2c, 0x20, 0x65, 0x72, 0x72, 0x20, 0x3a, 0x3d, 0x20, 0x6f, 0x61, 0x74, 0x6f, 0x6f, 0x6c, 0x2e, 0x4c, 0x6f, 0x61, 0x64, 0x49, 0x6e, 0x73, 0x74, 0x61, 0x6e, 0x63, 0x65, 0x28,
+		0x69, 0x6e, 0x70, 0x75, 0x74, 0x2c, 0x20, 0x6f, 0x62, 0x6a, 0x2c, 0x20, 0x73, 0x63, 0x68, 0x65, 0x6d, 0x61, 0x29, 0x0a, 0x09, 0x69, 0x66, 0x20, 0x65, 0x72, 0x72, 0x20, 0x21, 0x3d, 0x20, 0x6e, 0x69, 0x6c, 0x20, 0x7b, 0x0a, 0x09, 0x09, 0x66,
+		0x6d, 0x74, 0x2e, 0x46, 0x70, 0x72, 0x69, 0x6e, 0x74, 0x66, 0x28, 0x6f, 0x73, 0x2e, 0x53, 0x74, 0x64, 0x65, 0x72, 0x72, 0x2c, 0x20, 0x22, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x20, 0x4c, 0x6f, 0x61, 0x64, 0x69, 0x6e, 0x67, 0x20, 0x66, 0x69, 0x6c,
+		0x65, 0x3a, 0x20, 0x25, 0x76, 0x5c, 0x6e, 0x22, 0x2c, 0x20, 0x65, 0x72, 0x72, 0x29, 0x0a, 0x09, 0x09, 0x6f, 0x73, 0x2e, 0x45, 0x78, 0x69, 0x74, 0x28, 0x31, 0x29, 0x0a, 0x09, 0x7d, 0x0a, 0x09, 0x76, 0x69, 0x6f, 0x6c, 0x61, 0x74, 0x69, 0x6f,
+		0x6e, 0x73, 0x20, 0x3a, 0x3d, 0x20, 0x76, 0x61, 0x6c, 0x69, 0x64, 0x61, 0x74, 0x65, 0x2e, 0x56, 0x61, 0x6c, 0x69, 0x64, 0x61, 0x74, 0x65, 0x28, 0x73, 0x63, 0x68, 0x65, 0x6d, 0x61, 0x2c, 0x20, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x29, 0x0a, 0x09,
+		0x66, 0x6f, 0x72, 0x20, 0x5f, 0x2c, 0x20, 0x76, 0x20, 0x3a, 0x3d, 0x20, 0x72, 0x61, 0x6e, 0x67, 0x65, 0x20, 0x76, 0x69, 0x6f, 0x6c, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x20, 0x7b, 0x0a, 0x09, 0x09, 0x66, 0x6d, 0x74, 0x2e, 0x46, 0x70, 0x72,
+		0x69, 0x6e, 0x74, 0x66, 0x28, 0x6f, 0x73, 0x2e, 0x53, 0x74, 0x64, 0x65, 0x72, 0x72, 0x2c, 0x20, 0x22, 0x25, 0x73, 0x3a, 0x20, 0x25, 0x73, 0x5c, 0x6e, 0x22, 0x2c, 0x20, 0x69, 0x6e, 0x70, 0x75, 0x74, 0x2c, 0x20, 0x76, 0x29, 0x0a, 0x09, 0x7d,
+		0x0a, 0x09, 0x72, 0x65, 0x74, 0x75, 0x72, 0x6e, 0x20, 0x6c, 0x65, 0x6e, 0x28, 0x76, 0x69, 0x6f, 0x6c, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x29, 0x0a, 0x7d, 0x0a, 0x0a, 0x2f, 0x2f, 0x20, 0x6d, 0x61, 0x69, 0x6e, 0x4f, 0x62, 0x6a, 0x54, 0x6f,
+		0x6f, 0x6c, 0x20, 0x67, 0x65, 0x6e, 0x65, 0x72, 0x69, 0x63, 0x20, 0x6d, 0x61, 0x69, 0x6e, 0x20, 0x66, 0x75, 0x6e, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x20, 0x66, 0x6f, 0x72, 0x20, 0x74, 0x6f, 0x6f, 0x6c, 0x20, 0x67, 0x65, 0x6e, 0x65, 0x72, 0x61,
+		0x74, 0x65, 0x64, 0x20, 0x62, 0x79, 0x20, 0x6f, 0x61, 0x74, 0x6f, 0x6f, 0x6c, 0x67, 0x65, 0x6e, 0x0a, 0x66, 0x75, 0x6e, 0x63, 0x20, 0x6d, 0x61, 0x69, 0x6e, 0x4f, 0x62, 0x6a, 0x54, 0x6f, 0x6f, 0x6c, 0x28, 0x29, 0x20, 0x7b, 0x0a, 0x09, 0x76,
 		0x61, 0x72, 0x20, 0x69, 0x6e, 0x70, 0x75, 0x74, 0x20, 0x3d, 0x20, 0x66, 0x6c, 0x61, 0x67, 0x2e, 0x53, 0x74, 0x72, 0x69, 0x6e, 0x67, 0x28, 0x22, 0x69, 0x66, 0x22, 0x2c, 0x20, 0x22, 0x22, 0x2c, 0x20, 0x22, 0x69, 0x6e, 0x70, 0x75, 0x74, 0x20,
 		0x66, 0x69, 0x6c, 0x65, 0x20, 0x2e, 0x6a, 0x73, 0x6f, 0x6e, 0x2f, 0x2e, 0x79, 0x61, 0x6d, 0x6c, 0x2f, 0x2e, 0x62, 0x69, 0x6e, 0x22, 0x29, 0x0a, 0x09, 0x76, 0x61, 0x72, 0x20, 0x6f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x20, 0x3d, 0x20, 0x66, 0x6c,
 		0x61, 0x67, 0x2e, 0x53, 0x74, 0x72, 0x69, 0x6e, 0x67, 0x28, 0x22, 0x6f, 0x66, 0x22, 0x2c, 0x20, 0x22, 0x22, 0x2c, 0x20, 0x22, 0x3c, 0x66, 0x69, 0x6c, 0x65, 0x3e, 0x2e, 0x6a, 0x73, 0x6f, 0x6e, 0x7c, 0x79, 0x61, 0x6d, 0x6c, 0x7c, 0x62, 0x69,
 		0x6e, 0x22, 0x29, 0x0a, 0x09, 0x76, 0x61, 0x72, 0x20, 0x74, 0x6f, 0x66, 0x6f, 0x72, 0x6d, 0x61, 0x74, 0x20, 0x3d, 0x20, 0x66, 0x6c, 0x61, 0x67, 0x2e, 0x53, 0x74, 0x72, 0x69, 0x6e, 0x67, 0x28, 0x22, 0x74, 0x6f, 0x66, 0x6d, 0x74, 0x22, 0x2c,
 		0x20, 0x22, 0x22, 0x2c, 0x20, 0x22, 0x6a, 0x73, 0x6f, 0x6e, 0x7c, 0x79, 0x61, 0x6d, 0x6c, 0x7c, 0x62, 0x69, 0x6e, 0x20, 0x66, 0x6f, 0x72, 0x63, 0x65, 0x20, 0x6f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x20, 0x66, 0x6f, 0x72, 0x6d, 0x61, 0x74, 0x22,
-		0x29, 0x0a, 0x0a, 0x09, 0x76, 0x61, 0x72, 0x20, 0x6f, 0x75, 0x74, 0x20, 0x5b, 0x5d, 0x62, 0x79, 0x74, 0x65, 0x0a, 0x09, 0x76, 0x61, 0x72, 0x20, 0x66, 0x6f, 0x72, 0x6d, 0x61, 0x74, 0x20, 0x73, 0x74, 0x72, 0x69, 0x6e, 0x67, 0x0a, 0x09, 0x76,
-		0x61, 0x72, 0x20, 0x6f, 0x75, 0x74, 0x66, 0x69, 0x6c, 0x65, 0x20, 0x2a, 0x6f, 0x73, 0x2e, 0x46, 0x69, 0x6c, 0x65, 0x0a, 0x09, 0x2f, 0x2f, 0x20, 0x43, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x4c, 0x69, 0x6e, 0x65, 0x20, 0x70, 0x61, 0x72, 0x73,
-		0x69, 0x6e, 0x67, 0x0a, 0x09, 0x66, 0x6c, 0x61, 0x67, 0x2e, 0x50, 0x61, 0x72, 0x73, 0x65, 0x28, 0x29, 0x0a, 0x0a, 0x09, 0x2f, 0x2f, 0x20, 0x54, 0x4f, 0x44, 0x4f, 0x20, 0x3a, 0x20, 0x69, 0x66, 0x20, 0x6e, 0x6f, 0x20, 0x69, 0x6e, 0x70, 0x75,
-		0x74, 0x2c, 0x20, 0x6a, 0x75, 0x73, 0x74, 0x20, 0x6d, 0x61, 0x6b, 0x65, 0x20, 0x61, 0x20, 0x6d, 0x69, 0x6e, 0x69, 0x6d, 0x61, 0x6c, 0x20, 0x64, 0x61, 0x74, 0x61, 0x20, 0x66, 0x69, 0x6c, 0x65, 0x0a, 0x09, 0x65, 0x72, 0x72, 0x20, 0x3a, 0x3d,
-		0x20, 0x65, 0x6e, 0x63, 0x6f, 0x64, 0x69, 0x6e, 0x67, 0x74, 0x6f, 0x6f, 0x6c, 0x73, 0x2e, 0x4c, 0x6f, 0x61, 0x64, 0x28, 0x2a, 0x69, 0x6e, 0x70, 0x75, 0x74, 0x2c, 0x20, 0x6f, 0x62, 0x6a, 0x29, 0x0a, 0x09, 0x69, 0x66, 0x20, 0x65, 0x72, 0x72,
-		0x20, 0x21, 0x3d, 0x20, 0x6e, 0x69, 0x6c, 0x20, 0x7b, 0x0a, 0x09, 0x09, 0x66, 0x6d, 0x74, 0x2e, 0x46, 0x70, 0x72, 0x69, 0x6e, 0x74, 0x66, 0x28, 0x6f, 0x73, 0x2e, 0x53, 0x74, 0x64, 0x65, 0x72, 0x72, 0x2c, 0x20, 0x22, 0x65, 0x72, 0x72, 0x6f,
-		0x72, 0x20, 0x4c, 0x6f, 0x61, 0x64, 0x69, 0x6e, 0x67, 0x20, 0x66, 0x69, 0x6c, 0x65, 0x3a, 0x20, 0x25, 0x76, 0x5c, 0x6e, 0x22, 0x2c, 0x20, 0x65, 0x72, 0x72, 0x29, 0x0a, 0x09, 0x09, 0x6f, 0x73, 0x2e, 0x45, 0x78, 0x69, 0x74, 0x28, 0x31, 0x29,
-		0x0a, 0x09, 0x7d, 0x0a, 0x0a, 0x09, 0x66, 0x6f, 0x72, 0x6d, 0x61, 0x74, 0x20, 0x3d, 0x20, 0x2a, 0x74, 0x6f, 0x66, 0x6f, 0x72, 0x6d, 0x61, 0x74, 0x0a, 0x09, 0x2f, 0x2f, 0x20, 0x6f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x20, 0x66, 0x6f, 0x72, 0x6d,
-		0x61, 0x74, 0x20, 0x64, 0x65, 0x70, 0x65, 0x6e, 0x64, 0x20, 0x6f, 0x6e, 0x20, 0x65, 0x78, 0x74, 0x65, 0x6e, 0x73, 0x69, 0x6f, 0x6e, 0x0a, 0x09, 0x69, 0x66, 0x20, 0x2a, 0x6f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x20, 0x3d, 0x3d, 0x20, 0x22, 0x22,
-		0x20, 0x7b, 0x0a, 0x09, 0x09, 0x2f, 0x2f, 0x20, 0x6f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x20, 0x74, 0x6f, 0x20, 0x73, 0x74, 0x64, 0x6f, 0x75, 0x74, 0x0a, 0x09, 0x09, 0x6f, 0x75, 0x74, 0x66, 0x69, 0x6c, 0x65, 0x20, 0x3d, 0x20, 0x6f, 0x73, 0x2e,
-		0x53, 0x74, 0x64, 0x6f, 0x75, 0x74, 0x0a, 0x09, 0x7d, 0x20, 0x65, 0x6c, 0x73, 0x65, 0x20, 0x7b, 0x0a, 0x09, 0x09, 0x2f, 0x2f, 0x20, 0x69, 0x66, 0x20, 0x6e, 0x6f, 0x20, 0x66, 0x6f, 0x72, 0x6d, 0x61, 0x74, 0x20, 0x63, 0x68, 0x6f, 0x73, 0x65,
-		0x6e, 0x20, 0x3d, 0x3e, 0x20, 0x74, 0x72, 0x79, 0x20, 0x74, 0x6f, 0x20, 0x63, 0x68, 0x6f, 0x6f, 0x73, 0x65, 0x20, 0x6f, 0x6e, 0x65, 0x20, 0x72, 0x65, 0x67, 0x61, 0x72, 0x64, 0x69, 0x6e, 0x67, 0x20, 0x6f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x20,
-		0x65, 0x78, 0x74, 0x65, 0x6e, 0x73, 0x69, 0x6f, 0x6e, 0x0a, 0x09, 0x09, 0x69, 0x66, 0x20, 0x66, 0x6f, 0x72, 0x6d, 0x61, 0x74, 0x20, 0x3d, 0x3d, 0x20, 0x22, 0x22, 0x20, 0x7b, 0x0a, 0x09, 0x09, 0x09, 0x73, 0x6c, 0x20, 0x3a, 0x3d, 0x20, 0x73,
-		0x74, 0x72, 0x69, 0x6e, 0x67, 0x73, 0x2e, 0x53, 0x70, 0x6c, 0x69, 0x74, 0x28, 0x2a, 0x6f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x2c, 0x20, 0x22, 0x2e, 0x22, 0x29, 0x0a, 0x09, 0x09, 0x09, 0x66, 0x6f, 0x72, 0x6d, 0x61, 0x74, 0x20, 0x3d, 0x20, 0x73,
-		0x6c, 0x5b, 0x6c, 0x65, 0x6e, 0x28, 0x73, 0x6c, 0x29, 0x2d, 0x31, 0x5d, 0x0a, 0x09, 0x09, 0x7d, 0x0a, 0x09, 0x09, 0x2f, 0x2f, 0x20, 0x4f, 0x70, 0x65, 0x6e, 0x20, 0x66, 0x69, 0x6c, 0x65, 0x0a, 0x09, 0x09, 0x76, 0x61, 0x72, 0x20, 0x65, 0x72,
-		0x72, 0x20, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x0a, 0x09, 0x09, 0x6f, 0x75, 0x74, 0x66, 0x69, 0x6c, 0x65, 0x2c, 0x20, 0x65, 0x72, 0x72, 0x20, 0x3d, 0x20, 0x6f, 0x73, 0x2e, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x28, 0x2a, 0x6f, 0x75, 0x74, 0x70,
-		0x75, 0x74, 0x29, 0x0a, 0x09, 0x09, 0x69, 0x66, 0x20, 0x65, 0x72, 0x72, 0x20, 0x21, 0x3d, 0x20, 0x6e, 0x69, 0x6c, 0x20, 0x7b, 0x0a, 0x09, 0x09, 0x09, 0x66, 0x6d, 0x74, 0x2e, 0x46, 0x70, 0x72, 0x69, 0x6e, 0x74, 0x66, 0x28, 0x6f, 0x73, 0x2e,
-		0x53, 0x74, 0x64, 0x65, 0x72, 0x72, 0x2c, 0x20, 0x22, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x20, 0x25, 0x76, 0x5c, 0x6e, 0x22, 0x2c, 0x20, 0x65, 0x72, 0x72, 0x29, 0x0a, 0x09, 0x09, 0x7d, 0x0a, 0x09, 0x09, 0x64, 0x65, 0x66, 0x65, 0x72, 0x20, 0x6f,
-		0x75, 0x74, 0x66, 0x69, 0x6c, 0x65, 0x2e, 0x43, 0x6c, 0x6f, 0x73, 0x65, 0x28, 0x29, 0x0a, 0x09, 0x7d, 0x0a, 0x0a, 0x09, 0x2f, 0x2f, 0x20, 0x69, 0x6e, 0x20, 0x63, 0x61, 0x73, 0x20, 0x6f, 0x66, 0x20, 0x62, 0x61, 0x64, 0x20, 0x66, 0x6f, 0x72,
-		0x6d, 0x61, 0x74, 0x20, 0x66, 0x6f, 0x72, 0x63, 0x65, 0x20, 0x74, 0x6f, 0x20, 0x62, 0x69, 0x6e, 0x61, 0x72, 0x79, 0x0a, 0x09, 0x69, 0x66, 0x20, 0x66, 0x6f, 0x72, 0x6d, 0x61, 0x74, 0x20, 0x21, 0x3d, 0x20, 0x22, 0x6a, 0x73, 0x6f, 0x6e, 0x22,
-		0x20, 0x26, 0x26, 0x20, 0x66, 0x6f, 0x72, 0x6d, 0x61, 0x74, 0x20, 0x21, 0x3d, 0x20, 0x22, 0x79, 0x61, 0x6d, 0x6c, 0x22, 0x20, 0x26, 0x26, 0x20, 0x66, 0x6f, 0x72, 0x6d, 0x61, 0x74, 0x20, 0x21, 0x3d, 0x20, 0x22, 0x62, 0x69, 0x6e, 0x22, 0x20,
-		0x7b, 0x0a, 0x09, 0x09, 0x66, 0x6d, 0x74, 0x2e, 0x50, 0x72, 0x69, 0x6e, 0x74, 0x6c, 0x6e, 0x28, 0x22, 0x75, 0x6e, 0x6b, 0x6e, 0x6f, 0x77, 0x6e, 0x20, 0x6f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x20, 0x66, 0x6f, 0x72, 0x6d, 0x61, 0x74, 0x20, 0x63,
-		0x68, 0x6f, 0x6f, 0x73, 0x69, 0x6e, 0x67, 0x20, 0x62, 0x69, 0x6e, 0x61, 0x72, 0x79, 0x22, 0x29, 0x0a, 0x09, 0x09, 0x66, 0x6f, 0x72, 0x6d, 0x61, 0x74, 0x20, 0x3d, 0x20, 0x22, 0x62, 0x69, 0x6e, 0x22, 0x0a, 0x09, 0x7d, 0x0a, 0x0a, 0x09, 0x2f,
-		0x2f, 0x20, 0x63, 0x6f, 0x6e, 0x76, 0x65, 0x72, 0x74, 0x20, 0x6f, 0x62, 0x6a, 0x65, 0x63, 0x74, 0x20, 0x74, 0x6f, 0x20, 0x6f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x20, 0x66, 0x6f, 0x72, 0x6d, 0x61, 0x74, 0x0a, 0x09, 0x6f, 0x75, 0x74, 0x2c, 0x20,
-		0x65, 0x72, 0x72, 0x20, 0x3d, 0x20, 0x65, 0x6e, 0x63, 0x6f, 0x64, 0x69, 0x6e, 0x67, 0x74, 0x6f, 0x6f, 0x6c, 0x73, 0x2e, 0x4f, 0x62, 0x6a, 0x65, 0x74, 0x32, 0x42, 0x79, 0x74, 0x65, 0x73, 0x28, 0x6f, 0x62, 0x6a, 0x2c, 0x20, 0x65, 0x6e, 0x63,
-		0x6f, 0x64, 0x69, 0x6e, 0x67, 0x74, 0x6f, 0x6f, 0x6c, 0x73, 0x2e, 0x45, 0x6e, 0x63, 0x6f, 0x64, 0x69, 0x6e, 0x67, 0x54, 0x79, 0x70, 0x65, 0x46, 0x72, 0x6f, 0x6d, 0x53, 0x74, 0x72, 0x69, 0x6e, 0x67, 0x28, 0x66, 0x6f, 0x72, 0x6d, 0x61, 0x74,
-		0x29, 0x29, 0x0a, 0x0a, 0x09, 0x69, 0x66, 0x20, 0x65, 0x72, 0x72, 0x20, 0x21, 0x3d, 0x20, 0x6e, 0x69, 0x6c, 0x20, 0x7b, 0x0a, 0x09, 0x09, 0x66, 0x6d, 0x74, 0x2e, 0x46, 0x70, 0x72, 0x69, 0x6e, 0x74, 0x66, 0x28, 0x6f, 0x73, 0x2e, 0x53, 0x74,
-		0x64, 0x65, 0x72, 0x72, 0x2c, 0x20, 0x22, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x20, 0x4d, 0x61, 0x72, 0x73, 0x68, 0x61, 0x6c, 0x6c, 0x69, 0x6e, 0x67, 0x3a, 0x20, 0x25, 0x76, 0x5c, 0x6e, 0x22, 0x2c, 0x20, 0x65, 0x72, 0x72, 0x29, 0x0a, 0x09, 0x09,
-		0x6f, 0x73, 0x2e, 0x45, 0x78, 0x69, 0x74, 0x28, 0x31, 0x29, 0x0a, 0x09, 0x7d, 0x0a, 0x0a, 0x09, 0x5f, 0x2c, 0x20, 0x65, 0x72, 0x72, 0x20, 0x3d, 0x20, 0x6f, 0x75, 0x74, 0x66, 0x69, 0x6c, 0x65, 0x2e, 0x57, 0x72, 0x69, 0x74, 0x65, 0x28, 0x6f,
-		0x75, 0x74, 0x29, 0x0a, 0x09, 0x69, 0x66, 0x20, 0x65, 0x72, 0x72, 0x20, 0x21, 0x3d, 0x20, 0x6e, 0x69, 0x6c, 0x20, 0x7b, 0x0a, 0x09, 0x09, 0x66, 0x6d, 0x74, 0x2e, 0x46, 0x70, 0x72, 0x69, 0x6e, 0x74, 0x66, 0x28, 0x6f, 0x73, 0x2e, 0x53, 0x74,
-		0x64, 0x65, 0x72, 0x72, 0x2c, 0x20, 0x22, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x20, 0x73, 0x61, 0x76, 0x69, 0x6e, 0x67, 0x20, 0x66, 0x69, 0x6c, 0x65, 0x3a, 0x20, 0x25, 0x76, 0x5c, 0x6e, 0x22, 0x2c, 0x20, 0x65, 0x72, 0x72, 0x29, 0x0a, 0x09, 0x09,
-		0x6f, 0x73, 0x2e, 0x45, 0x78, 0x69, 0x74, 0x28, 0x31, 0x29, 0x0a, 0x09, 0x7d, 0x0a, 0x7d, 0x0a, 0x0a, 0x66, 0x75, 0x6e, 0x63, 0x20, 0x6d, 0x61, 0x69, 0x6e, 0x28, 0x29, 0x20, 0x7b, 0x0a, 0x09, 0x76, 0x61, 0x72, 0x20, 0x6f, 0x62, 0x6a, 0x20,
-		0x7b, 0x7b, 0x2e, 0x43, 0x6f, 0x6d, 0x70, 0x6f, 0x6e, 0x65, 0x6e, 0x74, 0x7d, 0x7d, 0x0a, 0x09, 0x6d, 0x61, 0x69, 0x6e, 0x4f, 0x62, 0x6a, 0x54, 0x6f, 0x6f, 0x6c, 0x28, 0x26, 0x6f, 0x62, 0x6a, 0x29, 0x0a, 0x7d}
+		0x29, 0x0a, 0x09, 0x76, 0x61, 0x72, 0x20, 0x76, 0x61, 0x6c, 0x69, 0x64, 0x61, 0x74, 0x65, 0x4f, 0x6e, 0x6c, 0x79, 0x20, 0x3d, 0x20, 0x66, 0x6c, 0x61, 0x67, 0x2e, 0x42, 0x6f, 0x6f, 0x6c, 0x28, 0x22, 0x76, 0x61, 0x6c, 0x69, 0x64, 0x61, 0x74,
+		0x65, 0x22, 0x2c, 0x20, 0x66, 0x61, 0x6c, 0x73, 0x65, 0x2c, 0x20, 0x22, 0x76, 0x61, 0x6c, 0x69, 0x64, 0x61, 0x74, 0x65, 0x20, 0x69, 0x6e, 0x70, 0x75, 0x74, 0x20, 0x61, 0x67, 0x61, 0x69, 0x6e, 0x73, 0x74, 0x20, 0x74, 0x68, 0x65, 0x20, 0x73,
+		0x63, 0x68, 0x65, 0x6d, 0x61, 0x20, 0x61, 0x6e, 0x64, 0x20, 0x65, 0x78, 0x69, 0x74, 0x22, 0x29, 0x0a, 0x09, 0x76, 0x61, 0x72, 0x20, 0x73, 0x70, 0x65, 0x63, 0x46, 0x69, 0x6c, 0x65, 0x20, 0x3d, 0x20, 0x66, 0x6c, 0x61, 0x67, 0x2e, 0x53, 0x74,
+		0x72, 0x69, 0x6e, 0x67, 0x28, 0x22, 0x73, 0x70, 0x65, 0x63, 0x22, 0x2c, 0x20, 0x22, 0x22, 0x2c, 0x20, 0x22, 0x73, 0x70, 0x65, 0x63, 0x20, 0x66, 0x69, 0x6c, 0x65, 0x20, 0x74, 0x6f, 0x20, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x20, 0x74, 0x68, 0x65,
+		0x20, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x20, 0x61, 0x6e, 0x64, 0x20, 0x76, 0x61, 0x6c, 0x69, 0x64, 0x61, 0x74, 0x65, 0x20, 0x77, 0x69, 0x74, 0x68, 0x2c, 0x20, 0x69, 0x6e, 0x73, 0x74, 0x65, 0x61, 0x64, 0x20, 0x6f, 0x66, 0x20, 0x74,
+		0x68, 0x65, 0x20, 0x65, 0x6d, 0x62, 0x65, 0x64, 0x64, 0x65, 0x64, 0x20, 0x6f, 0x6e, 0x65, 0x22, 0x29, 0x0a, 0x09, 0x76, 0x61, 0x72, 0x20, 0x76, 0x65, 0x72, 0x62, 0x6f, 0x73, 0x65, 0x20, 0x3d, 0x20, 0x66, 0x6c, 0x61, 0x67, 0x2e, 0x42, 0x6f,
+		0x6f, 0x6c, 0x28, 0x22, 0x76, 0x65, 0x72, 0x62, 0x6f, 0x73, 0x65, 0x22, 0x2c, 0x20, 0x66, 0x61, 0x6c, 0x73, 0x65, 0x2c, 0x20, 0x22, 0x73, 0x68, 0x6f, 0x77, 0x20, 0x6c, 0x6f, 0x67, 0x22, 0x29, 0x0a, 0x0a, 0x09, 0x2f, 0x2f, 0x20, 0x43, 0x6f,
+		0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x4c, 0x69, 0x6e, 0x65, 0x20, 0x70, 0x61, 0x72, 0x73, 0x69, 0x6e, 0x67, 0x0a, 0x09, 0x66, 0x6c, 0x61, 0x67, 0x2e, 0x50, 0x61, 0x72, 0x73, 0x65, 0x28, 0x29, 0x0a, 0x09, 0x69, 0x66, 0x20, 0x21, 0x2a, 0x76, 0x65,
+		0x72, 0x62, 0x6f, 0x73, 0x65, 0x20, 0x7b, 0x0a, 0x09, 0x09, 0x6c, 0x6f, 0x67, 0x2e, 0x53, 0x65, 0x74, 0x46, 0x6c, 0x61, 0x67, 0x73, 0x28, 0x30, 0x29, 0x0a, 0x09, 0x09, 0x6c, 0x6f, 0x67, 0x2e, 0x53, 0x65, 0x74, 0x4f, 0x75, 0x74, 0x70, 0x75,
+		0x74, 0x28, 0x69, 0x6f, 0x75, 0x74, 0x69, 0x6c, 0x2e, 0x44, 0x69, 0x73, 0x63, 0x61, 0x72, 0x64, 0x29, 0x0a, 0x09, 0x7d, 0x0a, 0x09, 0x66, 0x69, 0x6c, 0x65, 0x20, 0x3a, 0x3d, 0x20, 0x2a, 0x73, 0x70, 0x65, 0x63, 0x46, 0x69, 0x6c, 0x65, 0x0a,
+		0x09, 0x76, 0x61, 0x72, 0x20, 0x64, 0x69, 0x72, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x79, 0x20, 0x73, 0x74, 0x72, 0x69, 0x6e, 0x67, 0x0a, 0x09, 0x69, 0x66, 0x20, 0x66, 0x69, 0x6c, 0x65, 0x20, 0x3d, 0x3d, 0x20, 0x22, 0x22, 0x20, 0x7b, 0x0a, 0x09,
+		0x09, 0x64, 0x69, 0x72, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x79, 0x2c, 0x20, 0x66, 0x69, 0x6c, 0x65, 0x20, 0x3d, 0x20, 0x65, 0x78, 0x74, 0x72, 0x61, 0x63, 0x74, 0x53, 0x70, 0x65, 0x63, 0x28, 0x29, 0x0a, 0x09, 0x7d, 0x0a, 0x09, 0x6f, 0x62, 0x6a,
+		0x2c, 0x20, 0x73, 0x63, 0x68, 0x65, 0x6d, 0x61, 0x2c, 0x20, 0x65, 0x72, 0x72, 0x20, 0x3a, 0x3d, 0x20, 0x6c, 0x6f, 0x61, 0x64, 0x28, 0x66, 0x69, 0x6c, 0x65, 0x29, 0x0a, 0x09, 0x2f, 0x2f, 0x20, 0x74, 0x68, 0x65, 0x20, 0x73, 0x70, 0x65, 0x63,
+		0x20, 0x69, 0x73, 0x20, 0x6c, 0x6f, 0x61, 0x64, 0x65, 0x64, 0x2c, 0x20, 0x65, 0x78, 0x74, 0x72, 0x61, 0x63, 0x74, 0x65, 0x64, 0x20, 0x64, 0x6f, 0x63, 0x75, 0x6d, 0x65, 0x6e, 0x74, 0x73, 0x20, 0x61, 0x72, 0x65, 0x6e, 0x27, 0x74, 0x20, 0x6e,
+		0x65, 0x65, 0x64, 0x65, 0x64, 0x20, 0x61, 0x6e, 0x79, 0x6d, 0x6f, 0x72, 0x65, 0x0a, 0x09, 0x69, 0x66, 0x20, 0x64, 0x69, 0x72, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x79, 0x20, 0x21, 0x3d, 0x20, 0x22, 0x22, 0x20, 0x7b, 0x0a, 0x09, 0x09, 0x6f, 0x73,
+		0x2e, 0x52, 0x65, 0x6d, 0x6f, 0x76, 0x65, 0x41, 0x6c, 0x6c, 0x28, 0x64, 0x69, 0x72, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x79, 0x29, 0x0a, 0x09, 0x7d, 0x0a, 0x09, 0x69, 0x66, 0x20, 0x65, 0x72, 0x72, 0x20, 0x21, 0x3d, 0x20, 0x6e, 0x69, 0x6c, 0x20,
+		0x7b, 0x0a, 0x09, 0x09, 0x66, 0x6d, 0x74, 0x2e, 0x46, 0x70, 0x72, 0x69, 0x6e, 0x74, 0x66, 0x28, 0x6f, 0x73, 0x2e, 0x53, 0x74, 0x64, 0x65, 0x72, 0x72, 0x2c, 0x20, 0x22, 0x25, 0x76, 0x5c, 0x6e, 0x22, 0x2c, 0x20, 0x65, 0x72, 0x72, 0x29, 0x0a,
+		0x09, 0x09, 0x6f, 0x73, 0x2e, 0x45, 0x78, 0x69, 0x74, 0x28, 0x31, 0x29, 0x0a, 0x09, 0x7d, 0x0a, 0x0a, 0x09, 0x69, 0x66, 0x20, 0x2a, 0x76, 0x61, 0x6c, 0x69, 0x64, 0x61, 0x74, 0x65, 0x4f, 0x6e, 0x6c, 0x79, 0x20, 0x7b, 0x0a, 0x09, 0x09, 0x69,
+		0x66, 0x20, 0x76, 0x61, 0x6c, 0x69, 0x64, 0x61, 0x74, 0x65, 0x49, 0x6e, 0x70, 0x75, 0x74, 0x28, 0x2a, 0x69, 0x6e, 0x70, 0x75, 0x74, 0x2c, 0x20, 0x6f, 0x62, 0x6a, 0x2c, 0x20, 0x73, 0x63, 0x68, 0x65, 0x6d, 0x61, 0x29, 0x20, 0x3e, 0x20, 0x30,
+		0x20, 0x7b, 0x0a, 0x09, 0x09, 0x09, 0x6f, 0x73, 0x2e, 0x45, 0x78, 0x69, 0x74, 0x28, 0x31, 0x29, 0x0a, 0x09, 0x09, 0x7d, 0x0a, 0x09, 0x09, 0x6f, 0x73, 0x2e, 0x45, 0x78, 0x69, 0x74, 0x28, 0x30, 0x29, 0x0a, 0x09, 0x7d, 0x0a, 0x0a, 0x09, 0x65,
+		0x72, 0x72, 0x20, 0x3d, 0x20, 0x6f, 0x61, 0x74, 0x6f, 0x6f, 0x6c, 0x2e, 0x43, 0x6f, 0x6e, 0x76, 0x65, 0x72, 0x74, 0x28, 0x2a, 0x69, 0x6e, 0x70, 0x75, 0x74, 0x2c, 0x20, 0x2a, 0x6f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x2c, 0x20, 0x2a, 0x74, 0x6f,
+		0x66, 0x6f, 0x72, 0x6d, 0x61, 0x74, 0x2c, 0x20, 0x6f, 0x62, 0x6a, 0x29, 0x0a, 0x09, 0x69, 0x66, 0x20, 0x65, 0x72, 0x72, 0x20, 0x21, 0x3d, 0x20, 0x6e, 0x69, 0x6c, 0x20, 0x7b, 0x0a, 0x09, 0x09, 0x66, 0x6d, 0x74, 0x2e, 0x46, 0x70, 0x72, 0x69,
+		0x6e, 0x74, 0x66, 0x28, 0x6f, 0x73, 0x2e, 0x53, 0x74, 0x64, 0x65, 0x72, 0x72, 0x2c, 0x20, 0x22, 0x25, 0x76, 0x5c, 0x6e, 0x22, 0x2c, 0x20, 0x65, 0x72, 0x72, 0x29, 0x0a, 0x09, 0x09, 0x6f, 0x73, 0x2e, 0x45, 0x78, 0x69, 0x74, 0x28, 0x31, 0x29,
+		0x0a, 0x09, 0x7d, 0x0a, 0x7d, 0x0a, 0x0a, 0x66, 0x75, 0x6e, 0x63, 0x20, 0x6d, 0x61, 0x69, 0x6e, 0x28, 0x29, 0x20, 0x7b, 0x0a, 0x09, 0x6d, 0x61, 0x69, 0x6e, 0x4f, 0x62, 0x6a, 0x54, 0x6f, 0x6f, 0x6c, 0x28, 0x29, 0x0a, 0x7d}
 }
@@ -0,0 +1,105 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+
+	"github.com/Axili39/oastools/oasmodel"
+	"github.com/Axili39/oastools/protobuf"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+// Multiples file in command lines
+type stringList []string
+
+func (i *stringList) String() string {
+	return ""
+}
+
+func (i *stringList) Set(value string) error {
+	*i = append(*i, value)
+	return nil
+}
+
+func main() {
+	descriptorSet := flag.String("descriptor-set", "", "read a FileDescriptorSet, as written by protoc --descriptor_set_out, instead of .proto files")
+	out := flag.String("o", "", "output file")
+	format := flag.String("format", "yaml", "yaml or json")
+	verbose := flag.Bool("verbose", false, "show log")
+	var importPaths stringList
+	flag.Var(&importPaths, "I", "import path of .proto files (multi), default is the current directory")
+	flag.Usage = func() {
+		fmt.Fprintf(os.Stderr, "usage: %s [options] file.proto...\n", os.Args[0])
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	if !*verbose {
+		log.SetFlags(0)
+		log.SetOutput(ioutil.Discard)
+	}
+
+	outputFormat, err := oasmodel.ParseFormat(*format)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		os.Exit(1)
+	}
+
+	var files []*descriptorpb.FileDescriptorProto
+	var roots []string
+	if *descriptorSet != "" {
+		files, err = loadDescriptorSet(*descriptorSet)
+	} else if flag.NArg() > 0 {
+		files, err = protobuf.ParseProtoFiles(flag.Args(), importPaths)
+		// files given come first, before their imports
+		for i := 0; i < flag.NArg() && i < len(files); i++ {
+			roots = append(roots, files[i].GetName())
+		}
+	} else {
+		flag.Usage()
+		os.Exit(1)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		os.Exit(1)
+	}
+
+	oa, err := protobuf.Proto2OpenAPI(files, roots)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error converting : %v\n", err)
+		os.Exit(1)
+	}
+
+	output := os.Stdout
+	if *out != "" {
+		output, err = os.Create(*out)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "error opening %s : %v\n", *out, err)
+			os.Exit(1)
+		}
+		defer output.Close()
+	}
+	err = oa.Write(output, outputFormat)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error writing : %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// loadDescriptorSet reads files of a FileDescriptorSet
+func loadDescriptorSet(filename string) ([]*descriptorpb.FileDescriptorProto, error) {
+	data, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	set := &descriptorpb.FileDescriptorSet{}
+	err = proto.Unmarshal(data, set)
+	if err != nil {
+		return nil, fmt.Errorf("bad descriptor set %s : %v", filename, err)
+	}
+	return set.File, nil
+}
@@ -0,0 +1,129 @@
+package oasmodel
+
+import (
+	"regexp"
+	"strconv"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ParseError reports a document which can't be decoded into the model
+type ParseError struct {
+	File    string // empty when read from a buffer or a reader
+	Line    int
+	Column  int
+	Pointer string // JSON Pointer of the faulty element, when known
+	Msg     string
+}
+
+func (e *ParseError) Error() string {
+	msg := position(e.File, e.Line, e.Column) + e.Msg
+	if e.Pointer != "" {
+		msg += " (at " + e.Pointer + ")"
+	}
+	return msg
+}
+
+// UnresolvedRefError reports a $ref whose target can't be found
+type UnresolvedRefError struct {
+	File    string
+	Line    int
+	Column  int
+	Pointer string // JSON Pointer of the $ref location
+	Ref     string
+	Err     error
+}
+
+func (e *UnresolvedRefError) Error() string {
+	msg := position(e.File, e.Line, e.Column) + "unresolved reference " + e.Ref
+	if e.Pointer != "" {
+		msg += " (at " + e.Pointer + ")"
+	}
+	if e.Err != nil {
+		msg += ": " + e.Err.Error()
+	}
+	return msg
+}
+
+// Unwrap gives access to the resolution error
+func (e *UnresolvedRefError) Unwrap() error {
+	return e.Err
+}
+
+// position formats file:line:column: prefix
+func position(file string, line, column int) string {
+	pos := file
+	if line > 0 {
+		pos += ":" + strconv.Itoa(line)
+		if column > 0 {
+			pos += ":" + strconv.Itoa(column)
+		}
+	}
+	if pos == "" {
+		return ""
+	}
+	return pos + ": "
+}
+
+var yamlLineRe = regexp.MustCompile(`^(?:yaml: )?line (\d+): (.*)$`)
+
+// newParseError converts a yaml error into a *ParseError, root is used to locate the faulty element
+func newParseError(file string, root *yaml.Node, err error) *ParseError {
+	msg := err.Error()
+	// type errors hold one message per faulty element, the first one is reported
+	if terr, ok := err.(*yaml.TypeError); ok && len(terr.Errors) > 0 {
+		msg = terr.Errors[0]
+	}
+	perr := &ParseError{File: file, Msg: msg}
+	match := yamlLineRe.FindStringSubmatch(msg)
+	if match == nil {
+		return perr
+	}
+	perr.Line, _ = strconv.Atoi(match[1])
+	perr.Msg = match[2]
+	if root != nil {
+		perr.Pointer, perr.Column = locate(root, perr.Line, "#")
+	}
+	return perr
+}
+
+// locate returns the JSON Pointer and column of the first node found at line
+func locate(node *yaml.Node, line int, pointer string) (string, int) {
+	switch node.Kind {
+	case yaml.DocumentNode:
+		for _, n := range node.Content {
+			if p, c := locate(n, line, pointer); c > 0 {
+				return p, c
+			}
+		}
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key, value := node.Content[i], node.Content[i+1]
+			child := pointer + "/" + EscapeToken(key.Value)
+			if value.Line == line && value.Kind == yaml.ScalarNode {
+				return child, value.Column
+			}
+			if key.Line == line {
+				return child, key.Column
+			}
+			if p, c := locate(value, line, child); c > 0 {
+				return p, c
+			}
+		}
+	case yaml.SequenceNode:
+		for i, n := range node.Content {
+			if p, c := locate(n, line, pointer+"/"+strconv.Itoa(i)); c > 0 {
+				return p, c
+			}
+		}
+	}
+	if node.Line == line {
+		return pointer, node.Column
+	}
+	return "", 0
+}
+
+// refError builds an *UnresolvedRefError for ref located at pointer in file
+func refError(file string, ref *Ref, pointer string, err error) *UnresolvedRefError {
+	return &UnresolvedRefError{File: file, Line: ref.Line, Column: ref.Column, Pointer: pointer, Ref: ref.Ref, Err: err}
+}
@@ -0,0 +1,79 @@
+package oasmodel
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParseError(t *testing.T) {
+	data := `openapi: 3.0.0
+info:
+    title: bad type
+    version: 1.0.0
+paths: {}
+components:
+    schemas:
+        foo:
+            type: object
+            required: notalist
+`
+	var oa OpenAPI
+	_, err := oa.UnMarshal([]byte(data))
+	var perr *ParseError
+	if !errors.As(err, &perr) {
+		t.Fatalf("expected *ParseError, got %v", err)
+	}
+	if perr.Line != 10 || perr.Column != 23 || perr.Pointer != "#/components/schemas/foo/required" {
+		t.Errorf("bad error location : %d:%d %s", perr.Line, perr.Column, perr.Pointer)
+	}
+
+	err = oa.Read(errReader{})
+	if err == nil {
+		t.Errorf("reader error must be returned")
+	}
+
+	_, err = oa.UnMarshal([]byte("openapi: [3.0.0\n"))
+	if !errors.As(err, &perr) || perr.Line == 0 {
+		t.Errorf("expected syntax *ParseError with line, got %v", err)
+	}
+}
+
+func TestUnresolvedRefError(t *testing.T) {
+	data := `openapi: 3.0.0
+info:
+    title: unresolved
+    version: 1.0.0
+paths: {}
+components:
+    schemas:
+        foo:
+            type: object
+            properties:
+                bar:
+                    $ref: '#/components/schemas/bar'
+`
+	var oa OpenAPI
+	_, err := oa.UnMarshal([]byte(data))
+	if err != nil {
+		t.Fatalf("error unmarshalling : %v", err)
+	}
+	err = oa.ResolveRefs()
+	var rerr *UnresolvedRefError
+	if !errors.As(err, &rerr) {
+		t.Fatalf("expected *UnresolvedRefError, got %v", err)
+	}
+	if rerr.Line != 12 || rerr.Column != 21 || rerr.Pointer != "#/components/schemas/foo/properties/bar" || rerr.Ref != "#/components/schemas/bar" {
+		t.Errorf("bad error : %+v", rerr)
+	}
+
+	_, err = oa.ResolveRefsWithFilter([]string{"foo"})
+	if !errors.As(err, &rerr) {
+		t.Errorf("expected *UnresolvedRefError from filter, got %v", err)
+	}
+}
+
+type errReader struct{}
+
+func (errReader) Read([]byte) (int, error) {
+	return 0, errors.New("read error")
+}
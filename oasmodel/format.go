@@ -0,0 +1,169 @@
+package oasmodel
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Format of a serialized document
+type Format string
+
+// Supported formats
+const (
+	FormatYAML Format = "yaml"
+	FormatJSON Format = "json"
+)
+
+// ParseFormat converts a format name (yaml, yml or json) into a Format
+func ParseFormat(name string) (Format, error) {
+	switch strings.ToLower(name) {
+	case "yaml", "yml":
+		return FormatYAML, nil
+	case "json":
+		return FormatJSON, nil
+	}
+	return "", fmt.Errorf("unknown format %q", name)
+}
+
+// FormatOf returns the format of a file from its extension, YAML by default
+func FormatOf(filename string) Format {
+	if strings.EqualFold(filepath.Ext(filename), ".json") {
+		return FormatJSON
+	}
+	return FormatYAML
+}
+
+// Marshal serializes the specification, JSON keeps the order of the YAML output
+func (oa *OpenAPI) Marshal(format Format) ([]byte, error) {
+	switch format {
+	case FormatYAML:
+		return yaml.Marshal(oa)
+	case FormatJSON:
+		var root yaml.Node
+		err := root.Encode(oa)
+		if err != nil {
+			return nil, err
+		}
+		var buf bytes.Buffer
+		err = writeJSON(&buf, &root)
+		if err != nil {
+			return nil, err
+		}
+		var out bytes.Buffer
+		err = json.Indent(&out, buf.Bytes(), "", "  ")
+		if err != nil {
+			return nil, err
+		}
+		out.WriteByte('\n')
+		return out.Bytes(), nil
+	}
+	return nil, fmt.Errorf("unknown format %q", format)
+}
+
+// Write writes the specification to w
+func (oa *OpenAPI) Write(w io.Writer, format Format) error {
+	buf, err := oa.Marshal(format)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(buf)
+	return err
+}
+
+// writeJSON writes a YAML node as compact JSON, mapping keys are kept in order
+func writeJSON(buf *bytes.Buffer, node *yaml.Node) error {
+	switch node.Kind {
+	case yaml.DocumentNode:
+		if len(node.Content) == 0 {
+			buf.WriteString("null")
+			return nil
+		}
+		return writeJSON(buf, node.Content[0])
+	case yaml.AliasNode:
+		return writeJSON(buf, node.Alias)
+	case yaml.MappingNode:
+		buf.WriteByte('{')
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			writeJSONString(buf, node.Content[i].Value)
+			buf.WriteByte(':')
+			err := writeJSON(buf, node.Content[i+1])
+			if err != nil {
+				return err
+			}
+		}
+		buf.WriteByte('}')
+	case yaml.SequenceNode:
+		buf.WriteByte('[')
+		for i, n := range node.Content {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			err := writeJSON(buf, n)
+			if err != nil {
+				return err
+			}
+		}
+		buf.WriteByte(']')
+	case yaml.ScalarNode:
+		return writeJSONScalar(buf, node)
+	default:
+		return fmt.Errorf("line %d: unexpected yaml node", node.Line)
+	}
+	return nil
+}
+
+func writeJSONScalar(buf *bytes.Buffer, node *yaml.Node) error {
+	switch node.ShortTag() {
+	case "!!null":
+		buf.WriteString("null")
+	case "!!bool":
+		var b bool
+		err := node.Decode(&b)
+		if err != nil {
+			return err
+		}
+		buf.WriteString(strconv.FormatBool(b))
+	case "!!int", "!!float":
+		var f float64
+		err := node.Decode(&f)
+		if err != nil {
+			return err
+		}
+		if math.IsInf(f, 0) || math.IsNaN(f) {
+			// not representable in JSON
+			writeJSONString(buf, node.Value)
+			return nil
+		}
+		if node.ShortTag() == "!!int" {
+			var i int64
+			if node.Decode(&i) == nil {
+				buf.WriteString(strconv.FormatInt(i, 10))
+				return nil
+			}
+		}
+		buf.WriteString(strconv.FormatFloat(f, 'g', -1, 64))
+	default:
+		writeJSONString(buf, node.Value)
+	}
+	return nil
+}
+
+// writeJSONString writes a quoted string, without escaping HTML characters of descriptions
+func writeJSONString(buf *bytes.Buffer, s string) {
+	enc := json.NewEncoder(buf)
+	enc.SetEscapeHTML(false)
+	_ = enc.Encode(s)
+	// Encode terminates the value with a newline
+	buf.Truncate(buf.Len() - 1)
+}
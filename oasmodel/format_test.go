@@ -0,0 +1,88 @@
+package oasmodel
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"strings"
+	"testing"
+)
+
+func TestJSONRoundTrip(t *testing.T) {
+	for _, asset := range []string{"assets/lux-openapi.yaml", "assets/oas31-jsonschema.yaml"} {
+		data, err := ioutil.ReadFile(asset)
+		if err != nil {
+			t.Fatalf("error loading %s : %v", asset, err)
+		}
+		var oa OpenAPI
+		if _, err := oa.UnMarshal(data); err != nil {
+			t.Fatalf("error unmarshalling %s : %v", asset, err)
+		}
+		buf, err := oa.Marshal(FormatJSON)
+		if err != nil {
+			t.Fatalf("error marshalling %s to json : %v", asset, err)
+		}
+		if !json.Valid(buf) {
+			t.Fatalf("%s : invalid json output", asset)
+		}
+
+		var fromJSON OpenAPI
+		if _, err := fromJSON.UnMarshal(buf); err != nil {
+			t.Fatalf("error unmarshalling json of %s : %v", asset, err)
+		}
+		yml, err := fromJSON.Marshal(FormatYAML)
+		if err != nil {
+			t.Fatalf("error marshalling %s to yaml : %v", asset, err)
+		}
+		if string(yml) != string(data) {
+			t.Errorf("%s : YAML -> JSON -> YAML differs :\n%s", asset, yml)
+		}
+	}
+}
+
+func TestJSONKeyOrder(t *testing.T) {
+	var oa OpenAPI
+	_, err := oa.UnMarshal([]byte(`{"openapi": "3.0.0", "info": {"title": "a <b>", "version": "1"}, "paths": {},
+		"components": {"schemas": {"x": {"type": "object", "x-properties-order": ["z", "a"],
+		"properties": {"z": {"type": "integer", "maximum": 10}, "a": {"type": "boolean", "default": "true"}}}}}}`))
+	if err != nil {
+		t.Fatalf("error unmarshalling json : %v", err)
+	}
+	buf, err := oa.Marshal(FormatJSON)
+	if err != nil {
+		t.Fatalf("error marshalling : %v", err)
+	}
+	out := string(buf)
+	if strings.Index(out, `"openapi"`) > strings.Index(out, `"info"`) || strings.Index(out, `"info"`) > strings.Index(out, `"paths"`) {
+		t.Errorf("keys must follow the model order :\n%s", out)
+	}
+	if !strings.Contains(out, `"title": "a <b>"`) || !strings.Contains(out, `"maximum": 10`) {
+		t.Errorf("bad scalars :\n%s", out)
+	}
+	if FormatOf("spec.JSON") != FormatJSON || FormatOf("spec.yml") != FormatYAML {
+		t.Errorf("bad format detection")
+	}
+	if _, err := ParseFormat("xml"); err == nil {
+		t.Errorf("unknown format must be rejected")
+	}
+}
+
+func TestEnumValues(t *testing.T) {
+	var oa OpenAPI
+	_, err := oa.UnMarshal([]byte(`{"openapi": "3.0.0", "info": {"title": "enums", "version": "1"}, "paths": {},
+		"components": {"schemas": {"level": {"type": "integer", "enum": [0, 5]}, "ratio": {"type": "number", "enum": [0.5]},
+		"code": {"type": "string", "enum": ["1"]}}}}`))
+	if err != nil {
+		t.Fatalf("error unmarshalling json : %v", err)
+	}
+	buf, err := oa.Marshal(FormatJSON)
+	if err != nil {
+		t.Fatalf("error marshalling : %v", err)
+	}
+	// enum values are numbers for numeric types only
+	out := strings.Join(strings.Fields(string(buf)), "")
+	for _, expected := range []string{`"enum":[0,5]`, `"enum":[0.5]`, `"enum":["1"]`} {
+		if !strings.Contains(out, expected) {
+			t.Errorf("expected %s in :\n%s", expected, buf)
+		}
+	}
+}
@@ -0,0 +1,142 @@
+package oasmodel
+
+import (
+	"fmt"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+/*
+SchemaType holds the type keyword of a Schema :
+  - OAS 3.0 : a single type, "null" is expressed with nullable
+  - OAS 3.1 : a single type or an array of types, eg: [string, "null"]
+*/
+type SchemaType []string
+
+// UnmarshalYAML Implements the Unmarshaler interface of the yaml pkg.
+func (t *SchemaType) UnmarshalYAML(value *yaml.Node) error {
+	if value.Kind == yaml.ScalarNode {
+		*t = SchemaType{value.Value}
+		return nil
+	}
+	var types []string
+	err := value.Decode(&types)
+	if err != nil {
+		return err
+	}
+	*t = types
+	return nil
+}
+
+// MarshalYAML Implements the Marshaler interface of the yaml pkg.
+func (t SchemaType) MarshalYAML() (interface{}, error) {
+	if len(t) == 1 {
+		return t[0], nil
+	}
+	return []string(t), nil
+}
+
+// Has returns true if name is one of the types
+func (t SchemaType) Has(name string) bool {
+	for _, v := range t {
+		if v == name {
+			return true
+		}
+	}
+	return false
+}
+
+// Name returns the first type which is not "null", "" if none
+func (t SchemaType) Name() string {
+	for _, v := range t {
+		if v != "null" {
+			return v
+		}
+	}
+	return ""
+}
+
+func (t SchemaType) String() string {
+	return strings.Join(t, "|")
+}
+
+/*
+ExclusiveBound holds exclusiveMaximum and exclusiveMinimum keywords :
+  - OAS 3.0 : a boolean modifier of maximum / minimum
+  - OAS 3.1 : the bound itself, a number
+*/
+type ExclusiveBound struct {
+	IsBool       bool
+	BooleanValue bool
+	Value        float64
+}
+
+// UnmarshalYAML Implements the Unmarshaler interface of the yaml pkg.
+func (e *ExclusiveBound) UnmarshalYAML(value *yaml.Node) error {
+	*e = ExclusiveBound{}
+	if value.Kind == yaml.ScalarNode && value.Tag == "!!bool" {
+		e.IsBool = true
+		return value.Decode(&e.BooleanValue)
+	}
+	err := value.Decode(&e.Value)
+	if err != nil {
+		return fmt.Errorf("line %d: exclusive bound must be a boolean or a number", value.Line)
+	}
+	return nil
+}
+
+// MarshalYAML Implements the Marshaler interface of the yaml pkg.
+func (e *ExclusiveBound) MarshalYAML() (interface{}, error) {
+	if e.IsBool {
+		return e.BooleanValue, nil
+	}
+	return e.Value, nil
+}
+
+// TypeName returns the type of the schema, "null" aside ("string" for [string, "null"])
+func (s *Schema) TypeName() string {
+	return s.Type.Name()
+}
+
+// IsNullable returns true if null is allowed, using nullable (3.0) or type array (3.1)
+func (s *Schema) IsNullable() bool {
+	return s.Nullable || s.Type.Has("null")
+}
+
+// UpperBound returns the maximum of the schema and whether it is exclusive, ok is false if unbounded
+func (s *Schema) UpperBound() (value float64, exclusive bool, ok bool) {
+	return bound(s.Maximum, s.ExclusiveMaximum, func(a, b float64) bool { return a <= b })
+}
+
+// LowerBound returns the minimum of the schema and whether it is exclusive, ok is false if unbounded
+func (s *Schema) LowerBound() (value float64, exclusive bool, ok bool) {
+	return bound(s.Minimum, s.ExclusiveMinimum, func(a, b float64) bool { return a >= b })
+}
+
+// bound merges inclusive and exclusive bounds of both OAS versions, tighter tells if a is the stricter bound
+func bound(inclusive *float64, exclusive *ExclusiveBound, tighter func(a, b float64) bool) (float64, bool, bool) {
+	if exclusive == nil || (exclusive.IsBool && !exclusive.BooleanValue) {
+		if inclusive == nil {
+			return 0, false, false
+		}
+		return *inclusive, false, true
+	}
+	if exclusive.IsBool {
+		// 3.0 : exclusiveMaximum: true modifies maximum
+		if inclusive == nil {
+			return 0, false, false
+		}
+		return *inclusive, true, true
+	}
+	// 3.1 : both keywords may be present, the stricter one applies
+	if inclusive != nil && !tighter(exclusive.Value, *inclusive) {
+		return *inclusive, false, true
+	}
+	return exclusive.Value, true, true
+}
+
+// IsOAS31 returns true for OpenAPI 3.1 documents
+func (oa *OpenAPI) IsOAS31() bool {
+	return strings.HasPrefix(oa.Openapi, "3.1")
+}
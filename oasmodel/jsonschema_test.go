@@ -0,0 +1,77 @@
+package oasmodel
+
+import (
+	"io/ioutil"
+	"testing"
+)
+
+func TestSchemaVersionView(t *testing.T) {
+	data := `openapi: 3.0.3
+info:
+    title: 3.0 bounds
+    version: 1.0.0
+paths: {}
+components:
+    schemas:
+        percent:
+            type: integer
+            nullable: true
+            maximum: 100
+            exclusiveMaximum: true
+            minimum: 0
+`
+	var oa OpenAPI
+	if _, err := oa.UnMarshal([]byte(data)); err != nil {
+		t.Fatalf("error unmarshalling : %v", err)
+	}
+	if oa.IsOAS31() {
+		t.Errorf("3.0.3 document seen as 3.1")
+	}
+	s := oa.Components.Schemas["percent"].Schema()
+	if s.TypeName() != "integer" || !s.IsNullable() {
+		t.Errorf("bad type view : %s %v", s.TypeName(), s.IsNullable())
+	}
+	if max, exclusive, ok := s.UpperBound(); !ok || !exclusive || max != 100 {
+		t.Errorf("bad 3.0 upper bound : %v %v %v", max, exclusive, ok)
+	}
+	if min, exclusive, ok := s.LowerBound(); !ok || exclusive || min != 0 {
+		t.Errorf("bad 3.0 lower bound : %v %v %v", min, exclusive, ok)
+	}
+}
+
+func TestSchema31(t *testing.T) {
+	buf, err := ioutil.ReadFile("assets/oas31-jsonschema.yaml")
+	if err != nil {
+		t.Fatalf("error loading asset : %v", err)
+	}
+	var oa OpenAPI
+	if _, err = oa.UnMarshal(buf); err != nil {
+		t.Fatalf("error unmarshalling : %v", err)
+	}
+	if err = oa.ResolveRefs(); err != nil {
+		t.Fatalf("error resolving : %v", err)
+	}
+	if !oa.IsOAS31() {
+		t.Errorf("3.1.0 document not seen as 3.1")
+	}
+	pet := oa.Components.Schemas["pet"].Schema()
+	age := pet.Properties["age"].Schema()
+	if age.TypeName() != "integer" || !age.IsNullable() {
+		t.Errorf("bad type view : %s %v", age.TypeName(), age.IsNullable())
+	}
+	if max, exclusive, ok := age.UpperBound(); !ok || !exclusive || max != 100 {
+		t.Errorf("bad 3.1 upper bound : %v %v %v", max, exclusive, ok)
+	}
+	if owner := pet.Properties["owner"].Schema(); owner == nil || owner.DependentRequired["email"][0] != "phone" {
+		t.Errorf("$defs reference not resolved : %v", owner)
+	}
+	if pet.If == nil || pet.Then == nil || pet.Else.Schema().Not == nil {
+		t.Errorf("if/then/else not parsed")
+	}
+	if pet.UnevaluatedProperties == nil || !pet.UnevaluatedProperties.IsBool {
+		t.Errorf("unevaluatedProperties not parsed")
+	}
+	if oa.Webhooks["newPet"].Post.RequestBody.RequestBody().Content["application/json"].Schema.Schema() != pet {
+		t.Errorf("webhook reference not resolved")
+	}
+}
@@ -0,0 +1,160 @@
+package oasmodel
+
+import (
+	"fmt"
+	"log"
+	"path/filepath"
+	"reflect"
+	"strings"
+)
+
+// Loader loads a specification split across several files (.yaml, .yml or .json).
+// Each document is loaded once and kept in a cache keyed by its absolute path,
+// $ref to other documents are resolved relatively to the referencing document.
+type Loader struct {
+	documents map[string]*OpenAPI
+	pending   []string
+}
+
+// NewLoader creates a Loader with an empty document cache
+func NewLoader() *Loader {
+	return &Loader{documents: make(map[string]*OpenAPI)}
+}
+
+// Load loads filename and all the documents it references, then resolves every $ref
+func (l *Loader) Load(filename string) (*OpenAPI, error) {
+	path, err := filepath.Abs(filename)
+	if err != nil {
+		return nil, err
+	}
+	root, err := l.document(path)
+	if err != nil {
+		return nil, err
+	}
+	// documents are appended to pending while resolving references
+	for len(l.pending) > 0 {
+		path := l.pending[0]
+		l.pending = l.pending[1:]
+		err = l.resolveDocument(path)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return root, nil
+}
+
+// Document returns a document from the cache, nil if not loaded
+func (l *Loader) Document(filename string) *OpenAPI {
+	path, err := filepath.Abs(filename)
+	if err != nil {
+		return nil
+	}
+	return l.documents[path]
+}
+
+// Documents returns the absolute path of all loaded documents
+func (l *Loader) Documents() []string {
+	paths := make([]string, 0, len(l.documents))
+	for path := range l.documents {
+		paths = append(paths, path)
+	}
+	return paths
+}
+
+// document returns the document from the cache, loading it if needed
+func (l *Loader) document(path string) (*OpenAPI, error) {
+	if oa, ok := l.documents[path]; ok {
+		return oa, nil
+	}
+	log.Printf("Loading %s ...\n", path)
+	oa := &OpenAPI{}
+	// YAML is a superset of JSON, .json documents are read the same way
+	err := oa.Load(path)
+	if err != nil {
+		return nil, err
+	}
+	l.documents[path] = oa
+	l.pending = append(l.pending, path)
+	return oa, nil
+}
+
+// resolveDocument resolves all $ref of a loaded document
+func (l *Loader) resolveDocument(path string) error {
+	var err error
+	walkRefs(reflect.ValueOf(l.documents[path]), "#", func(ref *Ref, location string) {
+		if err != nil {
+			return
+		}
+		if e := l.resolveRef(path, ref, map[string]bool{}); e != nil {
+			err = refError(path, ref, location, e)
+		}
+	})
+	return err
+}
+
+// resolveRef resolves ref found in document path, chain holds the $ref being resolved to detect cycles
+func (l *Loader) resolveRef(path string, ref *Ref, chain map[string]bool) error {
+	if ref.Resolved != nil {
+		return nil
+	}
+	document, tokens, err := splitRef(ref.Ref)
+	if err != nil {
+		return err
+	}
+	target := path
+	if document != "" {
+		target = filepath.Join(filepath.Dir(path), filepath.FromSlash(document))
+	}
+
+	key := target + "#" + joinPointer(tokens)
+	if chain[key] {
+		return fmt.Errorf("circular reference %s", ref.Ref)
+	}
+	chain[key] = true
+	defer delete(chain, key)
+
+	oa, err := l.document(target)
+	if err != nil {
+		return err
+	}
+	resolved, err := oa.lookup(tokens, func(r *Ref) (interface{}, error) {
+		if err := l.resolveRef(target, r, chain); err != nil {
+			return nil, err
+		}
+		return r.Resolved, nil
+	})
+	if err != nil {
+		return fmt.Errorf("can't resolve %s %v", ref.Ref, err)
+	}
+	// target may itself be a $ref
+	if inner := refOf(resolved); inner != nil {
+		if err := l.resolveRef(target, inner, chain); err != nil {
+			return err
+		}
+	}
+
+	ref.Resolved = resolved
+	if len(tokens) > 0 {
+		ref.RefName = tokens[len(tokens)-1]
+	}
+	if document != "" {
+		ref.External = documentName(document)
+		ref.Document = target
+	}
+	return nil
+}
+
+// joinPointer builds a JSON Pointer from reference tokens
+func joinPointer(tokens []string) string {
+	pointer := ""
+	for _, t := range tokens {
+		pointer += "/" + EscapeToken(t)
+	}
+	return pointer
+}
+
+// documentName returns the name of a referenced document : ../common/types.yaml gives types
+func documentName(document string) string {
+	name := filepath.Base(filepath.FromSlash(document))
+	return strings.TrimSuffix(name, filepath.Ext(name))
+}
@@ -0,0 +1,43 @@
+package oasmodel
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLoaderExternalRefs(t *testing.T) {
+	l := NewLoader()
+	oa, err := l.Load("assets/split/api.yaml")
+	if err != nil {
+		t.Fatalf("error loading split spec : %v", err)
+	}
+	if len(l.Documents()) != 3 {
+		t.Errorf("expected 3 documents in cache, got %v", l.Documents())
+	}
+
+	param := oa.Paths["/pets/{id}"].Get.Parameters[0].Parameter()
+	if param == nil || param.Schema.Schema() == nil || param.Schema.Schema().Format != "uuid" {
+		t.Fatalf("parameter not resolved across documents : %v", param)
+	}
+
+	owner := oa.Components.Schemas["pet"].Val.Properties["owner"]
+	if owner.Ref.External != "types" || owner.Ref.RefName != "person" {
+		t.Errorf("bad external reference : %s %s", owner.Ref.External, owner.Ref.RefName)
+	}
+	if !filepath.IsAbs(owner.Ref.Document) || filepath.Base(owner.Ref.Document) != "types.json" {
+		t.Errorf("bad external document : %s", owner.Ref.Document)
+	}
+	// types.json references back pet in api.yaml
+	pets := owner.Schema().Properties["pets"].Schema().Items.Schema()
+	if pets != oa.Components.Schemas["pet"].Val {
+		t.Errorf("back reference must resolve to the cached document")
+	}
+}
+
+func TestLoaderCycle(t *testing.T) {
+	_, err := NewLoader().Load("assets/split/cycle.yaml")
+	if err == nil || !strings.Contains(err.Error(), "circular reference") {
+		t.Errorf("circular reference not detected : %v", err)
+	}
+}
@@ -5,18 +5,11 @@ import (
 	"io"
 	"io/ioutil"
 	"log"
-	"os"
-	"regexp"
+	"strconv"
 
 	"gopkg.in/yaml.v3"
 )
 
-// refIndexElement part of map used to resolve $ref directive
-type refIndexElement struct {
-	name   string
-	schema *SchemaOrRef
-}
-
 /*
 OpenAPI From OAS
 openapi 		string 	REQUIRED. 			This string MUST be the semantic version number of the OpenAPI Specification version that the OpenAPI document uses. The openapi field SHOULD be used by tooling specifications and clients to interpret the OpenAPI document. This is not related to the API info.version string.
@@ -27,18 +20,23 @@ components 		Components Object 			An element to hold various schemas for the spe
 security 		[Security Requirement Object] 	A declaration of which security mechanisms can be used across the API. The list of values includes alternative security requirement objects that can be used. Only one of the security requirement objects need to be satisfied to authorize a request. Individual operations can override this definition. To make security optional, an empty security requirement ({}) can be included in the array.
 tags 			[Tag Object] 				A list of tags used by the specification with additional metadata. The order of the tags can be used to reflect on their order by the parsing tools. Not all tags that are used by the Operation Object must be declared. The tags that are not declared MAY be organized randomly or based on the tools' logic. Each tag name in the list MUST be unique.
 externalDocs 	External Documentation Object 	Additional external documentation.
+-- OAS 3.1
+jsonSchemaDialect	string		The default value for the $schema keyword within Schema Objects contained within this OAS document.
+webhooks		Map[string, Path Item Object | Reference Object]	The incoming webhooks that MAY be received as part of this API.
 */
 type OpenAPI struct {
-	Openapi      string                     `yaml:"openapi"`
-	Info         Info                       `yaml:"info"`
-	Servers      []Server                   `yaml:"servers,omitempty"`
-	Paths        map[string]PathItem        `yaml:"paths"`
-	Components   Components                 `yaml:"components,omitempty"`
-	Security     []SecurityReq              `yaml:"security,omitempty"`
-	Tags         []Tag                      `yaml:"tags,omitempty"`
-	ExternalDocs ExternalDocs               `yaml:"externalDocs,omitempty"`
-	RefIndex     map[string]refIndexElement `yaml:"-"`
-	XWsRPC       map[string]XwsRPCService   `yaml:"x-ws-rpc,omitempty"`
+	Openapi           string                   `yaml:"openapi"`
+	Info              Info                     `yaml:"info"`
+	JSONSchemaDialect string                   `yaml:"jsonSchemaDialect,omitempty"`
+	Servers           []Server                 `yaml:"servers,omitempty"`
+	Paths             map[string]PathItem      `yaml:"paths"`
+	Webhooks          map[string]PathItem      `yaml:"webhooks,omitempty"`
+	Components        Components               `yaml:"components,omitempty"`
+	Security          []SecurityReq            `yaml:"security,omitempty"`
+	Tags              []Tag                    `yaml:"tags,omitempty"`
+	ExternalDocs      ExternalDocs             `yaml:"externalDocs,omitempty"`
+	XWsRPC            map[string]XwsRPCService `yaml:"x-ws-rpc,omitempty"`
+	file              string                   // file the document is loaded from
 }
 
 /*
@@ -64,6 +62,7 @@ headers 		Map[string, Header Object | Reference Object] 		An object to hold reus
 securitySchemes 	Map[string, Security Scheme Object | Reference Object] 	An object to hold reusable Security Scheme Objects.
 links 			Map[string, Link Object | Reference Object] 		An object to hold reusable Link Objects.
 callbacks 		Map[string, Callback Object | Reference Object] 	An object to hold reusable Callback Objects.
+pathItems 		Map[string, Path Item Object | Reference Object] 	An object to hold reusable Path Item Objects (OAS 3.1).
 */
 type Components struct {
 	Schemas         map[string]*SchemaOrRef         `yaml:"schemas,omitempty"`
@@ -75,11 +74,13 @@ type Components struct {
 	SecuritySchemes map[string]*SecuritySchemeOrRef `yaml:"securitySchemes,omitempty"`
 	Links           map[string]*LinkOrRef           `yaml:"links,omitempty"`
 	Callbacks       map[string]*CallbackOrRef       `yaml:"callbacks,omitempty"`
+	PathItems       map[string]*PathItem            `yaml:"pathItems,omitempty"`
 }
 
 /*
 Info ... From OAS Specifications :
 title 			string REQUIRED. 	The title of the API.
+summary 		string 				A short summary of the API (OAS 3.1).
 description 	string 				A short description of the API. CommonMark syntax MAY be used for rich text representation.
 termsOfService 	string 				A URL to the Terms of Service for the API. MUST be in the format of a URL.
 contact			Contact Object		The contact information for the exposed API.
@@ -88,12 +89,13 @@ version			string REQUIRED. 	The version of the OpenAPI document (which is distin
 */
 type Info struct {
 	Title          string   `yaml:"title"`
+	Summary        string   `yaml:"summary,omitempty"`
 	Description    string   `yaml:"description,omitempty"`
 	TermsOfService string   `yaml:"termsOfService,omitempty"`
 	Contact        *Contact `yaml:"contact,omitempty"`
 	License        *License `yaml:"license,omitempty"`
 	Version        string   `yaml:"version"`
-	XPackage       string   `yaml:"x-package"`
+	XPackage       string   `yaml:"x-package,omitempty"`
 }
 
 /*
@@ -111,11 +113,13 @@ type Contact struct {
 /*
 License from OAS Specifications :
 name      string REQUIRED. The license name used for the API.
+identifier string An SPDX license expression for the API, mutually exclusive with url (OAS 3.1).
 url string A URL to the license used for the API. MUST be in the format of a URL.
 */
 type License struct {
-	Name string `yaml:"name"`
-	URL  string `yaml:"url,omitempty"`
+	Name       string `yaml:"name"`
+	Identifier string `yaml:"identifier,omitempty"`
+	URL        string `yaml:"url,omitempty"`
 }
 
 /*
@@ -181,6 +185,27 @@ type PathItem struct {
 	Parameters  []ParameterOrRef `yaml:"parameters,omitempty"`
 }
 
+// MethodOperation is an operation of a path item with its http method
+type MethodOperation struct {
+	Method    string // lower case : get, put...
+	Operation *Operation
+}
+
+// Operations returns the defined operations of the path item, in the order of the model
+func (p *PathItem) Operations() []MethodOperation {
+	all := []MethodOperation{
+		{"get", p.Get}, {"put", p.Put}, {"post", p.Post}, {"delete", p.Delete},
+		{"options", p.Options}, {"head", p.Head}, {"patch", p.Patch}, {"trace", p.Trace},
+	}
+	ops := make([]MethodOperation, 0, len(all))
+	for _, op := range all {
+		if op.Operation != nil {
+			ops = append(ops, op)
+		}
+	}
+	return ops
+}
+
 /*
 Operation Object from OAS
 tags			[string]								A list of tags for API documentation control. Tags can be used for logical grouping of operations by resources or any other qualifier.
@@ -203,7 +228,7 @@ type Operation struct {
 	ExternalDocs *ExternalDocs       `yaml:"externalDocs,omitempty"`
 	OperationID  string              `yaml:"operationId,omitempty"`
 	Parameters   []*ParameterOrRef   `yaml:"parameters,omitempty"`
-	RequestBody  *RequestBody        `yaml:"requestBody,omitempty"`
+	RequestBody  *RequestBodyOrRef   `yaml:"requestBody,omitempty"`
 	Responses    Responses           `yaml:"responses"`
 	Callbacks    map[string]Callback `yaml:"callbacks,omitempty"`
 	Deprecated   bool                `yaml:"deprecated,omitempty"`
@@ -358,26 +383,26 @@ type Response struct {
 type Callback map[string]PathItem
 
 // UnmarshalYAML Implements the Unmarshaler interface of the yaml pkg.
-func (e *CallbackOrRef) UnmarshalYAML(unmarshal func(interface{}) error) error {
+func (e *CallbackOrRef) UnmarshalYAML(value *yaml.Node) error {
 	*e = CallbackOrRef{}
-	ref := Ref{}
-	err := unmarshal(&ref)
-	if err != nil || ref.Ref == "" {
-		val := make(Callback)
-		err = unmarshal(&val)
-		if err != nil {
-			fmt.Fprintln(os.Stderr, "error un marshalling CallbackOrRef")
-			return err
-		}
-		e.Val = &val
+	ref, err := decodeRef(value)
+	if err != nil {
+		return err
+	}
+	if ref != nil {
+		e.Ref = ref
 		return nil
 	}
-	e.Ref = &ref
-
+	val := make(Callback)
+	err = value.Decode(&val)
+	if err != nil {
+		return err
+	}
+	e.Val = &val
 	return nil
 }
 
-// Implements the Unmarshaler interface of the yaml pkg.
+// MarshalYAML Implements the Marshaler interface of the yaml pkg.
 func (e *CallbackOrRef) MarshalYAML() (interface{}, error) {
 	if e.Ref != nil {
 		return e.Ref, nil
@@ -388,18 +413,18 @@ func (e *CallbackOrRef) MarshalYAML() (interface{}, error) {
 type SecurityReq map[string][]string
 
 type Header struct {
-	Ref             string               `yaml:"$ref,omitempty"`
-	Description     string               `yaml:"description,omitempty"`
-	Required        bool                 `yaml:"required,omitempty"`
-	Deprecated      bool                 `yaml:"deprecated,omitempty"`
-	AllowEmptyValue bool                 `yaml:"allowEmptyValue,omitempty"`
-	Style           string               `yaml:"style,omitempty"`
-	Explode         *bool                `yaml:"required,omitempty"` // default value depends on style
-	AllowReserved   bool                 `yaml:"allowReserved,omitempty"`
-	Schema          *Schema              `yaml:"schema,omitempty"`
-	Example         *ExampleValue        `yaml:"example,omitempty"`
-	Examples        map[string]Example   `yaml:"examples,omitempty"`
-	Content         map[string]MediaType `yaml:"content,omitempty"`
+	Ref             string                  `yaml:"$ref,omitempty"`
+	Description     string                  `yaml:"description,omitempty"`
+	Required        bool                    `yaml:"required,omitempty"`
+	Deprecated      bool                    `yaml:"deprecated,omitempty"`
+	AllowEmptyValue bool                    `yaml:"allowEmptyValue,omitempty"`
+	Style           string                  `yaml:"style,omitempty"`
+	Explode         *bool                   `yaml:"explode,omitempty"` // default value depends on style
+	AllowReserved   bool                    `yaml:"allowReserved,omitempty"`
+	Schema          *SchemaOrRef            `yaml:"schema,omitempty"`
+	Example         *ExampleValue           `yaml:"example,omitempty"`
+	Examples        map[string]ExampleOrRef `yaml:"examples,omitempty"`
+	Content         map[string]MediaType    `yaml:"content,omitempty"`
 }
 
 /*
@@ -462,43 +487,77 @@ xml				XML Object				This MAY be used only on properties schemas. It has no effe
 externalDocs	External Documentation Object	Additional external documentation for this schema.
 example			Any						A free-form property to include an example of an instance for this schema. To represent examples that cannot be naturally represented in JSON or YAML, a string value can be used to contain the example with escaping where necessary.
 deprecated		boolean					Specifies that a schema is deprecated and SHOULD be transitioned out of usage. Default value is false.
+
+-- JSON Schema 2020-12 (OAS 3.1)
+type may be an array of types, eg: [string, "null"], nullable is replaced by "null" type
+exclusiveMaximum / exclusiveMinimum are numbers instead of boolean modifiers
+$id, $schema, $anchor, $comment, $defs, const, examples, prefixItems, contains, minContains, maxContains,
+if, then, else, dependentRequired, dependentSchemas, patternProperties, propertyNames,
+unevaluatedItems, unevaluatedProperties, contentEncoding, contentMediaType
 */
 type Schema struct {
-	Type                 string                  `yaml:"type,omitempty"`
-	Title                string                  `yaml:"title,omitempty"`
-	MultipleOf           int                     `yaml:"multipleOf,omitempty"`
-	Maximum              int                     `yaml:"maximum,omitempty"`
-	ExclusiveMaximum     int                     `yaml:"exclusiveMaximum,omitempty"`
-	Minimum              int                     `yaml:"minimum,omitempty"`
-	ExclusiveMinimum     int                     `yaml:"exclusiveMinimum,omitempty"`
-	MaxLength            int                     `yaml:"maxLength,omitempty"`
-	MinLength            int                     `yaml:"minLength,omitempty"`
-	Pattern              string                  `yaml:"pattern,omitempty"`
-	MaxItems             int                     `yaml:"maxItems,omitempty"`
-	MinItems             int                     `yaml:"minItems,omitempty"`
-	UniqueItems          bool                    `yaml:"uniqueItems,omitempty"`
-	MaxProperties        int                     `yaml:"maxProperties,omitempty"`
-	MinProperties        int                     `yaml:"minProperties,omitempty"`
-	Required             []string                `yaml:"required,omitempty"`
-	Enum                 []string                `yaml:"enum,omitempty"`
-	AllOf                []*SchemaOrRef          `yaml:"allOf,omitempty"`
-	OneOf                []*SchemaOrRef          `yaml:"oneOf,omitempty"`
-	AnyOf                []*SchemaOrRef          `yaml:"anyOf,omitempty"`
-	Items                *SchemaOrRef            `yaml:"items,omitempty"`
-	XPropertiesOrder     []string                `yaml:"x-properties-order"`
-	Properties           map[string]*SchemaOrRef `yaml:"properties,omitempty"`
-	AdditionalProperties *AdditionalProperties   `yaml:"additionalProperties,omitempty"`
-	Description          string                  `yaml:"description,omitempty"`
-	Format               string                  `yaml:"format,omitempty"`
-	Default              string                  `yaml:"default,omitempty"`
-	Nullable             bool                    `yaml:"nullable,omitempty"`
-	Discriminator        *Discriminator          `yaml:"discriminator,omitempty"`
-	ReadOnly             bool                    `yaml:"readonly,omitempty"`
-	WriteOnly            bool                    `yaml:"writeOnly,omitempty"`
-	XML                  XML                     `yaml:"xml,omitempty"`
-	ExternalDocs         *ExternalDocs           `yaml:"externalDocs,omitempty"`
-	Example              *Example                `yaml:"example,omitempty"`
-	Deprecated           bool                    `yaml:"depreacated,omitempty"`
+	Type                  SchemaType              `yaml:"type,omitempty"`
+	Title                 string                  `yaml:"title,omitempty"`
+	MultipleOf            float64                 `yaml:"multipleOf,omitempty"`
+	Maximum               *float64                `yaml:"maximum,omitempty"`
+	ExclusiveMaximum      *ExclusiveBound         `yaml:"exclusiveMaximum,omitempty"`
+	Minimum               *float64                `yaml:"minimum,omitempty"`
+	ExclusiveMinimum      *ExclusiveBound         `yaml:"exclusiveMinimum,omitempty"`
+	MaxLength             int                     `yaml:"maxLength,omitempty"`
+	MinLength             int                     `yaml:"minLength,omitempty"`
+	Pattern               string                  `yaml:"pattern,omitempty"`
+	MaxItems              int                     `yaml:"maxItems,omitempty"`
+	MinItems              int                     `yaml:"minItems,omitempty"`
+	UniqueItems           bool                    `yaml:"uniqueItems,omitempty"`
+	MaxProperties         int                     `yaml:"maxProperties,omitempty"`
+	MinProperties         int                     `yaml:"minProperties,omitempty"`
+	Required              []string                `yaml:"required,omitempty"`
+	Enum                  []string                `yaml:"enum,omitempty"`
+	AllOf                 []*SchemaOrRef          `yaml:"allOf,omitempty"`
+	OneOf                 []*SchemaOrRef          `yaml:"oneOf,omitempty"`
+	AnyOf                 []*SchemaOrRef          `yaml:"anyOf,omitempty"`
+	Not                   *SchemaOrRef            `yaml:"not,omitempty"`
+	Items                 *SchemaOrRef            `yaml:"items,omitempty"`
+	XPropertiesOrder      []string                `yaml:"x-properties-order,omitempty"`
+	XProtoFieldNumber     int                     `yaml:"x-proto-field-number,omitempty"`
+	XEnumVarnames         []string                `yaml:"x-enum-varnames,omitempty"`
+	XEnumDescriptions     []string                `yaml:"x-enum-descriptions,omitempty"`
+	XProtoOptions         map[string]interface{}  `yaml:"x-proto-options,omitempty"`
+	Properties            map[string]*SchemaOrRef `yaml:"properties,omitempty"`
+	AdditionalProperties  *AdditionalProperties   `yaml:"additionalProperties,omitempty"`
+	Description           string                  `yaml:"description,omitempty"`
+	Format                string                  `yaml:"format,omitempty"`
+	Default               string                  `yaml:"default,omitempty"`
+	Nullable              bool                    `yaml:"nullable,omitempty"`
+	Discriminator         *Discriminator          `yaml:"discriminator,omitempty"`
+	ReadOnly              bool                    `yaml:"readOnly,omitempty"`
+	WriteOnly             bool                    `yaml:"writeOnly,omitempty"`
+	XML                   XML                     `yaml:"xml,omitempty"`
+	ExternalDocs          *ExternalDocs           `yaml:"externalDocs,omitempty"`
+	Example               interface{}             `yaml:"example,omitempty"`
+	Deprecated            bool                    `yaml:"deprecated,omitempty"`
+	ID                    string                  `yaml:"$id,omitempty"`
+	Dialect               string                  `yaml:"$schema,omitempty"`
+	Anchor                string                  `yaml:"$anchor,omitempty"`
+	Comment               string                  `yaml:"$comment,omitempty"`
+	Defs                  map[string]*SchemaOrRef `yaml:"$defs,omitempty"`
+	Const                 interface{}             `yaml:"const,omitempty"`
+	Examples              []interface{}           `yaml:"examples,omitempty"`
+	PrefixItems           []*SchemaOrRef          `yaml:"prefixItems,omitempty"`
+	Contains              *SchemaOrRef            `yaml:"contains,omitempty"`
+	MinContains           *int                    `yaml:"minContains,omitempty"`
+	MaxContains           *int                    `yaml:"maxContains,omitempty"`
+	If                    *SchemaOrRef            `yaml:"if,omitempty"`
+	Then                  *SchemaOrRef            `yaml:"then,omitempty"`
+	Else                  *SchemaOrRef            `yaml:"else,omitempty"`
+	DependentRequired     map[string][]string     `yaml:"dependentRequired,omitempty"`
+	DependentSchemas      map[string]*SchemaOrRef `yaml:"dependentSchemas,omitempty"`
+	PatternProperties     map[string]*SchemaOrRef `yaml:"patternProperties,omitempty"`
+	PropertyNames         *SchemaOrRef            `yaml:"propertyNames,omitempty"`
+	UnevaluatedItems      *AdditionalProperties   `yaml:"unevaluatedItems,omitempty"`
+	UnevaluatedProperties *AdditionalProperties   `yaml:"unevaluatedProperties,omitempty"`
+	ContentEncoding       string                  `yaml:"contentEncoding,omitempty"`
+	ContentMediaType      string                  `yaml:"contentMediaType,omitempty"`
 }
 
 type AdditionalProperties struct {
@@ -519,7 +578,7 @@ type Encoding struct {
 	ContentType   string                 `yaml:"contentType,omitempty"`
 	Headers       map[string]HeaderOrRef `yaml:"headers,omitempty"`
 	Style         string                 `yaml:"style,omitempty"`
-	Explode       *bool                  `yaml:"required,omitempty"` // default value depends on style
+	Explode       *bool                  `yaml:"explode,omitempty"` // default value depends on style
 	AllowReserved bool                   `yaml:"allowReserved,omitempty"`
 }
 
@@ -551,6 +610,17 @@ type Discriminator struct {
 	Mapping      map[string]string `yaml:"mapping,omitempty"`
 }
 
+// UnmarshalYAML Implements the Unmarshaler interface of the yaml pkg.
+// Swagger 2.0 discriminator is the property name only.
+func (d *Discriminator) UnmarshalYAML(value *yaml.Node) error {
+	if value.Kind == yaml.ScalarNode {
+		*d = Discriminator{PropertyName: value.Value}
+		return nil
+	}
+	type discriminator Discriminator
+	return value.Decode((*discriminator)(d))
+}
+
 /*
 XML Object from OAS
 name	string	Replaces the name of the element/attribute used for the described schema property. When defined within items, it will affect the name of the individual XML elements within the list. When defined alongside type being array (outside the items), it will affect the wrapping element and only if wrapped is true. If wrapped is false, it will be ignored.
@@ -568,12 +638,39 @@ type XML struct {
 }
 
 type Ref struct {
-	Ref         string      `yaml:"$ref,omitempty"`
-	Description string      `yaml:"description,omitempty"`
-	Resolved    interface{} `yaml:"-"`
-	RefName     string      `yaml:"-"`
-	External    string      `yaml:"-"`
+	Ref               string                 `yaml:"$ref,omitempty"`
+	Description       string                 `yaml:"description,omitempty"`
+	XProtoFieldNumber int                    `yaml:"x-proto-field-number,omitempty"` // of a property defined by reference
+	XProtoOptions     map[string]interface{} `yaml:"x-proto-options,omitempty"`
+	Resolved          interface{}            `yaml:"-"`
+	RefName           string                 `yaml:"-"`
+	External          string                 `yaml:"-"`
+	Document          string                 `yaml:"-"` // absolute path of the external document, set by Loader
+	Line              int                    `yaml:"-"` // position of the $ref in its document
+	Column            int                    `yaml:"-"`
+}
+
+// decodeRef returns the Reference Object held by value, nil if value is not a reference
+func decodeRef(value *yaml.Node) (*Ref, error) {
+	if value.Kind != yaml.MappingNode {
+		return nil, nil
+	}
+	for i := 0; i+1 < len(value.Content); i += 2 {
+		if value.Content[i].Value != "$ref" {
+			continue
+		}
+		ref := Ref{}
+		err := value.Decode(&ref)
+		if err != nil {
+			return nil, err
+		}
+		ref.Line = value.Content[i].Line
+		ref.Column = value.Content[i].Column
+		return &ref, nil
+	}
+	return nil, nil
 }
+
 type CallbackOrRef struct {
 	Ref *Ref
 	Val *Callback
@@ -615,89 +712,223 @@ type SecuritySchemeOrRef struct {
 	Val *SecurityScheme
 }
 
-// Implements the Unmarshaler interface of the yaml pkg.
-func (e *MediaTypeOrRef) UnmarshalYAML(unmarshal func(interface{}) error) error {
-	*e = MediaTypeOrRef{nil, nil}
-	ref := Ref{}
-	err := unmarshal(&ref)
-	if err != nil || ref.Ref == "" {
-		val := MediaType{}
-		err = unmarshal(&val)
-		if err != nil {
-			fmt.Fprintln(os.Stderr, "error un marshalling MediaTypeOrRef")
-			return err
-		}
+// UnmarshalYAML Implements the Unmarshaler interface of the yaml pkg.
+func (e *ExampleOrRef) UnmarshalYAML(value *yaml.Node) error {
+	*e = ExampleOrRef{}
+	ref, err := decodeRef(value)
+	if err != nil {
+		return err
+	}
+	if ref != nil {
+		e.Ref = ref
+		return nil
+	}
+	val := Example{}
+	err = value.Decode(&val)
+	if err != nil {
+		return err
+	}
+	e.Val = &val
+	return nil
+}
 
-		e.Val = &val
+// MarshalYAML Implements the Marshaler interface of the yaml pkg.
+func (e *ExampleOrRef) MarshalYAML() (interface{}, error) {
+	if e.Ref != nil {
+		return e.Ref, nil
+	}
+	return e.Val, nil
+}
+
+// UnmarshalYAML Implements the Unmarshaler interface of the yaml pkg.
+func (e *HeaderOrRef) UnmarshalYAML(value *yaml.Node) error {
+	*e = HeaderOrRef{}
+	ref, err := decodeRef(value)
+	if err != nil {
+		return err
+	}
+	if ref != nil {
+		e.Ref = ref
 		return nil
 	}
-	e.Ref = &ref
+	val := Header{}
+	err = value.Decode(&val)
+	if err != nil {
+		return err
+	}
+	e.Val = &val
 	return nil
 }
 
-// Implements the Unmarshaler interface of the yaml pkg.
-func (e *MediaTypeOrRef) MarshalYAML() (interface{}, error) {
+// MarshalYAML Implements the Marshaler interface of the yaml pkg.
+func (e *HeaderOrRef) MarshalYAML() (interface{}, error) {
 	if e.Ref != nil {
 		return e.Ref, nil
 	}
 	return e.Val, nil
 }
 
-// Implements the Unmarshaler interface of the yaml pkg.
-func (e *ParameterOrRef) UnmarshalYAML(unmarshal func(interface{}) error) error {
-	*e = ParameterOrRef{}
-	ref := Ref{}
-	err := unmarshal(&ref)
+// UnmarshalYAML Implements the Unmarshaler interface of the yaml pkg.
+func (e *LinkOrRef) UnmarshalYAML(value *yaml.Node) error {
+	*e = LinkOrRef{}
+	ref, err := decodeRef(value)
+	if err != nil {
+		return err
+	}
+	if ref != nil {
+		e.Ref = ref
+		return nil
+	}
+	val := Link{}
+	err = value.Decode(&val)
+	if err != nil {
+		return err
+	}
+	e.Val = &val
+	return nil
+}
 
-	if ref.Ref == "" || err != nil {
-		val := Parameter{}
-		err = unmarshal(&val)
-		if err != nil {
-			fmt.Fprintln(os.Stderr, "error un marshalling CallbackOrRef")
-			return err
-		}
-		e.Val = &val
+// MarshalYAML Implements the Marshaler interface of the yaml pkg.
+func (e *LinkOrRef) MarshalYAML() (interface{}, error) {
+	if e.Ref != nil {
+		return e.Ref, nil
+	}
+	return e.Val, nil
+}
+
+// UnmarshalYAML Implements the Unmarshaler interface of the yaml pkg.
+func (e *RequestBodyOrRef) UnmarshalYAML(value *yaml.Node) error {
+	*e = RequestBodyOrRef{}
+	ref, err := decodeRef(value)
+	if err != nil {
+		return err
+	}
+	if ref != nil {
+		e.Ref = ref
 		return nil
 	}
+	val := RequestBody{}
+	err = value.Decode(&val)
+	if err != nil {
+		return err
+	}
+	e.Val = &val
+	return nil
+}
 
-	match, _ := regexp.MatchString("#/components/parameters/[A-Za-z_0-9/\\-]+$", ref.Ref)
-	if !match {
-		return fmt.Errorf("ref.Ref match not component reference")
+// MarshalYAML Implements the Marshaler interface of the yaml pkg.
+func (e *RequestBodyOrRef) MarshalYAML() (interface{}, error) {
+	if e.Ref != nil {
+		return e.Ref, nil
 	}
-	e.Ref = &ref
+	return e.Val, nil
+}
 
+// UnmarshalYAML Implements the Unmarshaler interface of the yaml pkg.
+func (e *SecuritySchemeOrRef) UnmarshalYAML(value *yaml.Node) error {
+	*e = SecuritySchemeOrRef{}
+	ref, err := decodeRef(value)
+	if err != nil {
+		return err
+	}
+	if ref != nil {
+		e.Ref = ref
+		return nil
+	}
+	val := SecurityScheme{}
+	err = value.Decode(&val)
+	if err != nil {
+		return err
+	}
+	e.Val = &val
 	return nil
 }
 
-// Implements the Unmarshaler interface of the yaml pkg.
-func (e *ParameterOrRef) MarshalYAML() (interface{}, error) {
+// MarshalYAML Implements the Marshaler interface of the yaml pkg.
+func (e *SecuritySchemeOrRef) MarshalYAML() (interface{}, error) {
 	if e.Ref != nil {
 		return e.Ref, nil
 	}
 	return e.Val, nil
 }
 
-// Implements the Unmarshaler interface of the yaml pkg.
-func (e *ResponseOrRef) UnmarshalYAML(unmarshal func(interface{}) error) error {
-	*e = ResponseOrRef{nil, nil}
-	ref := Ref{}
-	err := unmarshal(&ref)
-	if err != nil || ref.Ref == "" {
-		val := Response{}
-		err = unmarshal(&val)
-		if err != nil {
-			fmt.Fprintln(os.Stderr, "error un marshalling ResponseOrRef")
-			return err
-		}
+// UnmarshalYAML Implements the Unmarshaler interface of the yaml pkg.
+func (e *MediaTypeOrRef) UnmarshalYAML(value *yaml.Node) error {
+	*e = MediaTypeOrRef{}
+	ref, err := decodeRef(value)
+	if err != nil {
+		return err
+	}
+	if ref != nil {
+		e.Ref = ref
+		return nil
+	}
+	val := MediaType{}
+	err = value.Decode(&val)
+	if err != nil {
+		return err
+	}
+	e.Val = &val
+	return nil
+}
+
+// MarshalYAML Implements the Marshaler interface of the yaml pkg.
+func (e *MediaTypeOrRef) MarshalYAML() (interface{}, error) {
+	if e.Ref != nil {
+		return e.Ref, nil
+	}
+	return e.Val, nil
+}
+
+// UnmarshalYAML Implements the Unmarshaler interface of the yaml pkg.
+func (e *ParameterOrRef) UnmarshalYAML(value *yaml.Node) error {
+	*e = ParameterOrRef{}
+	ref, err := decodeRef(value)
+	if err != nil {
+		return err
+	}
+	if ref != nil {
+		e.Ref = ref
+		return nil
+	}
+	val := Parameter{}
+	err = value.Decode(&val)
+	if err != nil {
+		return err
+	}
+	e.Val = &val
+	return nil
+}
+
+// MarshalYAML Implements the Marshaler interface of the yaml pkg.
+func (e *ParameterOrRef) MarshalYAML() (interface{}, error) {
+	if e.Ref != nil {
+		return e.Ref, nil
+	}
+	return e.Val, nil
+}
 
-		e.Val = &val
+// UnmarshalYAML Implements the Unmarshaler interface of the yaml pkg.
+func (e *ResponseOrRef) UnmarshalYAML(value *yaml.Node) error {
+	*e = ResponseOrRef{}
+	ref, err := decodeRef(value)
+	if err != nil {
+		return err
+	}
+	if ref != nil {
+		e.Ref = ref
 		return nil
 	}
-	e.Ref = &ref
+	val := Response{}
+	err = value.Decode(&val)
+	if err != nil {
+		return err
+	}
+	e.Val = &val
 	return nil
 }
 
-// Implements the Unmarshaler interface of the yaml pkg.
+// MarshalYAML Implements the Marshaler interface of the yaml pkg.
 func (e *ResponseOrRef) MarshalYAML() (interface{}, error) {
 	if e.Ref != nil {
 		return e.Ref, nil
@@ -705,43 +936,62 @@ func (e *ResponseOrRef) MarshalYAML() (interface{}, error) {
 	return e.Val, nil
 }
 
-// Implements the Unmarshaler interface of the yaml pkg.
-func (s *SchemaOrRef) UnmarshalYAML(unmarshal func(interface{}) error) error {
+// UnmarshalYAML Implements the Unmarshaler interface of the yaml pkg.
+func (s *SchemaOrRef) UnmarshalYAML(value *yaml.Node) error {
 	*s = SchemaOrRef{nil, nil}
-	ref := Ref{}
-	err := unmarshal(&ref)
-	if err != nil || ref.Ref == "" {
+	ref, err := decodeRef(value)
+	if err != nil {
+		return err
+	}
+	if ref == nil {
 		val := Schema{}
-		err = unmarshal(&val)
+		err = value.Decode(&val)
 		if err != nil {
-			fmt.Fprintln(os.Stderr, "error un marshalling SchemaOrRef", ref)
 			return err
 		}
-
 		s.Val = &val
 		return nil
 	}
-	s.Ref = &ref
-	re := regexp.MustCompile(`([a-zA-Z0-9_\-.]+).yaml#/components/([a-zA-Z0-9_]+)/([a-zA-Z0-9]+)`)
-	if re == nil {
-		return nil
-	}
-
-	result := re.FindStringSubmatch(s.Ref.Ref)
-	if len(result) > 0 {
-		s.Ref.RefName = result[3]
-		s.Ref.External = result[1]
+	s.Ref = ref
+	// external reference : file.yaml#/components/schemas/name
+	document, tokens, err := splitRef(s.Ref.Ref)
+	if err == nil && document != "" {
+		s.Ref.External = documentName(document)
+		if len(tokens) > 0 {
+			s.Ref.RefName = tokens[len(tokens)-1]
+		}
 	}
 
 	return nil
 }
 
-// Implements the Unmarshaler interface of the yaml pkg.
+// MarshalYAML Implements the Marshaler interface of the yaml pkg.
+// Enum values are strings, those of integer and number schemas are written as numbers.
 func (s *SchemaOrRef) MarshalYAML() (interface{}, error) {
 	if s.Ref != nil {
 		return s.Ref, nil
 	}
-	return s.Val, nil
+	if s.Val == nil || len(s.Val.Enum) == 0 || (s.Val.TypeName() != "integer" && s.Val.TypeName() != "number") {
+		return s.Val, nil
+	}
+	var node yaml.Node
+	err := node.Encode(s.Val)
+	if err != nil {
+		return nil, err
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value != "enum" {
+			continue
+		}
+		for _, value := range node.Content[i+1].Content {
+			if _, err := strconv.ParseInt(value.Value, 10, 64); err == nil {
+				value.Tag, value.Style = "!!int", 0
+			} else if _, err := strconv.ParseFloat(value.Value, 64); err == nil {
+				value.Tag, value.Style = "!!float", 0
+			}
+		}
+	}
+	return &node, nil
 }
 
 func (s *SchemaOrRef) Description() string {
@@ -751,27 +1001,39 @@ func (s *SchemaOrRef) Description() string {
 	return s.Val.Description
 }
 
-// Implements the Unmarshaler interface of the yaml pkg.
-func (e *AdditionalProperties) UnmarshalYAML(unmarshal func(interface{}) error) error {
+// FieldNumber returns the x-proto-field-number of a property, 0 if not set
+func (s *SchemaOrRef) FieldNumber() int {
+	if s.Ref != nil {
+		return s.Ref.XProtoFieldNumber
+	}
+	return s.Val.XProtoFieldNumber
+}
+
+// ProtoOptions returns the x-proto-options of a property, field options by name
+func (s *SchemaOrRef) ProtoOptions() map[string]interface{} {
+	if s.Ref != nil {
+		return s.Ref.XProtoOptions
+	}
+	return s.Val.XProtoOptions
+}
+
+// UnmarshalYAML Implements the Unmarshaler interface of the yaml pkg.
+func (e *AdditionalProperties) UnmarshalYAML(value *yaml.Node) error {
 	*e = AdditionalProperties{}
-	e.IsBool = false
-	boolValue := false
-	err := unmarshal(&boolValue)
+	if value.Kind == yaml.ScalarNode && value.Tag == "!!bool" {
+		e.IsBool = true
+		return value.Decode(&e.BooleanValue)
+	}
+	schema := SchemaOrRef{}
+	err := value.Decode(&schema)
 	if err != nil {
-		schema := SchemaOrRef{}
-		err = unmarshal(&schema)
-		if err != nil {
-			fmt.Fprintln(os.Stderr, "error un marshalling schema")
-			return err
-		}
-		e.Schema = &schema
+		return err
 	}
-	e.BooleanValue = boolValue
-
+	e.Schema = &schema
 	return nil
 }
 
-// Implements the Unmarshaler interface of the yaml pkg.
+// MarshalYAML Implements the Marshaler interface of the yaml pkg.
 func (e *AdditionalProperties) MarshalYAML() (interface{}, error) {
 	if e.IsBool {
 		return e.BooleanValue, nil
@@ -781,152 +1043,173 @@ func (e *AdditionalProperties) MarshalYAML() (interface{}, error) {
 
 // UnMarshal : build OpenAPI struct form buffer
 func (oa *OpenAPI) UnMarshal(buffer []byte) (*OpenAPI, error) {
-	err := yaml.Unmarshal(buffer, oa)
+	err := oa.decode(buffer)
 	if err != nil {
-		log.Fatalf("Unmarshal: %v", err)
 		return nil, err
 	}
 	return oa, nil
 }
 
-// Load Charge le fichier de spec d'interface
+// Read loads the specification from a reader
 func (oa *OpenAPI) Read(file io.Reader) error {
 	yamlFile, err := ioutil.ReadAll(file)
 	if err != nil {
-		log.Printf("yamlFile.Get err   #%v ", err)
-		return err
-	}
-	err = yaml.Unmarshal(yamlFile, oa)
-	if err != nil {
-		log.Fatalf("Unmarshal: %v", err)
 		return err
 	}
-	return nil
+	return oa.decode(yamlFile)
 }
 
-// Load Charge le fichier de spec d'interface
+// Load loads the specification file
 func (oa *OpenAPI) Load(filename string) error {
 	yamlFile, err := ioutil.ReadFile(filename)
 	if err != nil {
-		log.Printf("yamlFile.Get err   #%v ", err)
-		return err
-	}
-	err = yaml.Unmarshal(yamlFile, oa)
-	if err != nil {
-		log.Fatalf("Unmarshal: %v", err)
 		return err
 	}
-	return nil
+	oa.file = filename
+	return oa.decode(yamlFile)
 }
 
-// Save dump la spec courante
-func (oa *OpenAPI) Save() {
-	buf, err := yaml.Marshal(oa)
+// decode builds the model from buffer, errors are reported as *ParseError
+func (oa *OpenAPI) decode(buffer []byte) error {
+	var root yaml.Node
+	err := yaml.Unmarshal(buffer, &root)
 	if err != nil {
-		log.Fatalf("Marshal: %v", err)
+		return newParseError(oa.file, nil, err)
 	}
-	fmt.Fprintf(os.Stdout, "%s", string(buf))
+	if isSwagger(&root) {
+		var sw swagger
+		err = root.Decode(&sw)
+		if err != nil {
+			return newParseError(oa.file, &root, err)
+		}
+		return oa.upgradeSwagger(&sw)
+	}
+	err = root.Decode(oa)
+	if err != nil {
+		return newParseError(oa.file, &root, err)
+	}
+	return nil
 }
 
-// $ref resolver
-func (oa *OpenAPI) makeSchemaRefIndex() map[string]refIndexElement {
-	refIndex := make(map[string]refIndexElement)
-	for k, v := range oa.Components.Schemas {
-		refIndex["#/components/schemas/"+k] = refIndexElement{k, v}
-		v.fillRefIndex("#/components/schemas/"+k, "types."+k, refIndex)
+// Schema returns the schema, following resolved $ref
+func (s *SchemaOrRef) Schema() *Schema {
+	if s.Ref != nil && s.Ref.Resolved != nil {
+		if target, ok := s.Ref.Resolved.(*SchemaOrRef); ok {
+			return target.Schema()
+		}
+		return nil
+	}
+	if s.Val != nil {
+		return s.Val
 	}
-	return refIndex
+	//log.Fatalf("unable to convert %s into valid schema", s.Ref.Ref)
+	return nil
 }
 
-// ResolveRefs
-func (oa *OpenAPI) ResolveRefs() {
-	refIndex := oa.makeSchemaRefIndex()
-	for _, v := range oa.Components.Schemas {
-		v.resolveRefs(refIndex)
+// Callback returns the Callback object, following resolved $ref
+func (e *CallbackOrRef) Callback() *Callback {
+	if e.Ref != nil && e.Ref.Resolved != nil {
+		if target, ok := e.Ref.Resolved.(*CallbackOrRef); ok {
+			return target.Callback()
+		}
+		return nil
 	}
+	return e.Val
 }
 
-func (s *SchemaOrRef) fillRefIndex(yPath string, path string, refIndex map[string]refIndexElement) {
-	if s.Ref != nil {
-		return
-	}
-	if s.Val.Properties != nil {
-		for p, v := range s.Val.Properties {
-			// add property to Index
-			refIndex[yPath+"/Properties/"+p] = refIndexElement{path + "." + p, v}
-			v.fillRefIndex(yPath+"/"+p, path+"."+p, refIndex)
+// Example returns the Example object, following resolved $ref
+func (e *ExampleOrRef) Example() *Example {
+	if e.Ref != nil && e.Ref.Resolved != nil {
+		if target, ok := e.Ref.Resolved.(*ExampleOrRef); ok {
+			return target.Example()
 		}
+		return nil
 	}
-	/* TODO Doit for
-	AllOf                []*SchemaOrRef          `yaml:"allOf,omitempty"`
-	OneOf                []*SchemaOrRef          `yaml:"oneOf,omitempty"`
-	AnyOf                []*SchemaOrRef          `yaml:"anyOf,omitempty"`
-	Items                *SchemaOrRef            `yaml:"items,omitempty"`
-	*/
-
+	return e.Val
 }
-func (s *SchemaOrRef) resolveRefs(refIndex map[string]refIndexElement) {
-	if s.Ref != nil {
-		log.Printf("Resolving %s ...\n", s.Ref.Ref)
-		if elem, ok := refIndex[s.Ref.Ref]; ok {
-			log.Printf("Resolving %s int %v\n", s.Ref.Ref, elem.schema)
-			s.Ref.Resolved = elem.schema
-			s.Ref.RefName = elem.name
-		} else {
-			log.Printf("Can't Resolve %s\n", s.Ref.Ref)
+
+// Header returns the Header object, following resolved $ref
+func (e *HeaderOrRef) Header() *Header {
+	if e.Ref != nil && e.Ref.Resolved != nil {
+		if target, ok := e.Ref.Resolved.(*HeaderOrRef); ok {
+			return target.Header()
 		}
-		return
+		return nil
 	}
+	return e.Val
+}
 
-	if s.Val.Properties != nil {
-		for p, v := range s.Val.Properties {
-			log.Printf("visit %s ...\n", p)
-			v.resolveRefs(refIndex)
+// Link returns the Link object, following resolved $ref
+func (e *LinkOrRef) Link() *Link {
+	if e.Ref != nil && e.Ref.Resolved != nil {
+		if target, ok := e.Ref.Resolved.(*LinkOrRef); ok {
+			return target.Link()
 		}
+		return nil
 	}
-	if s.Val.Items != nil {
-		s.Val.Items.resolveRefs(refIndex)
-	}
-	if s.Val.AllOf != nil {
-		for p, v := range s.Val.AllOf {
-			log.Printf("visit %d %v ...\n", p, v)
-			v.resolveRefs(refIndex)
+	return e.Val
+}
+
+// MediaType returns the MediaType object, following resolved $ref
+func (e *MediaTypeOrRef) MediaType() *MediaType {
+	if e.Ref != nil && e.Ref.Resolved != nil {
+		if target, ok := e.Ref.Resolved.(*MediaTypeOrRef); ok {
+			return target.MediaType()
 		}
+		return nil
 	}
-	if s.Val.OneOf != nil {
-		for p, v := range s.Val.OneOf {
-			log.Printf("visit %d %v ...\n", p, v)
-			v.resolveRefs(refIndex)
+	return e.Val
+}
+
+// Parameter returns the Parameter object, following resolved $ref
+func (e *ParameterOrRef) Parameter() *Parameter {
+	if e.Ref != nil && e.Ref.Resolved != nil {
+		if target, ok := e.Ref.Resolved.(*ParameterOrRef); ok {
+			return target.Parameter()
 		}
+		return nil
 	}
-	if s.Val.AnyOf != nil {
-		for p, v := range s.Val.OneOf {
-			log.Printf("visit %d %v ...\n", p, v)
-			v.resolveRefs(refIndex)
+	return e.Val
+}
+
+// RequestBody returns the RequestBody object, following resolved $ref
+func (e *RequestBodyOrRef) RequestBody() *RequestBody {
+	if e.Ref != nil && e.Ref.Resolved != nil {
+		if target, ok := e.Ref.Resolved.(*RequestBodyOrRef); ok {
+			return target.RequestBody()
 		}
+		return nil
 	}
-	if s.Val.AdditionalProperties != nil {
-		if s.Val.AdditionalProperties.Schema != nil {
-			s.Val.AdditionalProperties.Schema.resolveRefs(refIndex)
+	return e.Val
+}
+
+// Response returns the Response object, following resolved $ref
+func (e *ResponseOrRef) Response() *Response {
+	if e.Ref != nil && e.Ref.Resolved != nil {
+		if target, ok := e.Ref.Resolved.(*ResponseOrRef); ok {
+			return target.Response()
 		}
+		return nil
 	}
+	return e.Val
 }
-func (s *SchemaOrRef) Schema() *Schema {
-	if s.Ref != nil && s.Ref.Resolved != nil {
-		return s.Ref.Resolved.(*SchemaOrRef).Schema()
-	}
-	if s.Val != nil {
-		return s.Val
+
+// SecurityScheme returns the SecurityScheme object, following resolved $ref
+func (e *SecuritySchemeOrRef) SecurityScheme() *SecurityScheme {
+	if e.Ref != nil && e.Ref.Resolved != nil {
+		if target, ok := e.Ref.Resolved.(*SecuritySchemeOrRef); ok {
+			return target.SecurityScheme()
+		}
+		return nil
 	}
-	//log.Fatalf("unable to convert %s into valid schema", s.Ref.Ref)
-	return nil
+	return e.Val
 }
 
-// ResolveRefs
-func (oa *OpenAPI) ResolveRefsWithFilter(filter []string) map[string]*SchemaOrRef {
-	refIndex := oa.makeSchemaRefIndex()
-	for _, v := range oa.Components.Schemas {
-		v.resolveRefs(refIndex)
+// ResolveRefsWithFilter resolves references and returns filter components with all the schemas they depend on
+func (oa *OpenAPI) ResolveRefsWithFilter(filter []string) (map[string]*SchemaOrRef, error) {
+	err := oa.ResolveRefs()
+	if err != nil {
+		return nil, err
 	}
 	// create filtrered map
 	filteredComponents := make(map[string]*SchemaOrRef)
@@ -934,56 +1217,53 @@ func (oa *OpenAPI) ResolveRefsWithFilter(filter []string) map[string]*SchemaOrRe
 		if s, ok := oa.Components.Schemas[name]; ok {
 			if _, already := filteredComponents[name]; !already {
 				filteredComponents[name] = s
-				s.filterRefs(&filteredComponents)
+				err = s.filterRefs(filteredComponents)
+				if err != nil {
+					return nil, err
+				}
 			}
 		}
 	}
-	return filteredComponents
+	return filteredComponents, nil
 }
 
-func (s *SchemaOrRef) filterRefs(filteredComponents *map[string]*SchemaOrRef) {
+func (s *SchemaOrRef) filterRefs(filteredComponents map[string]*SchemaOrRef) error {
+	if s == nil {
+		return nil
+	}
 	if s.Ref != nil {
 		if s.Ref.External != "" {
 			// no following references needed (external case)
-			return
+			return nil
 		}
-		if s.Ref.Resolved == nil {
-			log.Fatalf("unresolved reference:", s.Ref.Ref)
+		target, ok := s.Ref.Resolved.(*SchemaOrRef)
+		if !ok {
+			return &UnresolvedRefError{Line: s.Ref.Line, Column: s.Ref.Column, Ref: s.Ref.Ref, Err: fmt.Errorf("not a schema")}
+		}
+		if _, already := filteredComponents[s.Ref.RefName]; already {
+			return nil
 		}
 		log.Printf("filtering : %s added", s.Ref.RefName)
-		(*filteredComponents)[s.Ref.RefName] = s
-		s.Ref.Resolved.(*SchemaOrRef).filterRefs(filteredComponents)
-		return
+		filteredComponents[s.Ref.RefName] = s
+		return target.filterRefs(filteredComponents)
 	}
 
-	if s.Val.Properties != nil {
-		for p, v := range s.Val.Properties {
-			log.Printf("visit %s ...\n", p)
-			v.filterRefs(filteredComponents)
-		}
-	}
-	if s.Val.Items != nil {
-		s.Val.Items.filterRefs(filteredComponents)
-	}
-	if s.Val.AllOf != nil {
-		for p, v := range s.Val.AllOf {
-			log.Printf("visit %d %v ...\n", p, v)
-			v.filterRefs(filteredComponents)
-		}
+	var children []*SchemaOrRef
+	for _, v := range s.Val.Properties {
+		children = append(children, v)
 	}
-	if s.Val.OneOf != nil {
-		for p, v := range s.Val.OneOf {
-			log.Printf("visit %d %v ...\n", p, v)
-			v.filterRefs(filteredComponents)
-		}
+	children = append(children, s.Val.Items)
+	children = append(children, s.Val.AllOf...)
+	children = append(children, s.Val.OneOf...)
+	children = append(children, s.Val.AnyOf...)
+	if s.Val.AdditionalProperties != nil {
+		children = append(children, s.Val.AdditionalProperties.Schema)
 	}
-	if s.Val.AnyOf != nil {
-		for p, v := range s.Val.OneOf {
-			log.Printf("visit %d %v ...\n", p, v)
-			v.filterRefs(filteredComponents)
+	for _, v := range children {
+		err := v.filterRefs(filteredComponents)
+		if err != nil {
+			return err
 		}
 	}
-	if s.Val.AdditionalProperties != nil {
-		s.Val.AdditionalProperties.Schema.filterRefs(filteredComponents)
-	}
+	return nil
 }
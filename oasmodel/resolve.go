@@ -0,0 +1,311 @@
+package oasmodel
+
+import (
+	"fmt"
+	"log"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// maxRefDepth limits the number of $ref followed while walking a single pointer
+const maxRefDepth = 32
+
+var refPtrType = reflect.TypeOf(&Ref{})
+
+// ParsePointer splits a RFC 6901 JSON Pointer into unescaped reference tokens.
+// "" designates the whole document, "/a~1b/c~0d" gives ["a/b", "c~d"].
+func ParsePointer(pointer string) ([]string, error) {
+	if pointer == "" {
+		return nil, nil
+	}
+	if pointer[0] != '/' {
+		return nil, fmt.Errorf("invalid JSON pointer %q: must start with '/'", pointer)
+	}
+	tokens := strings.Split(pointer[1:], "/")
+	for i, t := range tokens {
+		// ~1 MUST be transformed before ~0 (RFC 6901 section 4)
+		tokens[i] = strings.Replace(strings.Replace(t, "~1", "/", -1), "~0", "~", -1)
+	}
+	return tokens, nil
+}
+
+// EscapeToken escapes a reference token so it can be embedded in a JSON Pointer
+func EscapeToken(token string) string {
+	return strings.Replace(strings.Replace(token, "~", "~0", -1), "/", "~1", -1)
+}
+
+// splitRef splits a $ref value into its document part and its JSON Pointer tokens.
+// The fragment is URI encoded as stated by RFC 6901 section 6.
+func splitRef(ref string) (string, []string, error) {
+	document := ref
+	fragment := ""
+	if i := strings.Index(ref, "#"); i >= 0 {
+		document = ref[:i]
+		fragment = ref[i+1:]
+	}
+	fragment, err := url.PathUnescape(fragment)
+	if err != nil {
+		return "", nil, fmt.Errorf("invalid $ref %q: %v", ref, err)
+	}
+	tokens, err := ParsePointer(fragment)
+	if err != nil {
+		return "", nil, err
+	}
+	return document, tokens, nil
+}
+
+// followRef returns the target of a $ref met while walking a pointer
+type followRef func(ref *Ref) (interface{}, error)
+
+// Resolve returns the object designated by a local $ref (eg: "#/components/parameters/limit").
+// Result is a pointer to the model element : *SchemaOrRef, *ParameterOrRef, *PathItem...
+func (oa *OpenAPI) Resolve(ref string) (interface{}, error) {
+	return oa.resolve(ref, 0)
+}
+
+func (oa *OpenAPI) resolve(ref string, depth int) (interface{}, error) {
+	if depth > maxRefDepth {
+		return nil, fmt.Errorf("can't resolve %s: too many nested references", ref)
+	}
+	document, tokens, err := splitRef(ref)
+	if err != nil {
+		return nil, err
+	}
+	if document != "" {
+		return nil, fmt.Errorf("can't resolve %s: external document", ref)
+	}
+	target, err := oa.lookup(tokens, func(r *Ref) (interface{}, error) {
+		return oa.resolve(r.Ref, depth+1)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("can't resolve %s %v", ref, err)
+	}
+	// target may itself be a $ref
+	if inner := refOf(target); inner != nil && inner.Resolved == nil {
+		if _, err := oa.resolve(inner.Ref, depth+1); err != nil {
+			return nil, err
+		}
+	}
+	return target, nil
+}
+
+// lookup walks the document along tokens, unresolved $ref met on the way are given to follow
+func (oa *OpenAPI) lookup(tokens []string, follow followRef) (interface{}, error) {
+	var err error
+	v := reflect.ValueOf(oa)
+	for i, token := range tokens {
+		v, err = walk(v, token, follow)
+		if err != nil {
+			return nil, fmt.Errorf("at /%s: %v", strings.Join(tokens[:i+1], "/"), err)
+		}
+	}
+	target := pointerTo(v)
+	// a pointer to additionalProperties designates its schema
+	if ap, ok := target.(*AdditionalProperties); ok {
+		if ap.Schema == nil {
+			return nil, fmt.Errorf("additionalProperties is not a schema")
+		}
+		return ap.Schema, nil
+	}
+	return target, nil
+}
+
+// walk goes one token down from v
+func walk(v reflect.Value, token string, follow followRef) (reflect.Value, error) {
+	for {
+		switch v.Kind() {
+		case reflect.Ptr, reflect.Interface:
+			if v.IsNil() {
+				return v, fmt.Errorf("no such element %q", token)
+			}
+			v = v.Elem()
+			continue
+		case reflect.Struct:
+			if isOrRef(v.Type()) {
+				next, err := unwrap(v, follow)
+				if err != nil {
+					return v, err
+				}
+				v = next
+				continue
+			}
+			// additionalProperties is a boolean or a schema
+			if v.Type() == reflect.TypeOf(AdditionalProperties{}) {
+				v = v.FieldByName("Schema")
+				continue
+			}
+			return structField(v, token)
+		case reflect.Map:
+			if v.Type().Key().Kind() != reflect.String {
+				return v, fmt.Errorf("unsupported map key type %s", v.Type().Key())
+			}
+			elem := v.MapIndex(reflect.ValueOf(token).Convert(v.Type().Key()))
+			if !elem.IsValid() {
+				return v, fmt.Errorf("no such key %q", token)
+			}
+			return elem, nil
+		case reflect.Slice, reflect.Array:
+			i, err := strconv.Atoi(token)
+			if err != nil || i < 0 || i >= v.Len() || strconv.Itoa(i) != token {
+				return v, fmt.Errorf("invalid index %q", token)
+			}
+			return v.Index(i), nil
+		default:
+			return v, fmt.Errorf("can't walk into %s with %q", v.Kind(), token)
+		}
+	}
+}
+
+// unwrap returns the value of an xxxOrRef element, following $ref when needed
+func unwrap(v reflect.Value, follow followRef) (reflect.Value, error) {
+	if val := v.FieldByName("Val"); !val.IsNil() {
+		return val, nil
+	}
+	ref := v.FieldByName("Ref").Interface().(*Ref)
+	if ref == nil {
+		return v, fmt.Errorf("empty element")
+	}
+	if ref.Resolved != nil {
+		return reflect.ValueOf(ref.Resolved), nil
+	}
+	target, err := follow(ref)
+	if err != nil {
+		return v, err
+	}
+	return reflect.ValueOf(target), nil
+}
+
+// structField selects the field having token as yaml name
+func structField(v reflect.Value, token string) (reflect.Value, error) {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		name := strings.Split(t.Field(i).Tag.Get("yaml"), ",")[0]
+		if name == token && name != "-" {
+			return v.Field(i), nil
+		}
+	}
+	return v, fmt.Errorf("no such field %q in %s", token, t.Name())
+}
+
+// isOrRef return true for xxxOrRef types : struct { Ref *Ref; Val *xxx }
+func isOrRef(t reflect.Type) bool {
+	if t.Kind() != reflect.Struct || t.NumField() != 2 {
+		return false
+	}
+	ref, ok := t.FieldByName("Ref")
+	if !ok || ref.Type != refPtrType {
+		return false
+	}
+	_, ok = t.FieldByName("Val")
+	return ok
+}
+
+// refOf returns the $ref of a xxxOrRef element, nil if target is not a reference
+func refOf(target interface{}) *Ref {
+	v := reflect.ValueOf(target)
+	if v.Kind() != reflect.Ptr || v.IsNil() || !isOrRef(v.Elem().Type()) {
+		return nil
+	}
+	if !v.Elem().FieldByName("Val").IsNil() {
+		return nil
+	}
+	return v.Elem().FieldByName("Ref").Interface().(*Ref)
+}
+
+// pointerTo returns v as a pointer, map values are not addressable and are copied
+func pointerTo(v reflect.Value) interface{} {
+	if v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		return v.Interface()
+	}
+	if v.CanAddr() {
+		return v.Addr().Interface()
+	}
+	p := reflect.New(v.Type())
+	p.Elem().Set(v)
+	return p.Interface()
+}
+
+// walkRefs calls visit for each $ref of the document, with the JSON Pointer of its location
+func walkRefs(v reflect.Value, location string, visit func(ref *Ref, location string)) {
+	walkModel(v, location, func(v reflect.Value, location string) bool {
+		if v.Type() == refPtrType {
+			visit(v.Interface().(*Ref), location)
+			return false
+		}
+		return true
+	})
+}
+
+// walkModel calls visit for each non nil pointer of the model, with the JSON Pointer of its location.
+// Pointed values are walked while visit returns true, resolved $ref are not followed.
+func walkModel(v reflect.Value, location string, visit func(v reflect.Value, location string) bool) {
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if v.IsNil() {
+			return
+		}
+		if v.Kind() == reflect.Ptr && !visit(v, location) {
+			return
+		}
+		walkModel(v.Elem(), location, visit)
+	case reflect.Struct:
+		t := v.Type()
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			name := strings.Split(f.Tag.Get("yaml"), ",")[0]
+			if name == "-" || f.PkgPath != "" {
+				continue
+			}
+			// xxxOrRef and AdditionalProperties fields are not part of the pointer
+			if name == "" {
+				walkModel(v.Field(i), location, visit)
+				continue
+			}
+			walkModel(v.Field(i), location+"/"+EscapeToken(name), visit)
+		}
+	case reflect.Map:
+		if v.Type().Key().Kind() != reflect.String {
+			return
+		}
+		iter := v.MapRange()
+		for iter.Next() {
+			walkModel(iter.Value(), location+"/"+EscapeToken(iter.Key().String()), visit)
+		}
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			walkModel(v.Index(i), location+"/"+strconv.Itoa(i), visit)
+		}
+	}
+}
+
+// ResolveRefs resolves every local $ref of the document (schemas, parameters, responses...).
+// References to other documents are left to the Loader.
+func (oa *OpenAPI) ResolveRefs() error {
+	var err error
+	walkRefs(reflect.ValueOf(oa), "#", func(ref *Ref, location string) {
+		if err != nil || ref.Resolved != nil || ref.External != "" {
+			return
+		}
+		document, tokens, e := splitRef(ref.Ref)
+		if e != nil {
+			err = refError(oa.file, ref, location, e)
+			return
+		}
+		if document != "" {
+			return
+		}
+		log.Printf("Resolving %s (%s) ...\n", ref.Ref, location)
+		target, e := oa.Resolve(ref.Ref)
+		if e != nil {
+			err = refError(oa.file, ref, location, e)
+			return
+		}
+		ref.Resolved = target
+		if len(tokens) > 0 {
+			ref.RefName = tokens[len(tokens)-1]
+		}
+	})
+	return err
+}
@@ -0,0 +1,152 @@
+package oasmodel
+
+import (
+	"reflect"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+const resolveSpec = `openapi: 3.0.0
+info:
+    title: test resolve
+    version: 1.0.0
+paths:
+    /pets/{id}:
+        get:
+            parameters:
+                - $ref: '#/components/parameters/id'
+            requestBody:
+                $ref: '#/components/requestBodies/pet'
+            responses:
+                "200":
+                    $ref: '#/components/responses/pet'
+                default:
+                    description: error
+                    headers:
+                        X-Rate:
+                            $ref: '#/components/headers/rate'
+components:
+    schemas:
+        pet:
+            type: object
+            properties:
+                tags:
+                    type: array
+                    items:
+                        type: string
+                owner:
+                    allOf:
+                        - $ref: '#/components/schemas/person'
+        person:
+            type: object
+            properties:
+                name:
+                    type: string
+        a/b~c:
+            type: integer
+        alias:
+            $ref: '#/components/schemas/pet/properties/tags/items'
+        owned:
+            $ref: '#/components/schemas/pet/properties/owner/allOf/0/properties/name'
+        escaped:
+            $ref: '#/components/schemas/a~1b~0c'
+    parameters:
+        id:
+            name: id
+            in: path
+            required: true
+            schema:
+                type: string
+    responses:
+        pet:
+            description: a pet
+            content:
+                application/json:
+                    schema:
+                        $ref: '#/components/schemas/pet'
+    requestBodies:
+        pet:
+            content:
+                application/json:
+                    schema:
+                        $ref: '#/components/schemas/pet'
+    headers:
+        rate:
+            schema:
+                type: integer
+`
+
+func loadResolveSpec(t *testing.T) *OpenAPI {
+	var oa OpenAPI
+	err := yaml.Unmarshal([]byte(resolveSpec), &oa)
+	if err != nil {
+		t.Fatalf("error unmarshalling : %v", err)
+	}
+	oa.ResolveRefs()
+	return &oa
+}
+
+func TestParsePointer(t *testing.T) {
+	tests := map[string][]string{
+		"":                  nil,
+		"/":                 {""},
+		"/a~1b/c~0d":        {"a/b", "c~d"},
+		"/~01":              {"~1"},
+		"/components/x/0/y": {"components", "x", "0", "y"},
+	}
+	for pointer, expected := range tests {
+		tokens, err := ParsePointer(pointer)
+		if err != nil {
+			t.Errorf("%q: unexpected error %v", pointer, err)
+		}
+		if !reflect.DeepEqual(tokens, expected) {
+			t.Errorf("%q: got %v expected %v", pointer, tokens, expected)
+		}
+	}
+	if _, err := ParsePointer("a/b"); err == nil {
+		t.Errorf("pointer without leading '/' must be rejected")
+	}
+	if EscapeToken("a/b~c") != "a~1b~0c" {
+		t.Errorf("bad escaping : %s", EscapeToken("a/b~c"))
+	}
+}
+
+func TestResolveComponents(t *testing.T) {
+	oa := loadResolveSpec(t)
+	get := oa.Paths["/pets/{id}"].Get
+
+	if p := get.Parameters[0].Parameter(); p == nil || p.Name != "id" {
+		t.Errorf("parameter not resolved : %v", p)
+	}
+	if rb := get.RequestBody.RequestBody(); rb == nil || rb.Content["application/json"].Schema.Schema().TypeName() != "object" {
+		t.Errorf("request body not resolved : %v", rb)
+	}
+	if r := get.Responses["200"].Response(); r == nil || r.Description != "a pet" {
+		t.Errorf("response not resolved : %v", r)
+	}
+	if h := get.Responses["default"].Response().Headers["X-Rate"].Header(); h == nil || h.Schema.Schema().TypeName() != "integer" {
+		t.Errorf("header not resolved : %v", h)
+	}
+}
+
+func TestResolveDeepPointers(t *testing.T) {
+	oa := loadResolveSpec(t)
+	schemas := oa.Components.Schemas
+
+	if s := schemas["alias"].Schema(); s == nil || s.TypeName() != "string" {
+		t.Errorf("items pointer not resolved : %v", s)
+	}
+	if s := schemas["owned"].Schema(); s == nil || s.TypeName() != "string" {
+		t.Errorf("allOf pointer not resolved : %v", s)
+	}
+	if s := schemas["escaped"].Schema(); s == nil || s.TypeName() != "integer" {
+		t.Errorf("escaped pointer not resolved : %v", s)
+	}
+	if schemas["escaped"].Ref.RefName != "a/b~c" {
+		t.Errorf("bad RefName : %s", schemas["escaped"].Ref.RefName)
+	}
+	if _, err := oa.Resolve("#/components/schemas/unknown"); err == nil {
+		t.Errorf("unknown reference must fail")
+	}
+}
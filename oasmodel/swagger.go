@@ -0,0 +1,548 @@
+package oasmodel
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Swagger 2.0 documents are read into the swagger structures below, then upgraded to the OpenAPI 3.0 model.
+
+// swaggerVersion is the OpenAPI version given to upgraded documents
+const swaggerVersion = "3.0.3"
+
+// defaultMediaType is used when neither the operation nor the document declares consumes / produces
+const defaultMediaType = "application/json"
+
+/*
+swagger Swagger Object from Swagger 2.0
+swagger				string		REQUIRED. Specifies the Swagger Specification version being used. Value MUST be "2.0".
+host				string		The host (name or ip) serving the API.
+basePath			string		The base path on which the API is served, which is relative to the host.
+schemes				[string]	The transfer protocol of the API. Values MUST be from the list: "http", "https", "ws", "wss".
+consumes			[string]	A list of MIME types the APIs can consume.
+produces			[string]	A list of MIME types the APIs can produce.
+definitions			Definitions Object			An object to hold data types produced and consumed by operations.
+parameters			Parameters Definitions Object	An object to hold parameters that can be used across operations.
+responses			Responses Definitions Object	An object to hold responses that can be used across operations.
+securityDefinitions	Security Definitions Object		Security scheme definitions that can be used across the specification.
+*/
+type swagger struct {
+	Swagger             string                            `yaml:"swagger"`
+	Info                Info                              `yaml:"info"`
+	Host                string                            `yaml:"host,omitempty"`
+	BasePath            string                            `yaml:"basePath,omitempty"`
+	Schemes             []string                          `yaml:"schemes,omitempty"`
+	Consumes            []string                          `yaml:"consumes,omitempty"`
+	Produces            []string                          `yaml:"produces,omitempty"`
+	Paths               map[string]swaggerPathItem        `yaml:"paths"`
+	Definitions         map[string]*SchemaOrRef           `yaml:"definitions,omitempty"`
+	Parameters          map[string]*swaggerParameter      `yaml:"parameters,omitempty"`
+	Responses           map[string]*swaggerResponse       `yaml:"responses,omitempty"`
+	SecurityDefinitions map[string]*swaggerSecurityScheme `yaml:"securityDefinitions,omitempty"`
+	Security            []SecurityReq                     `yaml:"security,omitempty"`
+	Tags                []Tag                             `yaml:"tags,omitempty"`
+	ExternalDocs        ExternalDocs                      `yaml:"externalDocs,omitempty"`
+	XWsRPC              map[string]XwsRPCService          `yaml:"x-ws-rpc,omitempty"`
+}
+
+type swaggerPathItem struct {
+	Ref        string              `yaml:"$ref,omitempty"`
+	Get        *swaggerOperation   `yaml:"get,omitempty"`
+	Put        *swaggerOperation   `yaml:"put,omitempty"`
+	Post       *swaggerOperation   `yaml:"post,omitempty"`
+	Delete     *swaggerOperation   `yaml:"delete,omitempty"`
+	Options    *swaggerOperation   `yaml:"options,omitempty"`
+	Head       *swaggerOperation   `yaml:"head,omitempty"`
+	Patch      *swaggerOperation   `yaml:"patch,omitempty"`
+	Parameters []*swaggerParameter `yaml:"parameters,omitempty"`
+}
+
+type swaggerOperation struct {
+	Tags         []string                    `yaml:"tags,omitempty"`
+	Summary      string                      `yaml:"summary,omitempty"`
+	Description  string                      `yaml:"description,omitempty"`
+	ExternalDocs *ExternalDocs               `yaml:"externalDocs,omitempty"`
+	OperationID  string                      `yaml:"operationId,omitempty"`
+	Consumes     []string                    `yaml:"consumes,omitempty"`
+	Produces     []string                    `yaml:"produces,omitempty"`
+	Parameters   []*swaggerParameter         `yaml:"parameters,omitempty"`
+	Responses    map[string]*swaggerResponse `yaml:"responses"`
+	Schemes      []string                    `yaml:"schemes,omitempty"`
+	Deprecated   bool                        `yaml:"deprecated,omitempty"`
+	Security     []SecurityReq               `yaml:"security,omitempty"`
+	XWsRPC       string                      `yaml:"x-ws-rpc,omitempty"`
+}
+
+// swaggerItems holds the type and validations of non body parameters, headers and array items
+type swaggerItems struct {
+	Type             string        `yaml:"type,omitempty"`
+	Format           string        `yaml:"format,omitempty"`
+	Items            *swaggerItems `yaml:"items,omitempty"`
+	CollectionFormat string        `yaml:"collectionFormat,omitempty"`
+	Default          interface{}   `yaml:"default,omitempty"`
+	Maximum          *float64      `yaml:"maximum,omitempty"`
+	ExclusiveMaximum bool          `yaml:"exclusiveMaximum,omitempty"`
+	Minimum          *float64      `yaml:"minimum,omitempty"`
+	ExclusiveMinimum bool          `yaml:"exclusiveMinimum,omitempty"`
+	MaxLength        int           `yaml:"maxLength,omitempty"`
+	MinLength        int           `yaml:"minLength,omitempty"`
+	Pattern          string        `yaml:"pattern,omitempty"`
+	MaxItems         int           `yaml:"maxItems,omitempty"`
+	MinItems         int           `yaml:"minItems,omitempty"`
+	UniqueItems      bool          `yaml:"uniqueItems,omitempty"`
+	Enum             []string      `yaml:"enum,omitempty"`
+	MultipleOf       float64       `yaml:"multipleOf,omitempty"`
+}
+
+/*
+swaggerParameter Parameter Object from Swagger 2.0
+in : "query", "header", "path", "formData" or "body".
+body parameters hold a schema, other parameters hold type, format, items...
+*/
+type swaggerParameter struct {
+	Ref             string       `yaml:"$ref,omitempty"`
+	Name            string       `yaml:"name"`
+	IN              string       `yaml:"in"`
+	Description     string       `yaml:"description,omitempty"`
+	Required        bool         `yaml:"required,omitempty"`
+	Schema          *SchemaOrRef `yaml:"schema,omitempty"`
+	AllowEmptyValue bool         `yaml:"allowEmptyValue,omitempty"`
+	swaggerItems    `yaml:",inline"`
+}
+
+type swaggerHeader struct {
+	Description  string `yaml:"description,omitempty"`
+	swaggerItems `yaml:",inline"`
+}
+
+type swaggerResponse struct {
+	Ref         string                   `yaml:"$ref,omitempty"`
+	Description string                   `yaml:"description"`
+	Schema      *SchemaOrRef             `yaml:"schema,omitempty"`
+	Headers     map[string]swaggerHeader `yaml:"headers,omitempty"`
+	Examples    map[string]interface{}   `yaml:"examples,omitempty"`
+}
+
+/*
+swaggerSecurityScheme Security Scheme Object from Swagger 2.0
+type : "basic", "apiKey" or "oauth2"
+flow : "implicit", "password", "application" or "accessCode"
+*/
+type swaggerSecurityScheme struct {
+	Type             string            `yaml:"type"`
+	Description      string            `yaml:"description,omitempty"`
+	Name             string            `yaml:"name,omitempty"`
+	IN               string            `yaml:"in,omitempty"`
+	Flow             string            `yaml:"flow,omitempty"`
+	AuthorizationURL string            `yaml:"authorizationUrl,omitempty"`
+	TokenURL         string            `yaml:"tokenUrl,omitempty"`
+	Scopes           map[string]string `yaml:"scopes,omitempty"`
+}
+
+// isSwagger returns true if the document root holds the swagger field
+func isSwagger(root *yaml.Node) bool {
+	if root.Kind == yaml.DocumentNode && len(root.Content) > 0 {
+		root = root.Content[0]
+	}
+	if root.Kind != yaml.MappingNode {
+		return false
+	}
+	for i := 0; i+1 < len(root.Content); i += 2 {
+		if root.Content[i].Value == "swagger" {
+			return true
+		}
+	}
+	return false
+}
+
+// upgradeSwagger fills oa with the OpenAPI 3.0 equivalent of a Swagger 2.0 document
+func (oa *OpenAPI) upgradeSwagger(sw *swagger) error {
+	if sw.Swagger != "2.0" {
+		return fmt.Errorf("unsupported swagger version %q", sw.Swagger)
+	}
+	oa.Openapi = swaggerVersion
+	oa.Info = sw.Info
+	oa.Servers = sw.servers()
+	oa.Security = sw.Security
+	oa.Tags = sw.Tags
+	oa.ExternalDocs = sw.ExternalDocs
+	oa.XWsRPC = sw.XWsRPC
+
+	// components
+	oa.Components.Schemas = sw.Definitions
+	for name, p := range sw.Parameters {
+		switch p.IN {
+		case "body":
+			if oa.Components.RequestBodies == nil {
+				oa.Components.RequestBodies = make(map[string]*RequestBodyOrRef)
+			}
+			oa.Components.RequestBodies[name] = &RequestBodyOrRef{Val: bodyParameter(p, sw.Consumes)}
+		case "formData":
+			// form parameters are merged into each operation request body
+		default:
+			if oa.Components.Parameters == nil {
+				oa.Components.Parameters = make(map[string]*ParameterOrRef)
+			}
+			oa.Components.Parameters[name] = &ParameterOrRef{Val: p.parameter()}
+		}
+	}
+	for name, r := range sw.Responses {
+		if oa.Components.Responses == nil {
+			oa.Components.Responses = make(map[string]*ResponseOrRef)
+		}
+		oa.Components.Responses[name] = sw.response(r, sw.Produces)
+	}
+	for name, s := range sw.SecurityDefinitions {
+		if oa.Components.SecuritySchemes == nil {
+			oa.Components.SecuritySchemes = make(map[string]*SecuritySchemeOrRef)
+		}
+		oa.Components.SecuritySchemes[name] = &SecuritySchemeOrRef{Val: s.securityScheme()}
+	}
+
+	// paths
+	oa.Paths = make(map[string]PathItem)
+	for path, item := range sw.Paths {
+		upgraded, err := sw.pathItem(&item)
+		if err != nil {
+			return fmt.Errorf("path %s: %v", path, err)
+		}
+		oa.Paths[path] = upgraded
+	}
+
+	// remaining references : #/definitions/x, #/parameters/x, #/responses/x
+	walkRefs(reflect.ValueOf(oa), "#", func(ref *Ref, location string) {
+		ref.Ref = upgradeRef(ref.Ref)
+	})
+	return nil
+}
+
+// servers builds server urls from schemes, host and basePath
+func (sw *swagger) servers() []Server {
+	if sw.Host == "" {
+		if sw.BasePath == "" {
+			return nil
+		}
+		return []Server{{URL: sw.BasePath}}
+	}
+	if len(sw.Schemes) == 0 {
+		// scheme of the document itself
+		return []Server{{URL: "//" + sw.Host + sw.BasePath}}
+	}
+	servers := make([]Server, 0, len(sw.Schemes))
+	for _, scheme := range sw.Schemes {
+		servers = append(servers, Server{URL: scheme + "://" + sw.Host + sw.BasePath})
+	}
+	return servers
+}
+
+func (sw *swagger) pathItem(item *swaggerPathItem) (PathItem, error) {
+	upgraded := PathItem{Ref: upgradeRef(item.Ref)}
+	var common []*swaggerParameter
+	for _, p := range item.Parameters {
+		param, err := sw.lookupParameter(p)
+		if err != nil {
+			return upgraded, err
+		}
+		// body and form parameters are moved to each operation
+		if param.IN == "body" || param.IN == "formData" {
+			common = append(common, p)
+			continue
+		}
+		upgraded.Parameters = append(upgraded.Parameters, *sw.parameterOrRef(p))
+	}
+
+	ops := []struct {
+		from *swaggerOperation
+		to   **Operation
+	}{
+		{item.Get, &upgraded.Get}, {item.Put, &upgraded.Put}, {item.Post, &upgraded.Post}, {item.Delete, &upgraded.Delete},
+		{item.Options, &upgraded.Options}, {item.Head, &upgraded.Head}, {item.Patch, &upgraded.Patch},
+	}
+	for _, op := range ops {
+		if op.from == nil {
+			continue
+		}
+		o, err := sw.operation(op.from, common)
+		if err != nil {
+			return upgraded, err
+		}
+		*op.to = o
+	}
+	return upgraded, nil
+}
+
+func (sw *swagger) operation(op *swaggerOperation, common []*swaggerParameter) (*Operation, error) {
+	upgraded := Operation{
+		Tags:         op.Tags,
+		Summary:      op.Summary,
+		Description:  op.Description,
+		ExternalDocs: op.ExternalDocs,
+		OperationID:  op.OperationID,
+		Deprecated:   op.Deprecated,
+		Security:     op.Security,
+		XWsRPC:       op.XWsRPC,
+		Responses:    make(Responses),
+	}
+	consumes := op.Consumes
+	if len(consumes) == 0 {
+		consumes = sw.Consumes
+	}
+	produces := op.Produces
+	if len(produces) == 0 {
+		produces = sw.Produces
+	}
+
+	var form []*swaggerParameter
+	for _, p := range append(common, op.Parameters...) {
+		param, err := sw.lookupParameter(p)
+		if err != nil {
+			return nil, err
+		}
+		switch param.IN {
+		case "body":
+			if p.Ref != "" {
+				upgraded.RequestBody = &RequestBodyOrRef{Ref: &Ref{Ref: upgradeBodyRef(p.Ref)}}
+			} else {
+				upgraded.RequestBody = &RequestBodyOrRef{Val: bodyParameter(param, consumes)}
+			}
+		case "formData":
+			form = append(form, param)
+		default:
+			upgraded.Parameters = append(upgraded.Parameters, sw.parameterOrRef(p))
+		}
+	}
+	if len(form) > 0 {
+		upgraded.RequestBody = &RequestBodyOrRef{Val: formBody(form, consumes)}
+	}
+
+	for code, r := range op.Responses {
+		upgraded.Responses[code] = sw.response(r, produces)
+	}
+	return &upgraded, nil
+}
+
+// lookupParameter returns the parameter definition, following references to global parameters
+func (sw *swagger) lookupParameter(p *swaggerParameter) (*swaggerParameter, error) {
+	if p.Ref == "" {
+		return p, nil
+	}
+	const prefix = "#/parameters/"
+	if !strings.HasPrefix(p.Ref, prefix) {
+		// external parameters can't be body or form parameters
+		return &swaggerParameter{Ref: p.Ref}, nil
+	}
+	param, ok := sw.Parameters[strings.TrimPrefix(p.Ref, prefix)]
+	if !ok {
+		return nil, fmt.Errorf("unknown parameter %s", p.Ref)
+	}
+	return param, nil
+}
+
+func (sw *swagger) parameterOrRef(p *swaggerParameter) *ParameterOrRef {
+	if p.Ref != "" {
+		return &ParameterOrRef{Ref: &Ref{Ref: upgradeRef(p.Ref)}}
+	}
+	return &ParameterOrRef{Val: p.parameter()}
+}
+
+func (p *swaggerParameter) parameter() *Parameter {
+	param := Parameter{
+		Name:            p.Name,
+		IN:              p.IN,
+		Description:     p.Description,
+		Required:        p.Required,
+		AllowEmptyValue: p.AllowEmptyValue,
+		Schema:          &SchemaOrRef{Val: p.swaggerItems.schema()},
+	}
+	param.Style, param.Explode = collectionStyle(p.IN, p.Type, p.CollectionFormat)
+	return &param
+}
+
+// collectionStyle converts collectionFormat into style and explode
+func collectionStyle(in, typ, collectionFormat string) (string, *bool) {
+	if typ != "array" {
+		return "", nil
+	}
+	explode := false
+	switch collectionFormat {
+	case "multi":
+		explode = true
+		return "form", &explode
+	case "ssv":
+		return "spaceDelimited", &explode
+	case "pipes":
+		return "pipeDelimited", &explode
+	case "tsv":
+		// no equivalent in OpenAPI 3
+		return "", nil
+	}
+	// csv (default)
+	if in == "query" || in == "cookie" {
+		return "form", &explode
+	}
+	return "simple", nil
+}
+
+func bodyParameter(p *swaggerParameter, consumes []string) *RequestBody {
+	body := RequestBody{Description: p.Description, Required: p.Required, Content: make(map[string]MediaType)}
+	for _, mime := range mediaTypes(consumes) {
+		body.Content[mime] = MediaType{Schema: p.Schema}
+	}
+	return &body
+}
+
+// formBody merges formData parameters into a single object schema
+func formBody(params []*swaggerParameter, consumes []string) *RequestBody {
+	schema := Schema{Type: SchemaType{"object"}, Properties: make(map[string]*SchemaOrRef)}
+	mime := "application/x-www-form-urlencoded"
+	for _, p := range params {
+		schema.Properties[p.Name] = &SchemaOrRef{Val: p.swaggerItems.schema()}
+		if p.Required {
+			schema.Required = append(schema.Required, p.Name)
+		}
+		if p.Type == "file" {
+			mime = "multipart/form-data"
+		}
+	}
+	for _, c := range consumes {
+		if c == "multipart/form-data" {
+			mime = c
+		}
+	}
+	body := RequestBody{Content: map[string]MediaType{mime: {Schema: &SchemaOrRef{Val: &schema}}}}
+	for _, p := range params {
+		body.Required = body.Required || p.Required
+	}
+	return &body
+}
+
+func (sw *swagger) response(r *swaggerResponse, produces []string) *ResponseOrRef {
+	if r.Ref != "" {
+		return &ResponseOrRef{Ref: &Ref{Ref: upgradeRef(r.Ref)}}
+	}
+	upgraded := Response{Description: r.Description}
+	if r.Schema != nil {
+		upgraded.Content = make(map[string]*MediaTypeOrRef)
+		for _, mime := range mediaTypes(produces) {
+			media := MediaType{Schema: r.Schema}
+			if example, ok := r.Examples[mime].(map[string]interface{}); ok {
+				media.Example = example
+			}
+			upgraded.Content[mime] = &MediaTypeOrRef{Val: &media}
+		}
+	}
+	for name, h := range r.Headers {
+		if upgraded.Headers == nil {
+			upgraded.Headers = make(map[string]*HeaderOrRef)
+		}
+		header := Header{Description: h.Description, Schema: &SchemaOrRef{Val: h.swaggerItems.schema()}}
+		upgraded.Headers[name] = &HeaderOrRef{Val: &header}
+	}
+	return &ResponseOrRef{Val: &upgraded}
+}
+
+func (s *swaggerSecurityScheme) securityScheme() *SecurityScheme {
+	upgraded := SecurityScheme{Type: s.Type, Description: s.Description}
+	switch s.Type {
+	case "basic":
+		upgraded.Type = "http"
+		upgraded.Scheme = "basic"
+	case "apiKey":
+		upgraded.Name = s.Name
+		upgraded.IN = s.IN
+	case "oauth2":
+		flow := OAuth{AuthorizationURL: s.AuthorizationURL, TokenURL: s.TokenURL, Scopes: s.Scopes}
+		if flow.Scopes == nil {
+			flow.Scopes = map[string]string{}
+		}
+		switch s.Flow {
+		case "implicit":
+			upgraded.Flows.Implicit = &flow
+		case "password":
+			upgraded.Flows.Password = &flow
+		case "application":
+			upgraded.Flows.ClientCredentials = &flow
+		case "accessCode":
+			upgraded.Flows.AuthorizationCode = &flow
+		}
+	}
+	return &upgraded
+}
+
+// schema converts type, format and validations into a Schema
+func (i *swaggerItems) schema() *Schema {
+	s := Schema{
+		Format:      i.Format,
+		Maximum:     i.Maximum,
+		Minimum:     i.Minimum,
+		MaxLength:   i.MaxLength,
+		MinLength:   i.MinLength,
+		Pattern:     i.Pattern,
+		MaxItems:    i.MaxItems,
+		MinItems:    i.MinItems,
+		UniqueItems: i.UniqueItems,
+		Enum:        i.Enum,
+		MultipleOf:  i.MultipleOf,
+	}
+	if i.Type != "" {
+		s.Type = SchemaType{i.Type}
+	}
+	// files are binary strings
+	if i.Type == "file" {
+		s.Type = SchemaType{"string"}
+		s.Format = "binary"
+	}
+	if i.ExclusiveMaximum {
+		s.ExclusiveMaximum = &ExclusiveBound{IsBool: true, BooleanValue: true}
+	}
+	if i.ExclusiveMinimum {
+		s.ExclusiveMinimum = &ExclusiveBound{IsBool: true, BooleanValue: true}
+	}
+	if i.Default != nil {
+		s.Default = fmt.Sprint(i.Default)
+	}
+	if i.Items != nil {
+		s.Items = &SchemaOrRef{Val: i.Items.schema()}
+	}
+	return &s
+}
+
+// mediaTypes returns consumes / produces in a stable order, defaulting to application/json
+func mediaTypes(mimes []string) []string {
+	if len(mimes) == 0 {
+		return []string{defaultMediaType}
+	}
+	sorted := append([]string(nil), mimes...)
+	sort.Strings(sorted)
+	return sorted
+}
+
+// swaggerRefs maps Swagger 2.0 JSON Pointer prefixes to their OpenAPI 3 equivalents
+var swaggerRefs = []struct{ from, to string }{
+	{"#/definitions/", "#/components/schemas/"},
+	{"#/parameters/", "#/components/parameters/"},
+	{"#/responses/", "#/components/responses/"},
+}
+
+// upgradeRef rewrites a Swagger 2.0 reference, local or external
+func upgradeRef(ref string) string {
+	i := strings.Index(ref, "#")
+	if i < 0 {
+		return ref
+	}
+	for _, r := range swaggerRefs {
+		if strings.HasPrefix(ref[i:], r.from) {
+			return ref[:i] + r.to + strings.TrimPrefix(ref[i:], r.from)
+		}
+	}
+	return ref
+}
+
+// upgradeBodyRef rewrites a reference to a body parameter into a request body reference
+func upgradeBodyRef(ref string) string {
+	return strings.Replace(ref, "#/parameters/", "#/components/requestBodies/", 1)
+}
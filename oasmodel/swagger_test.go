@@ -0,0 +1,87 @@
+package oasmodel
+
+import (
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+func TestSwaggerUpgrade(t *testing.T) {
+	oa, err := NewLoader().Load("assets/swagger2/petstore.yaml")
+	if err != nil {
+		t.Fatalf("error loading swagger 2.0 spec : %v", err)
+	}
+	if oa.Openapi != "3.0.3" {
+		t.Errorf("bad openapi version : %s", oa.Openapi)
+	}
+	if len(oa.Servers) != 1 || oa.Servers[0].URL != "https://petstore.example.com/v1" {
+		t.Errorf("bad servers : %v", oa.Servers)
+	}
+
+	pet := oa.Components.Schemas["Pet"].Schema()
+	if pet == nil || pet.Discriminator == nil || pet.Discriminator.PropertyName != "kind" {
+		t.Fatalf("definitions not converted : %v", pet)
+	}
+	if owner := pet.Properties["owner"]; owner.Ref.Ref != "#/components/schemas/Person" || owner.Schema() == nil {
+		t.Errorf("definition reference not rewritten : %s", owner.Ref.Ref)
+	}
+
+	list := oa.Paths["/pets"].Get
+	limit := list.Parameters[0]
+	if limit.Ref.Ref != "#/components/parameters/limit" || limit.Parameter().Schema.Schema().TypeName() != "integer" {
+		t.Errorf("parameter reference not rewritten : %s", limit.Ref.Ref)
+	}
+	if max, exclusive, ok := limit.Parameter().Schema.Schema().UpperBound(); !ok || !exclusive || max != 100 {
+		t.Errorf("bad parameter bound : %v %v %v", max, exclusive, ok)
+	}
+	tags := list.Parameters[1].Parameter()
+	if tags.Style != "form" || tags.Explode == nil || !*tags.Explode || tags.Schema.Schema().Items.Schema().TypeName() != "string" {
+		t.Errorf("bad array parameter : %v", tags)
+	}
+	ok := list.Responses["200"].Response()
+	if ok.Content["application/json"].MediaType().Schema.Schema().Items.Schema() != pet {
+		t.Errorf("response schema not converted")
+	}
+	if ok.Headers["X-Next"].Header().Schema.Schema().TypeName() != "string" {
+		t.Errorf("response header not converted")
+	}
+	if e := list.Responses["default"].Response(); e == nil || e.Description != "unexpected error" {
+		t.Errorf("response reference not rewritten : %v", e)
+	}
+
+	body := oa.Paths["/pets"].Post.RequestBody
+	if body.Ref.Ref != "#/components/requestBodies/pet" || !body.RequestBody().Required ||
+		body.RequestBody().Content["application/json"].Schema.Schema() != pet {
+		t.Errorf("body parameter not converted : %v", body.Ref.Ref)
+	}
+
+	photo := oa.Paths["/pets/{id}/photo"]
+	if len(photo.Parameters) != 1 || photo.Parameters[0].Parameter().Schema.Schema().Format != "int64" {
+		t.Errorf("path parameters not converted : %v", photo.Parameters)
+	}
+	form, found := photo.Post.RequestBody.RequestBody().Content["multipart/form-data"]
+	if !found {
+		t.Fatalf("form parameters not converted")
+	}
+	file := form.Schema.Schema().Properties["file"].Schema()
+	if file.TypeName() != "string" || file.Format != "binary" || form.Schema.Schema().Required[0] != "file" {
+		t.Errorf("bad file parameter : %v", file)
+	}
+
+	if s := oa.Components.SecuritySchemes["basic"].SecurityScheme(); s.Type != "http" || s.Scheme != "basic" {
+		t.Errorf("bad basic security scheme : %v", s)
+	}
+	if s := oa.Components.SecuritySchemes["oauth"].SecurityScheme(); s.Flows.AuthorizationCode == nil {
+		t.Errorf("bad oauth2 security scheme : %v", s)
+	}
+
+	// the upgraded document is written as OpenAPI 3
+	buf, err := yaml.Marshal(oa)
+	if err != nil {
+		t.Fatalf("error marshalling : %v", err)
+	}
+	if strings.Contains(string(buf), "#/definitions/") || !strings.Contains(string(buf), "openapi: 3.0.3") {
+		t.Errorf("bad upgraded document :\n%s", buf)
+	}
+}
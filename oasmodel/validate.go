@@ -0,0 +1,134 @@
+package oasmodel
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// Severity of a Diagnostic
+type Severity int
+
+// Severities, from the most to the least severe
+const (
+	SeverityError Severity = iota
+	SeverityWarning
+)
+
+func (s Severity) String() string {
+	if s == SeverityError {
+		return "error"
+	}
+	return "warning"
+}
+
+// MarshalText gives the severity name in JSON reports
+func (s Severity) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
+
+// Diagnostic is a problem found in a document by Validate
+type Diagnostic struct {
+	Severity Severity `json:"severity"`
+	File     string   `json:"file,omitempty"`
+	Pointer  string   `json:"pointer"` // JSON Pointer of the faulty element
+	Msg      string   `json:"message"`
+}
+
+func (d Diagnostic) String() string {
+	return position(d.File, 0, 0) + d.Severity.String() + ": " + d.Msg + " (at " + d.Pointer + ")"
+}
+
+var schemaPtrType = reflect.TypeOf(&Schema{})
+
+// Validate checks the document against rules the model can't enforce by itself.
+// Diagnostics are sorted by location.
+func (oa *OpenAPI) Validate() []Diagnostic {
+	var diags []Diagnostic
+	report := func(severity Severity, pointer string, format string, args ...interface{}) {
+		diags = append(diags, Diagnostic{Severity: severity, File: oa.file, Pointer: pointer, Msg: fmt.Sprintf(format, args...)})
+	}
+
+	if oa.Openapi == "" {
+		report(SeverityError, "#/openapi", "missing openapi version")
+	}
+	if oa.Info.Title == "" {
+		report(SeverityError, "#/info/title", "missing info title")
+	}
+	if oa.Info.Version == "" {
+		report(SeverityError, "#/info/version", "missing info version")
+	}
+	oa.validatePaths(report)
+
+	walkModel(reflect.ValueOf(oa), "#", func(v reflect.Value, location string) bool {
+		if v.Type() == refPtrType {
+			return false
+		}
+		if v.Type() == schemaPtrType {
+			oa.validateSchema(v.Interface().(*Schema), location, report)
+		}
+		return true
+	})
+
+	sort.SliceStable(diags, func(i, j int) bool {
+		return diags[i].Pointer < diags[j].Pointer
+	})
+	return diags
+}
+
+type reportFunc func(severity Severity, pointer string, format string, args ...interface{})
+
+func (oa *OpenAPI) validatePaths(report reportFunc) {
+	paths := make([]string, 0, len(oa.Paths))
+	for path := range oa.Paths {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	// operationId -> location of its first use
+	operations := make(map[string]string)
+	for _, path := range paths {
+		location := "#/paths/" + EscapeToken(path)
+		if !strings.HasPrefix(path, "/") {
+			report(SeverityError, location, "path %q must begin with a slash", path)
+		}
+		item := oa.Paths[path]
+		for _, op := range item.Operations() {
+			if op.Operation.OperationID == "" {
+				continue
+			}
+			opLocation := location + "/" + op.Method + "/operationId"
+			if first, found := operations[op.Operation.OperationID]; found {
+				report(SeverityError, opLocation, "duplicate operationId %q, already used at %s", op.Operation.OperationID, first)
+				continue
+			}
+			operations[op.Operation.OperationID] = opLocation
+		}
+	}
+}
+
+func (oa *OpenAPI) validateSchema(s *Schema, location string, report reportFunc) {
+	if s.ReadOnly && s.WriteOnly {
+		report(SeverityError, location, "schema is both readOnly and writeOnly")
+	}
+
+	if s.TypeName() == "array" && s.Items == nil && len(s.PrefixItems) == 0 {
+		// items is optional in JSON Schema 2020-12
+		if oa.IsOAS31() {
+			report(SeverityWarning, location, "array without items")
+		} else {
+			report(SeverityError, location, "array without items")
+		}
+	}
+
+	// properties may be declared by subschemas, constraint only schemas (then, not...) have none
+	if len(s.Properties) == 0 || len(s.AllOf) > 0 || len(s.OneOf) > 0 || len(s.AnyOf) > 0 {
+		return
+	}
+	for i, name := range s.Required {
+		if _, found := s.Properties[name]; !found {
+			report(SeverityError, fmt.Sprintf("%s/required/%d", location, i), "required property %q is not defined", name)
+		}
+	}
+}
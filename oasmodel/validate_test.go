@@ -0,0 +1,77 @@
+package oasmodel
+
+import (
+	"testing"
+)
+
+const invalidSpec = `openapi: 3.0.0
+info:
+    version: "1"
+paths:
+    pets:
+        get:
+            operationId: list
+    /pets:
+        get:
+            operationId: list
+components:
+    schemas:
+        pet:
+            type: object
+            required: [name, age]
+            properties:
+                name:
+                    type: string
+                tags:
+                    type: array
+                    readOnly: true
+                    writeOnly: true
+        composed:
+            required: [name]
+            allOf:
+                - $ref: '#/components/schemas/pet'
+`
+
+func TestValidate(t *testing.T) {
+	var oa OpenAPI
+	if _, err := oa.UnMarshal([]byte(invalidSpec)); err != nil {
+		t.Fatalf("error unmarshalling : %v", err)
+	}
+	expected := []Diagnostic{
+		{SeverityError, "", "#/components/schemas/pet/properties/tags", "schema is both readOnly and writeOnly"},
+		{SeverityError, "", "#/components/schemas/pet/properties/tags", "array without items"},
+		{SeverityError, "", "#/components/schemas/pet/required/1", `required property "age" is not defined`},
+		{SeverityError, "", "#/info/title", "missing info title"},
+		{SeverityError, "", "#/paths/pets", `path "pets" must begin with a slash`},
+		{SeverityError, "", "#/paths/pets/get/operationId", `duplicate operationId "list", already used at #/paths/~1pets/get/operationId`},
+	}
+	diags := oa.Validate()
+	if len(diags) != len(expected) {
+		t.Fatalf("expected %d diagnostics, got %v", len(expected), diags)
+	}
+	for i := range expected {
+		if diags[i] != expected[i] {
+			t.Errorf("got %v expected %v", diags[i], expected[i])
+		}
+	}
+
+	// items is optional with OAS 3.1
+	oa.Openapi = "3.1.0"
+	for _, d := range oa.Validate() {
+		if d.Msg == "array without items" && d.Severity != SeverityWarning {
+			t.Errorf("array without items must be a warning in 3.1 : %v", d)
+		}
+	}
+}
+
+func TestValidateAssets(t *testing.T) {
+	for _, asset := range []string{"assets/lux-openapi.yaml", "assets/oas31-jsonschema.yaml", "assets/swagger2/petstore.yaml"} {
+		var oa OpenAPI
+		if err := oa.Load(asset); err != nil {
+			t.Fatalf("error loading %s : %v", asset, err)
+		}
+		for _, d := range oa.Validate() {
+			t.Errorf("unexpected diagnostic %v", d)
+		}
+	}
+}
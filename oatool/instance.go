@@ -0,0 +1,171 @@
+package oatool
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"strings"
+
+	"github.com/Axili39/oastools/oasmodel"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// instanceValue converts a message decoded from binary to the generic value of its schema, as if it was read from json :
+// properties are named after json names, unpopulated scalars are zero values, integers are numbers,
+// string enums are their spec values and oneofs are the property of their message.
+// schema may be nil, constants of enums are then named as in .proto.
+func instanceValue(m protoreflect.Message, schema *oasmodel.Schema) interface{} {
+	md := m.Descriptor()
+	if md.FullName().Parent() == "google.protobuf" {
+		return wellKnownValue(m)
+	}
+	oneofs := md.Oneofs()
+	// oneOf components are messages holding a oneof select
+	if oneofs.Len() == 1 && oneofs.Get(0).Name() == "select" && schema != nil && schema.OneOf != nil {
+		return oneofValue(m, oneofs.Get(0), schema)
+	}
+	value := make(map[string]interface{})
+	for i := 0; i < oneofs.Len(); i++ {
+		o := oneofs.Get(i)
+		if o.IsSynthetic() {
+			continue
+		}
+		name := string(o.Name())
+		prop := property(schema, func(p string) bool { return strings.Replace(p, "-", "_", -1) == name })
+		if prop != nil && m.WhichOneof(o) != nil {
+			value[prop.name] = oneofValue(m, o, prop.schema)
+		}
+	}
+	fields := md.Fields()
+	for i := 0; i < fields.Len(); i++ {
+		fd := fields.Get(i)
+		if o := fd.ContainingOneof(); o != nil && !o.IsSynthetic() {
+			continue
+		}
+		// fields without presence are always set
+		if fd.HasPresence() && !m.Has(fd) {
+			continue
+		}
+		name := fd.JSONName()
+		var propSchema *oasmodel.Schema
+		if prop := property(schema, func(p string) bool { return p == name }); prop != nil {
+			propSchema = prop.schema
+		}
+		value[name] = fieldValue(fd, m.Get(fd), propSchema)
+	}
+	return value
+}
+
+// oneofValue : value of the member set, the alternative of the schema at the index of the member
+func oneofValue(m protoreflect.Message, o protoreflect.OneofDescriptor, schema *oasmodel.Schema) interface{} {
+	member := m.WhichOneof(o)
+	if member == nil {
+		return nil
+	}
+	var alternative *oasmodel.Schema
+	if schema != nil && member.Index() < len(schema.OneOf) {
+		alternative = schema.OneOf[member.Index()].Schema()
+	}
+	return fieldValue(member, m.Get(member), alternative)
+}
+
+type namedSchema struct {
+	name   string
+	schema *oasmodel.Schema
+}
+
+// property looks up a property of schema and of the schemas it is composed of
+func property(schema *oasmodel.Schema, match func(string) bool) *namedSchema {
+	if schema == nil {
+		return nil
+	}
+	for name, prop := range schema.Properties {
+		if match(name) {
+			return &namedSchema{name, prop.Schema()}
+		}
+	}
+	for _, sub := range schema.AllOf {
+		if prop := property(sub.Schema(), match); prop != nil {
+			return prop
+		}
+	}
+	return nil
+}
+
+func fieldValue(fd protoreflect.FieldDescriptor, v protoreflect.Value, schema *oasmodel.Schema) interface{} {
+	switch {
+	case fd.IsList():
+		var items *oasmodel.Schema
+		if schema != nil && schema.Items != nil {
+			items = schema.Items.Schema()
+		}
+		list := v.List()
+		values := make([]interface{}, list.Len())
+		for i := range values {
+			values[i] = singularValue(fd, list.Get(i), items)
+		}
+		return values
+	case fd.IsMap():
+		var elem *oasmodel.Schema
+		if schema != nil && schema.AdditionalProperties != nil && schema.AdditionalProperties.Schema != nil {
+			elem = schema.AdditionalProperties.Schema.Schema()
+		}
+		values := make(map[string]interface{})
+		v.Map().Range(func(k protoreflect.MapKey, e protoreflect.Value) bool {
+			values[k.String()] = singularValue(fd.MapValue(), e, elem)
+			return true
+		})
+		return values
+	}
+	return singularValue(fd, v, schema)
+}
+
+func singularValue(fd protoreflect.FieldDescriptor, v protoreflect.Value, schema *oasmodel.Schema) interface{} {
+	switch fd.Kind() {
+	case protoreflect.MessageKind, protoreflect.GroupKind:
+		return instanceValue(v.Message(), schema)
+	case protoreflect.EnumKind:
+		return enumValue(fd.Enum(), v.Enum(), schema)
+	case protoreflect.BytesKind:
+		return base64.StdEncoding.EncodeToString(v.Bytes())
+	}
+	return v.Interface()
+}
+
+// enumValue : string enums are numbered in the order of their values, integer enums keep their value
+func enumValue(ed protoreflect.EnumDescriptor, n protoreflect.EnumNumber, schema *oasmodel.Schema) interface{} {
+	if ed.FullName() == "google.protobuf.NullValue" {
+		return nil
+	}
+	if schema != nil && len(schema.Enum) > 0 {
+		if schema.TypeName() == "integer" {
+			return int64(n)
+		}
+		if n >= 0 && int(n) < len(schema.Enum) {
+			return schema.Enum[n]
+		}
+	}
+	if value := ed.Values().ByNumber(n); value != nil {
+		return string(value.Name())
+	}
+	return int64(n)
+}
+
+// wellKnownValue : json mapping of well-known types, wrappers are their value
+func wellKnownValue(m protoreflect.Message) interface{} {
+	md := m.Descriptor()
+	if strings.HasSuffix(string(md.Name()), "Value") && md.Name() != "Value" && md.Name() != "ListValue" {
+		if fd := md.Fields().ByName("value"); fd != nil {
+			return singularValue(fd, m.Get(fd), nil)
+		}
+	}
+	data, err := protojson.Marshal(m.Interface())
+	if err != nil {
+		return nil
+	}
+	var value interface{}
+	if err := json.Unmarshal(data, &value); err != nil {
+		return nil
+	}
+	return value
+}
@@ -6,11 +6,14 @@ package oatool
 import (
 	"flag"
 	"fmt"
+	"io/ioutil"
 	"os"
 	"strings"
 
 	"github.com/Axili39/encodingtools"
+	"github.com/Axili39/oastools/oasmodel"
 	"google.golang.org/protobuf/proto"
+	"gopkg.in/yaml.v3"
 )
 
 // MainObjTool generic main function for tool generated by oatoolgen
@@ -19,35 +22,40 @@ func MainObjTool(obj proto.Message) {
 	var output = flag.String("of", "", "<file>.json|yaml|bin")
 	var toformat = flag.String("tofmt", "", "json|yaml|bin force output format")
 
-	var out []byte
-	var format string
-	var outfile *os.File
 	// CommandLine parsing
 	flag.Parse()
 
-	// TODO : if no input, just make a minimal data file
-	err := encodingtools.Load(*input, obj)
+	err := Convert(*input, *output, *toformat, obj)
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "error Loading file: %v\n", err)
+		fmt.Fprintf(os.Stderr, "%v\n", err)
 		os.Exit(1)
 	}
+}
+
+// Convert loads input, a .json/.yaml/.bin file, into obj and writes it to output in format json, yaml or bin.
+// output "" is stdout, format "" is given by the output extension, unknown formats are binary.
+func Convert(input string, output string, format string, obj proto.Message) error {
+	var outfile *os.File
+	// TODO : if no input, just make a minimal data file
+	err := encodingtools.Load(input, obj)
+	if err != nil {
+		return fmt.Errorf("error Loading file: %v", err)
+	}
 
-	format = *toformat
 	// output format depend on extension
-	if *output == "" {
+	if output == "" {
 		// output to stdout
 		outfile = os.Stdout
 	} else {
 		// if no format chosen => try to choose one regarding output extension
 		if format == "" {
-			sl := strings.Split(*output, ".")
+			sl := strings.Split(output, ".")
 			format = sl[len(sl)-1]
 		}
 		// Open file
-		var err error
-		outfile, err = os.Create(*output)
+		outfile, err = os.Create(output)
 		if err != nil {
-			fmt.Fprintf(os.Stderr, "error %v\n", err)
+			return fmt.Errorf("error %v", err)
 		}
 		defer outfile.Close()
 	}
@@ -59,16 +67,37 @@ func MainObjTool(obj proto.Message) {
 	}
 
 	// convert object to output format
-	out, err = encodingtools.Objet2Bytes(obj, encodingtools.EncodingTypeFromString(format))
-
+	out, err := encodingtools.Objet2Bytes(obj, encodingtools.EncodingTypeFromString(format))
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "error Marshalling: %v\n", err)
-		os.Exit(1)
+		return fmt.Errorf("error Marshalling: %v", err)
 	}
 
 	_, err = outfile.Write(out)
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "error saving file: %v\n", err)
-		os.Exit(1)
+		return fmt.Errorf("error saving file: %v", err)
+	}
+	return nil
+}
+
+// LoadInstance loads a data file as a generic value, suitable for validate.Validate.
+// .json/.yaml/.yml files are decoded as is, binary files are decoded into obj first then converted along schema.
+func LoadInstance(filename string, obj proto.Message, schema *oasmodel.SchemaOrRef) (interface{}, error) {
+	if encodingtools.EncodingTypeFromString(filename[strings.LastIndex(filename, ".")+1:]) == encodingtools.EncodingTypeBinaryPB {
+		err := encodingtools.Load(filename, obj)
+		if err != nil {
+			return nil, err
+		}
+		var s *oasmodel.Schema
+		if schema != nil {
+			s = schema.Schema()
+		}
+		return instanceValue(obj.ProtoReflect(), s), nil
+	}
+	data, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return nil, err
 	}
+	var value interface{}
+	err = yaml.Unmarshal(data, &value)
+	return value, err
 }
@@ -0,0 +1,123 @@
+package oatool
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/Axili39/encodingtools"
+	"github.com/Axili39/oastools/oasmodel"
+	"github.com/Axili39/oastools/protobuf"
+	"github.com/Axili39/oastools/validate"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestConvert(t *testing.T) {
+	mt, err := protobuf.MessageType("../protobuf/tests/maps.yaml", "catalog", protobuf.GenerationOptions{})
+	if err != nil {
+		t.Fatalf("error building catalog : %v", err)
+	}
+	directory := t.TempDir()
+	input := filepath.Join(directory, "catalog.json")
+	err = ioutil.WriteFile(input, []byte(`{"name": "shop", "counts": {"fruits": 3}, "labels": {"a": {"text": "apple"}}}`), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// json -> bin -> yaml
+	binary := filepath.Join(directory, "catalog.bin")
+	err = Convert(input, binary, "", mt.New().Interface())
+	if err != nil {
+		t.Fatalf("error converting to binary : %v", err)
+	}
+	yaml := filepath.Join(directory, "catalog.yaml")
+	err = Convert(binary, yaml, "", mt.New().Interface())
+	if err != nil {
+		t.Fatalf("error converting to yaml : %v", err)
+	}
+
+	expected := mt.New().Interface()
+	err = encodingtools.Load(input, expected)
+	if err != nil {
+		t.Fatal(err)
+	}
+	result := mt.New().Interface()
+	err = encodingtools.Load(yaml, result)
+	if err != nil {
+		t.Fatalf("error loading yaml : %v", err)
+	}
+	if !proto.Equal(expected, result) {
+		t.Errorf("conversion changed %v to %v", expected, result)
+	}
+}
+
+const instanceSpec = `openapi: 3.0.0
+info:
+    title: instance
+    version: 1.0.0
+paths: {}
+components:
+    schemas:
+        request:
+            type: object
+            required: [count, content-type, size]
+            properties:
+                count:
+                    type: integer
+                    format: int32
+                size:
+                    type: integer
+                    format: int64
+                    minimum: 1
+                content-type:
+                    type: string
+                    enum: [application/json, text/plain]
+                level:
+                    type: integer
+                    enum: [1, 5]
+                tags:
+                    type: array
+                    items:
+                        type: string
+`
+
+func TestLoadInstanceBinary(t *testing.T) {
+	directory := t.TempDir()
+	specFile := filepath.Join(directory, "spec.yaml")
+	err := ioutil.WriteFile(specFile, []byte(instanceSpec), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	oa, err := oasmodel.NewLoader().Load(specFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	mt, err := protobuf.MessageType(specFile, "request", protobuf.GenerationOptions{})
+	if err != nil {
+		t.Fatalf("error building request : %v", err)
+	}
+	input := filepath.Join(directory, "request.json")
+	err = ioutil.WriteFile(input, []byte(`{"count": 0, "size": "12345678901", "content-type": "application_json", "level": 5}`), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	binary := filepath.Join(directory, "request.bin")
+	err = Convert(input, binary, "", mt.New().Interface())
+	if err != nil {
+		t.Fatalf("error converting to binary : %v", err)
+	}
+
+	schema := oa.Components.Schemas["request"]
+	value, err := LoadInstance(binary, mt.New().Interface(), schema)
+	if err != nil {
+		t.Fatalf("error loading binary : %v", err)
+	}
+	// names, zero values, integers and enums are the ones of the spec
+	expected := map[string]interface{}{"count": int32(0), "size": int64(12345678901), "content-type": "application/json", "level": int64(5), "tags": []interface{}{}}
+	if !reflect.DeepEqual(value, expected) {
+		t.Errorf("got %#v expected %#v", value, expected)
+	}
+	if violations := validate.Validate(schema, value); len(violations) > 0 {
+		t.Errorf("unexpected violations %v", violations)
+	}
+}
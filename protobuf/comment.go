@@ -0,0 +1,134 @@
+package protobuf
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/Axili39/oastools/oasmodel"
+)
+
+// commentWidth : comment lines are wrapped after this number of characters
+const commentWidth = 100
+
+// leadingComments formats text as the leading comments of a source code location, as protoc does :
+// each line starts with a space and ends with a new line, "" if text is empty
+func leadingComments(text string) string {
+	text = strings.TrimSpace(strings.Replace(text, "\r", "", -1))
+	if text == "" {
+		return ""
+	}
+	var b strings.Builder
+	for _, line := range strings.Split(text, "\n") {
+		for _, wrapped := range wrap(strings.TrimRight(line, " \t"), commentWidth) {
+			if wrapped != "" {
+				b.WriteString(" " + wrapped)
+			}
+			b.WriteString("\n")
+		}
+	}
+	return b.String()
+}
+
+// wrap splits a line on spaces, words longer than width are kept whole
+func wrap(line string, width int) []string {
+	var lines []string
+	for len([]rune(line)) > width {
+		runes := []rune(line)
+		cut := strings.LastIndex(string(runes[:width+1]), " ")
+		if cut <= 0 {
+			cut = strings.Index(line, " ")
+			if cut < 0 {
+				break
+			}
+		}
+		lines = append(lines, strings.TrimRight(line[:cut], " "))
+		line = strings.TrimLeft(line[cut:], " ")
+	}
+	return append(lines, line)
+}
+
+// schemaComment documents a type or a field : title, description, deprecation, constraints and example
+func schemaComment(schema *oasmodel.Schema) string {
+	var lines []string
+	if schema.Title != "" && schema.Title != schema.Description {
+		lines = append(lines, schema.Title)
+	}
+	if schema.Description != "" {
+		lines = append(lines, schema.Description)
+	}
+	if schema.Deprecated {
+		lines = append(lines, "Deprecated.")
+	}
+	if c := constraints(schema); len(c) > 0 {
+		lines = append(lines, "Constraints: "+strings.Join(c, ", "))
+	}
+	if schema.Example != nil {
+		lines = append(lines, "Example: "+exampleString(schema.Example))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// propertyComment documents a field, properties defined by reference have only their own description
+func propertyComment(prop *oasmodel.SchemaOrRef) string {
+	if prop.Ref != nil || prop.Val == nil {
+		return prop.Description()
+	}
+	return schemaComment(prop.Val)
+}
+
+func constraints(schema *oasmodel.Schema) []string {
+	var list []string
+	if value, exclusive, ok := schema.LowerBound(); ok {
+		list = append(list, comparison(">", exclusive)+formatNumber(value))
+	}
+	if value, exclusive, ok := schema.UpperBound(); ok {
+		list = append(list, comparison("<", exclusive)+formatNumber(value))
+	}
+	if schema.MultipleOf != 0 {
+		list = append(list, "multipleOf "+formatNumber(schema.MultipleOf))
+	}
+	if schema.MinLength != 0 {
+		list = append(list, fmt.Sprintf("minLength %d", schema.MinLength))
+	}
+	if schema.MaxLength != 0 {
+		list = append(list, fmt.Sprintf("maxLength %d", schema.MaxLength))
+	}
+	if schema.Pattern != "" {
+		list = append(list, "pattern "+schema.Pattern)
+	}
+	if schema.MinItems != 0 {
+		list = append(list, fmt.Sprintf("minItems %d", schema.MinItems))
+	}
+	if schema.MaxItems != 0 {
+		list = append(list, fmt.Sprintf("maxItems %d", schema.MaxItems))
+	}
+	if schema.UniqueItems {
+		list = append(list, "uniqueItems")
+	}
+	return list
+}
+
+func comparison(operator string, exclusive bool) string {
+	if exclusive {
+		return operator + " "
+	}
+	return operator + "= "
+}
+
+func formatNumber(value float64) string {
+	return strconv.FormatFloat(value, 'g', -1, 64)
+}
+
+// exampleString gives examples as JSON
+func exampleString(example interface{}) string {
+	if s, ok := example.(string); ok {
+		return s
+	}
+	data, err := json.Marshal(example)
+	if err != nil {
+		return fmt.Sprint(example)
+	}
+	return string(data)
+}
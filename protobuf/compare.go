@@ -0,0 +1,207 @@
+package protobuf
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Change is a wire incompatible difference between two generations
+type Change struct {
+	Type  string // full name of the message or enum, eg: Parent.Nested
+	Field string // field or enum value, "" for the type itself
+	Msg   string
+}
+
+func (c Change) String() string {
+	if c.Field == "" {
+		return c.Type + " : " + c.Msg
+	}
+	return c.Type + "." + c.Field + " : " + c.Msg
+}
+
+// Compare reports the changes between old and new generations that break decoding of data encoded with old :
+// renumbered fields, incompatible type changes, repeated / singular changes, removed enum values and moved types.
+func Compare(old []ProtoType, new []ProtoType) []Change {
+	var changes []Change
+	report := func(typename string, field string, format string, args ...interface{}) {
+		changes = append(changes, Change{typename, field, fmt.Sprintf(format, args...)})
+	}
+
+	oldTypes := indexTypes(old)
+	newTypes := indexTypes(new)
+	// full names of new types by simple name, to detect moved types
+	moved := make(map[string][]string)
+	for _, name := range newTypes.order {
+		simple := name[strings.LastIndex(name, ".")+1:]
+		moved[simple] = append(moved[simple], name)
+	}
+
+	for _, name := range oldTypes.order {
+		oldType := oldTypes.types[name]
+		newType, found := newTypes.types[name]
+		if !found {
+			if to := moved[name[strings.LastIndex(name, ".")+1:]]; len(to) > 0 {
+				report(name, "", "moved to %s", strings.Join(to, ", "))
+			} else {
+				report(name, "", "removed")
+			}
+			continue
+		}
+		switch o := oldType.(type) {
+		case *Enum:
+			n, ok := newType.(*Enum)
+			if !ok {
+				report(name, "", "enum changed to message")
+				continue
+			}
+			compareEnums(name, o, n, report)
+		default:
+			if _, ok := newType.(*Enum); ok {
+				report(name, "", "message changed to enum")
+				continue
+			}
+			compareFields(name, fieldsOf(oldType), fieldsOf(newType), reservedOf(newType), report)
+		}
+	}
+	return changes
+}
+
+// typeIndex : messages and enums by full name, in declaration order
+type typeIndex struct {
+	order []string
+	types map[string]ProtoType
+}
+
+func indexTypes(list []ProtoType) typeIndex {
+	index := typeIndex{types: make(map[string]ProtoType)}
+	var add func(prefix string, t ProtoType)
+	add = func(prefix string, t ProtoType) {
+		switch t.(type) {
+		case *Message, *Enum:
+		default:
+			return
+		}
+		name := prefix + normalizeName(t.Name())
+		if _, found := index.types[name]; !found {
+			index.order = append(index.order, name)
+		}
+		index.types[name] = t
+		if m, ok := t.(*Message); ok {
+			for _, nested := range m.nested {
+				add(name+".", nested)
+			}
+		}
+	}
+	for _, t := range list {
+		add("", t)
+	}
+	return index
+}
+
+// fieldsOf gives the fields of a message, oneof members included
+func fieldsOf(t ProtoType) []MessageMembers {
+	m, ok := t.(*Message)
+	if !ok {
+		return nil
+	}
+	var fields []MessageMembers
+	for _, f := range m.body {
+		if f.oneof == nil {
+			fields = append(fields, f)
+		}
+		fields = append(fields, f.oneof...)
+	}
+	return fields
+}
+
+func reservedOf(t ProtoType) []int {
+	if m, ok := t.(*Message); ok {
+		return m.reserved
+	}
+	return nil
+}
+
+func compareFields(typename string, old []MessageMembers, new []MessageMembers, reserved []int, report reportFunc) {
+	byName := make(map[string]*MessageMembers)
+	byNumber := make(map[int]*MessageMembers)
+	for i := range new {
+		byName[new[i].name] = &new[i]
+		byNumber[new[i].number] = &new[i]
+	}
+	isReserved := make(map[int]bool)
+	for _, n := range reserved {
+		isReserved[n] = true
+	}
+
+	for i := range old {
+		o := &old[i]
+		n, found := byName[o.name]
+		if found && n.number != o.number {
+			report(typename, o.name, "renumbered from %d to %d", o.number, n.number)
+			continue
+		}
+		if !found {
+			// renamed field keep its number
+			n, found = byNumber[o.number]
+		}
+		if !found {
+			if !isReserved[o.number] {
+				report(typename, o.name, "removed without reserving number %d", o.number)
+			}
+			continue
+		}
+		if o.repeated != n.repeated {
+			report(typename, o.name, "changed from %s to %s", cardinality(o.repeated), cardinality(n.repeated))
+		}
+		if wireType(o.typedecl) != wireType(n.typedecl) {
+			report(typename, o.name, "type changed from %s to %s", normalizeName(o.typedecl.Name()), normalizeName(n.typedecl.Name()))
+		}
+	}
+}
+
+func cardinality(repeated bool) string {
+	if repeated {
+		return "repeated"
+	}
+	return "singular"
+}
+
+// wireType gives a key equal for types that can be decoded from one another
+func wireType(t ProtoType) string {
+	if _, ok := t.(*Enum); ok {
+		return "varint"
+	}
+	switch name := normalizeName(t.Name()); name {
+	case "int32", "int64", "uint32", "uint64", "bool":
+		return "varint"
+	case "sint32", "sint64":
+		return "zigzag"
+	case "fixed32", "sfixed32":
+		return "fixed32"
+	case "fixed64", "sfixed64":
+		return "fixed64"
+	case "string", "bytes":
+		return "bytes"
+	default:
+		return name
+	}
+}
+
+func compareEnums(typename string, old *Enum, new *Enum, report reportFunc) {
+	numbers := make(map[string]int)
+	for _, v := range new.values {
+		numbers[v.name] = v.number
+	}
+	for _, v := range old.values {
+		n, found := numbers[v.name]
+		if !found {
+			report(typename, v.name, "enum value removed")
+			continue
+		}
+		if n != v.number {
+			report(typename, v.name, "enum value renumbered from %d to %d", v.number, n)
+		}
+	}
+}
+
+type reportFunc func(typename string, field string, format string, args ...interface{})
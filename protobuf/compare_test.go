@@ -0,0 +1,155 @@
+package protobuf
+
+import (
+	"testing"
+
+	"github.com/Axili39/oastools/oasmodel"
+)
+
+func createTypes(t *testing.T, spec string, lock *Lock) []ProtoType {
+	t.Helper()
+	oa := oasmodel.OpenAPI{}
+	if _, err := oa.UnMarshal([]byte(spec)); err != nil {
+		t.Fatalf("error loading spec : %v", err)
+	}
+	types, _, err := CreateTypes(&oa, GenerationOptions{Imports: map[string]bool{}, Lock: lock}, nil)
+	if err != nil {
+		t.Fatalf("error generating : %v", err)
+	}
+	return types
+}
+
+func TestCompare(t *testing.T) {
+	old := createTypes(t, `openapi: 3.0.0
+info:
+  title: compare
+  version: 1.0.0
+components:
+  schemas:
+    status:
+      type: string
+      enum: [on, off, unknown]
+    foo:
+      type: object
+      x-properties-order: [id, name, tags, count, state, sub]
+      properties:
+        id:
+          type: integer
+        name:
+          type: string
+        tags:
+          type: array
+          items:
+            type: string
+        count:
+          type: integer
+          format: int64
+        state:
+          $ref: '#/components/schemas/status'
+        sub:
+          type: object
+          properties:
+            level:
+              type: string
+              enum: [low, high]
+`, nil)
+	new := createTypes(t, `openapi: 3.0.0
+info:
+  title: compare
+  version: 1.0.0
+components:
+  schemas:
+    status:
+      type: string
+      enum: [off, on]
+    foo:
+      type: object
+      x-properties-order: [name, id, tags, count, state]
+      properties:
+        id:
+          type: string
+        name:
+          type: string
+        tags:
+          type: string
+        count:
+          type: integer
+        state:
+          $ref: '#/components/schemas/status'
+    level_:
+      type: string
+      enum: [low, high]
+`, nil)
+
+	expected := []string{
+		"foo.id : renumbered from 1 to 2",
+		"foo.name : renumbered from 2 to 1",
+		"foo.tags : changed from repeated to singular",
+		"foo.sub : removed without reserving number 6",
+		"foo.sub_ : removed",
+		"foo.sub_.level_ : moved to level_",
+		"status.on : enum value renumbered from 0 to 1",
+		"status.off : enum value renumbered from 1 to 0",
+		"status.unknown : enum value removed",
+	}
+	// count int32 -> int64 and state enum are compatible
+	changes := Compare(old, new)
+	got := make([]string, len(changes))
+	for i := range changes {
+		got[i] = changes[i].String()
+	}
+	if len(got) != len(expected) {
+		t.Fatalf("got %d changes, expected %d :\n%q", len(got), len(expected), got)
+	}
+	for i := range expected {
+		if got[i] != expected[i] {
+			t.Errorf("change %d : got %q, expected %q", i, got[i], expected[i])
+		}
+	}
+
+	if changes := Compare(old, old); len(changes) != 0 {
+		t.Errorf("expected no change, got %v", changes)
+	}
+}
+
+func TestCompareLock(t *testing.T) {
+	v1 := `openapi: 3.0.0
+info:
+  title: compare
+  version: 1.0.0
+components:
+  schemas:
+    foo:
+      type: object
+      properties:
+        b:
+          type: string
+        c:
+          type: integer
+`
+	v2 := `openapi: 3.0.0
+info:
+  title: compare
+  version: 1.0.0
+components:
+  schemas:
+    foo:
+      type: object
+      properties:
+        a:
+          type: boolean
+        c:
+          type: integer
+`
+	// without lock, a takes the number of b
+	if changes := Compare(createTypes(t, v1, nil), createTypes(t, v2, nil)); len(changes) != 1 || changes[0].String() != "foo.b : type changed from string to bool" {
+		t.Errorf("unexpected changes %v", changes)
+	}
+
+	// with lock, b is reserved
+	lock := NewLock()
+	old := createTypes(t, v1, lock)
+	if changes := Compare(old, createTypes(t, v2, lock)); len(changes) != 0 {
+		t.Errorf("expected no change, got %v", changes)
+	}
+}
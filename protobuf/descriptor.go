@@ -0,0 +1,389 @@
+package protobuf
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+// scalarTypes : descriptor types of proto scalars
+var scalarTypes = map[string]descriptorpb.FieldDescriptorProto_Type{
+	"double":   descriptorpb.FieldDescriptorProto_TYPE_DOUBLE,
+	"float":    descriptorpb.FieldDescriptorProto_TYPE_FLOAT,
+	"int64":    descriptorpb.FieldDescriptorProto_TYPE_INT64,
+	"uint64":   descriptorpb.FieldDescriptorProto_TYPE_UINT64,
+	"int32":    descriptorpb.FieldDescriptorProto_TYPE_INT32,
+	"fixed64":  descriptorpb.FieldDescriptorProto_TYPE_FIXED64,
+	"fixed32":  descriptorpb.FieldDescriptorProto_TYPE_FIXED32,
+	"bool":     descriptorpb.FieldDescriptorProto_TYPE_BOOL,
+	"string":   descriptorpb.FieldDescriptorProto_TYPE_STRING,
+	"bytes":    descriptorpb.FieldDescriptorProto_TYPE_BYTES,
+	"uint32":   descriptorpb.FieldDescriptorProto_TYPE_UINT32,
+	"sfixed32": descriptorpb.FieldDescriptorProto_TYPE_SFIXED32,
+	"sfixed64": descriptorpb.FieldDescriptorProto_TYPE_SFIXED64,
+	"sint32":   descriptorpb.FieldDescriptorProto_TYPE_SINT32,
+	"sint64":   descriptorpb.FieldDescriptorProto_TYPE_SINT64,
+}
+
+// source code info paths : field numbers of descriptor.proto
+const (
+	fileMessages      = 4
+	fileEnums         = 5
+	fileServices      = 6
+	fileExtensions    = 7
+	messageFields     = 2
+	messageNested     = 3
+	messageEnums      = 4
+	messageOneofs     = 8
+	messageExtensions = 6
+	enumValues        = 2
+	serviceMethods    = 2
+)
+
+// fileBuilder builds a file descriptor, comments are recorded in its source code info.
+// Type names are fully-qualified, as in descriptors built by protoc.
+type fileBuilder struct {
+	file  *descriptorpb.FileDescriptorProto
+	scope string               // package, with a leading dot
+	names map[ProtoType]string // full names of messages and enums declared in the file
+}
+
+// newFile : descriptor of a proto3 file declaring nodes and services, options are name=value file options
+func newFile(name string, packageName string, imports map[string]bool, options []string, nodes []ProtoType, services []ProtoType) (*descriptorpb.FileDescriptorProto, error) {
+	file := &descriptorpb.FileDescriptorProto{Syntax: proto.String("proto3"), SourceCodeInfo: &descriptorpb.SourceCodeInfo{}}
+	if name != "" {
+		file.Name = proto.String(name)
+	}
+	if packageName != "" {
+		file.Package = proto.String(packageName)
+	}
+	for i := range imports {
+		file.Dependency = append(file.Dependency, i+".proto")
+	}
+	sort.Strings(file.Dependency)
+	for _, o := range options {
+		pair := strings.SplitN(o, "=", 2)
+		if len(pair) != 2 {
+			return nil, fmt.Errorf("bad option %s, expected name=value", o)
+		}
+		if file.Options == nil {
+			file.Options = &descriptorpb.FileOptions{}
+		}
+		setOption(file.Options, Option{strings.TrimSpace(pair[0]), strings.TrimSpace(pair[1])})
+	}
+
+	b := fileBuilder{file: file, names: make(map[ProtoType]string)}
+	if packageName != "" {
+		b.scope = "." + packageName
+	}
+	for _, node := range nodes {
+		b.register(node, b.scope)
+	}
+	for _, node := range nodes {
+		switch t := node.(type) {
+		case *Message:
+			file.MessageType = append(file.MessageType, b.message(t, path(nil, fileMessages, len(file.MessageType))))
+		case *Enum:
+			file.EnumType = append(file.EnumType, b.enum(t, path(nil, fileEnums, len(file.EnumType))))
+		case *Extension:
+			for i := range t.fields {
+				field := b.field(&t.fields[i], path(nil, fileExtensions, len(file.Extension)), nil, b.scope)
+				field.Extendee = proto.String("." + t.extendee)
+				file.Extension = append(file.Extension, field)
+			}
+		}
+	}
+	for _, s := range services {
+		if t, ok := s.(*Service); ok {
+			file.Service = append(file.Service, b.service(t, path(nil, fileServices, len(file.Service))))
+		}
+	}
+
+	if len(file.SourceCodeInfo.Location) == 0 {
+		file.SourceCodeInfo = nil
+	} else {
+		setSpans(file)
+	}
+	return file, nil
+}
+
+// path gives the source code info path of the index-th element of kind in parent, parent isn't modified
+func path(parent []int32, kind int32, index int) []int32 {
+	p := make([]int32, len(parent), len(parent)+2)
+	copy(p, parent)
+	return append(p, kind, int32(index))
+}
+
+// register records the full names of a message or enum and of its nested types
+func (b *fileBuilder) register(t ProtoType, scope string) {
+	switch n := t.(type) {
+	case *Message:
+		name := scope + "." + normalizeName(n.name)
+		b.names[n] = name
+		for _, nested := range n.nested {
+			b.register(nested, name)
+		}
+	case *Enum:
+		b.names[n] = scope + "." + normalizeName(n.name)
+	}
+}
+
+// typeName gives the full name of a message or enum, names of other files are already qualified
+func (b *fileBuilder) typeName(t ProtoType) string {
+	if name, ok := b.names[t]; ok {
+		return name
+	}
+	if n, ok := t.(*TypeName); ok && n.local {
+		return b.scope + "." + normalizeName(n.name)
+	}
+	return "." + normalizeName(t.Name())
+}
+
+// setType : scalars have a type, messages and enums declared in other files only a type name
+func (b *fileBuilder) setType(field *descriptorpb.FieldDescriptorProto, t ProtoType) {
+	if scalar, ok := scalarTypes[t.Name()]; ok {
+		field.Type = scalar.Enum()
+		return
+	}
+	switch t.(type) {
+	case *Message:
+		field.Type = descriptorpb.FieldDescriptorProto_TYPE_MESSAGE.Enum()
+	case *Enum:
+		field.Type = descriptorpb.FieldDescriptorProto_TYPE_ENUM.Enum()
+	}
+	field.TypeName = proto.String(b.typeName(t))
+}
+
+// comment records the leading comments of the element at path
+func (b *fileBuilder) comment(path []int32, text string) {
+	comments := leadingComments(text)
+	if comments == "" {
+		return
+	}
+	b.file.SourceCodeInfo.Location = append(b.file.SourceCodeInfo.Location, &descriptorpb.SourceCodeInfo_Location{
+		Path:            path,
+		Span:            []int32{0, 0, 0},
+		LeadingComments: proto.String(comments),
+	})
+}
+
+func (b *fileBuilder) message(t *Message, p []int32) *descriptorpb.DescriptorProto {
+	b.comment(p, t.comment)
+	m := &descriptorpb.DescriptorProto{Name: proto.String(normalizeName(t.name))}
+	scope := b.names[t]
+	for _, n := range t.nested {
+		switch nested := n.(type) {
+		case *Message:
+			m.NestedType = append(m.NestedType, b.message(nested, path(p, messageNested, len(m.NestedType))))
+		case *Enum:
+			m.EnumType = append(m.EnumType, b.enum(nested, path(p, messageEnums, len(m.EnumType))))
+		}
+	}
+
+	// proto3 optional fields have a synthetic oneof, declared after the others
+	var optionals []*descriptorpb.FieldDescriptorProto
+	for i := range t.body {
+		f := &t.body[i]
+		if f.oneof == nil {
+			field := b.field(f, path(p, messageFields, len(m.Field)), m, scope)
+			if f.optional {
+				optionals = append(optionals, field)
+			}
+			m.Field = append(m.Field, field)
+			continue
+		}
+		index := int32(len(m.OneofDecl))
+		b.comment(path(p, messageOneofs, len(m.OneofDecl)), f.comment)
+		m.OneofDecl = append(m.OneofDecl, &descriptorpb.OneofDescriptorProto{Name: proto.String(normalizeName(f.name))})
+		for j := range f.oneof {
+			field := b.field(&f.oneof[j], path(p, messageFields, len(m.Field)), m, scope)
+			field.OneofIndex = proto.Int32(index)
+			m.Field = append(m.Field, field)
+		}
+	}
+	for _, field := range optionals {
+		field.Proto3Optional = proto.Bool(true)
+		field.OneofIndex = proto.Int32(int32(len(m.OneofDecl)))
+		m.OneofDecl = append(m.OneofDecl, &descriptorpb.OneofDescriptorProto{Name: proto.String("_" + field.GetName())})
+	}
+
+	for _, n := range t.reserved {
+		m.ReservedRange = append(m.ReservedRange, &descriptorpb.DescriptorProto_ReservedRange{Start: proto.Int32(int32(n)), End: proto.Int32(int32(n + 1))})
+	}
+	m.ReservedName = append(m.ReservedName, t.reservedNames...)
+	return m
+}
+
+// field : map fields declare their entry message in parent, whose full name is scope
+func (b *fileBuilder) field(f *MessageMembers, p []int32, parent *descriptorpb.DescriptorProto, scope string) *descriptorpb.FieldDescriptorProto {
+	b.comment(p, f.comment)
+	field := &descriptorpb.FieldDescriptorProto{
+		Name:   proto.String(normalizeName(f.name)),
+		Number: proto.Int32(int32(f.number)),
+		Label:  descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum(),
+	}
+	if f.repeated {
+		field.Label = descriptorpb.FieldDescriptorProto_LABEL_REPEATED.Enum()
+	}
+	if m, ok := f.typedecl.(*Map); ok && parent != nil {
+		entry := b.mapEntry(field.GetName(), m)
+		parent.NestedType = append(parent.NestedType, entry)
+		field.Label = descriptorpb.FieldDescriptorProto_LABEL_REPEATED.Enum()
+		field.Type = descriptorpb.FieldDescriptorProto_TYPE_MESSAGE.Enum()
+		field.TypeName = proto.String(scope + "." + entry.GetName())
+	} else {
+		b.setType(field, f.typedecl)
+	}
+	for _, o := range f.options {
+		if o.name == "json_name" {
+			if name, err := strconv.Unquote(o.value); err == nil {
+				field.JsonName = proto.String(name)
+			}
+			continue
+		}
+		if field.Options == nil {
+			field.Options = &descriptorpb.FieldOptions{}
+		}
+		setOption(field.Options, o)
+	}
+	return field
+}
+
+// mapEntry : message of the entries of a map field, named as protoc does : labels gives LabelsEntry
+func (b *fileBuilder) mapEntry(field string, m *Map) *descriptorpb.DescriptorProto {
+	key := &descriptorpb.FieldDescriptorProto{Name: proto.String("key"), Number: proto.Int32(1), Label: descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum()}
+	b.setType(key, &TypeName{name: m.key})
+	value := &descriptorpb.FieldDescriptorProto{Name: proto.String("value"), Number: proto.Int32(2), Label: descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum()}
+	b.setType(value, m.value)
+	return &descriptorpb.DescriptorProto{
+		Name:    proto.String(camelCase(field) + "Entry"),
+		Field:   []*descriptorpb.FieldDescriptorProto{key, value},
+		Options: &descriptorpb.MessageOptions{MapEntry: proto.Bool(true)},
+	}
+}
+
+func (b *fileBuilder) enum(t *Enum, p []int32) *descriptorpb.EnumDescriptorProto {
+	b.comment(p, t.comment)
+	e := &descriptorpb.EnumDescriptorProto{Name: proto.String(normalizeName(t.name))}
+	for i, v := range t.values {
+		b.comment(path(p, enumValues, i), v.comment)
+		value := &descriptorpb.EnumValueDescriptorProto{Name: proto.String(v.name), Number: proto.Int32(int32(v.number))}
+		for _, o := range v.options {
+			if value.Options == nil {
+				value.Options = &descriptorpb.EnumValueOptions{}
+			}
+			setOption(value.Options, o)
+		}
+		e.Value = append(e.Value, value)
+	}
+	return e
+}
+
+func (b *fileBuilder) service(t *Service, p []int32) *descriptorpb.ServiceDescriptorProto {
+	b.comment(p, t.comment)
+	s := &descriptorpb.ServiceDescriptorProto{Name: proto.String(t.name)}
+	for i, r := range t.rpcs {
+		b.comment(path(p, serviceMethods, i), r.comment)
+		method := &descriptorpb.MethodDescriptorProto{
+			Name:       proto.String(r.name),
+			InputType:  proto.String(b.scope + "." + r.request),
+			OutputType: proto.String(b.scope + "." + r.response),
+		}
+		if r.http != nil {
+			method.Options = &descriptorpb.MethodOptions{}
+			setOption(method.Options, Option{"(google.api.http)", "{" + r.http.String() + "}"})
+		}
+		s.Method = append(s.Method, method)
+	}
+	return s
+}
+
+// setOption sets a field of options, custom options are kept uninterpreted as protoc does until their extension is resolved
+func setOption(options proto.Message, o Option) {
+	m := options.ProtoReflect()
+	fields := m.Descriptor().Fields()
+	if fd := fields.ByName(protoreflect.Name(o.name)); fd != nil && fd.Cardinality() != protoreflect.Repeated {
+		if v, ok := scalarValue(fd, o.value); ok {
+			m.Set(fd, v)
+			return
+		}
+	}
+	list := m.Mutable(fields.ByName("uninterpreted_option")).List()
+	list.Append(protoreflect.ValueOfMessage(uninterpreted(o).ProtoReflect()))
+}
+
+// scalarValue parses the value of a bool, string or enum option
+func scalarValue(fd protoreflect.FieldDescriptor, value string) (protoreflect.Value, bool) {
+	switch fd.Kind() {
+	case protoreflect.BoolKind:
+		b, err := strconv.ParseBool(value)
+		return protoreflect.ValueOfBool(b), err == nil
+	case protoreflect.StringKind:
+		s, err := strconv.Unquote(value)
+		return protoreflect.ValueOfString(s), err == nil
+	case protoreflect.EnumKind:
+		if v := fd.Enum().Values().ByName(protoreflect.Name(value)); v != nil {
+			return protoreflect.ValueOfEnum(v.Number()), true
+		}
+	}
+	return protoreflect.Value{}, false
+}
+
+// uninterpreted : option as parsed by protoc, eg: (validate.rules).string = {min_len: 1}
+func uninterpreted(o Option) *descriptorpb.UninterpretedOption {
+	u := &descriptorpb.UninterpretedOption{}
+	for _, part := range splitOptionName(o.name) {
+		u.Name = append(u.Name, &descriptorpb.UninterpretedOption_NamePart{
+			NamePart:    proto.String(strings.TrimSuffix(strings.TrimPrefix(part, "("), ")")),
+			IsExtension: proto.Bool(strings.HasPrefix(part, "(")),
+		})
+	}
+	value := o.value
+	if strings.HasPrefix(value, "{") && strings.HasSuffix(value, "}") {
+		u.AggregateValue = proto.String(strings.TrimSpace(value[1 : len(value)-1]))
+		return u
+	}
+	if s, err := strconv.Unquote(value); err == nil && strings.HasPrefix(value, "\"") {
+		u.StringValue = []byte(s)
+		return u
+	}
+	if n, err := strconv.ParseUint(value, 10, 64); err == nil {
+		u.PositiveIntValue = proto.Uint64(n)
+		return u
+	}
+	if n, err := strconv.ParseInt(value, 10, 64); err == nil {
+		u.NegativeIntValue = proto.Int64(n)
+		return u
+	}
+	if f, err := strconv.ParseFloat(value, 64); err == nil && strings.ContainsAny(value, "0123456789") {
+		u.DoubleValue = proto.Float64(f)
+		return u
+	}
+	u.IdentifierValue = proto.String(value)
+	return u
+}
+
+// splitOptionName splits an option name on dots outside parentheses : (validate.rules).string gives (validate.rules) and string
+func splitOptionName(name string) []string {
+	var parts []string
+	depth, start := 0, 0
+	for i, r := range name {
+		switch r {
+		case '(':
+			depth++
+		case ')':
+			depth--
+		case '.':
+			if depth == 0 {
+				parts = append(parts, name[start:i])
+				start = i + 1
+			}
+		}
+	}
+	return append(parts, name[start:])
+}
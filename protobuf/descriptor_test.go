@@ -0,0 +1,63 @@
+package protobuf
+
+import (
+	"testing"
+
+	"github.com/Axili39/oastools/oasmodel"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	_ "google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func TestCreateFile(t *testing.T) {
+	for _, spec := range []string{"tests/maps.yaml", "tests/comments.yaml", "tests/polymorphism.yaml", "tests/nestedoneof.yaml", "tests/presence/presence.yaml"} {
+		oa := oasmodel.OpenAPI{}
+		err := oa.Load(spec)
+		if err != nil {
+			t.Fatalf("error loading spec : %v", err)
+		}
+		file, err := CreateFile(&oa, "test.proto", "test.v1", GenerationOptions{Imports: map[string]bool{}, Presence: PresenceOptional}, nil, `go_package="gen/test"`)
+		if err != nil {
+			t.Fatalf("error generating %s : %v", spec, err)
+		}
+		if file.GetOptions().GetGoPackage() != "gen/test" {
+			t.Errorf("%s : go_package not set : %v", spec, file.GetOptions())
+		}
+		// types are resolved, and source code info checked
+		_, err = protodesc.NewFile(file, protoregistry.GlobalFiles)
+		if err != nil {
+			t.Errorf("%s : invalid descriptor : %v", spec, err)
+		}
+	}
+}
+
+func TestCreateFileDescriptors(t *testing.T) {
+	oa := oasmodel.OpenAPI{}
+	err := oa.Load("tests/maps.yaml")
+	if err != nil {
+		t.Fatalf("error loading spec : %v", err)
+	}
+	file, err := CreateFile(&oa, "maps.proto", "maps", GenerationOptions{Imports: map[string]bool{}}, nil)
+	if err != nil {
+		t.Fatalf("error generating : %v", err)
+	}
+	fd, err := protodesc.NewFile(file, nil)
+	if err != nil {
+		t.Fatalf("invalid descriptor : %v", err)
+	}
+	catalog := fd.Messages().ByName("catalog")
+	if catalog == nil {
+		t.Fatalf("message catalog not found")
+	}
+	labels := catalog.Fields().ByName("labels")
+	if labels == nil || !labels.IsMap() || labels.MapValue().Message().FullName() != "maps.label" {
+		t.Errorf("labels must be a map of maps.label : %v", labels)
+	}
+	if prices := catalog.Fields().ByName("item_prices"); prices.JSONName() != "item_prices" {
+		t.Errorf("bad json name %s", prices.JSONName())
+	}
+	comment := fd.SourceLocations().ByDescriptor(catalog.Fields().ByName("counts")).LeadingComments
+	if comment != " number of items by category\n" {
+		t.Errorf("bad comment %q", comment)
+	}
+}
@@ -2,61 +2,150 @@ package protobuf
 
 import (
 	"fmt"
-	"io"
+	"sort"
+	"strconv"
 	"strings"
 
 	"github.com/Axili39/oastools/oasmodel"
 )
 
+// EnumValue : enum constant, value is the wire value of the spec
+type EnumValue struct {
+	name    string
+	number  int
+	value   string
+	options []Option
+	comment string
+}
+
 // Enum simple type or reference (by-name)
 type Enum struct {
-	name   string
-	values []string
-	prefix string
+	name    string
+	values  []EnumValue
+	comment string
 }
 
-//Declare : ProtoType interface realization
-/* Example :
-enum Corpus {
-    UNIVERSAL = 0;
-    WEB = 1;
-    IMAGES = 2;
-    LOCAL = 3;
-    NEWS = 4;
-    PRODUCTS = 5;
-    VIDEO = 6;
-  }
-*/
-func (t *Enum) Declare(w io.Writer, indent string) {
-	fmt.Fprintf(w, "%senum %s {\n", indent, normalizeName(t.name))
-	values := 0
-	for i := range t.values {
-		fmt.Fprintf(w, "%s\t%s%s = %d;\n", indent, t.prefix, normalizeName(t.values[i]), values)
-		values++
-	}
-	fmt.Fprintf(w, "%s}\n", indent)
-}
+// jsonValueOption : custom enum value option recording the spec value of a renamed constant
+const jsonValueOption = "(json_value)"
 
 // Name :  ProtoType interface realization
 func (t *Enum) Name() string {
 	return t.name
 }
 
+// isEnum returns true for schemas translated to enums : string and integer enumerations
+func isEnum(schema *oasmodel.Schema) bool {
+	return (schema.TypeName() == "string" || schema.TypeName() == "integer") && len(schema.Enum) > 0
+}
+
+// createEnum : string values are numbered in order, integer values keep their value.
+// Constants are named after x-enum-varnames, or the values made valid identifiers, and documented by x-enum-descriptions.
 func createEnum(name string, schema *oasmodel.Schema, parent *Message, genOpts GenerationOptions) (ProtoType, error) {
-	// Enums
-	if schema.Type == "string" && len(schema.Enum) > 0 {
-		prefix := ""
-		if genOpts.AddEnumPrefix {
-			prefix = strings.ToUpper(normalizeName(name)) + "_"
+	if !isEnum(schema) {
+		return nil, fmt.Errorf("Enum must be string or integer and have non empty Enum Array")
+	}
+	if len(schema.XEnumVarnames) > 0 && len(schema.XEnumVarnames) != len(schema.Enum) {
+		return nil, fatalErrorf("enum %s : %d x-enum-varnames for %d values", name, len(schema.XEnumVarnames), len(schema.Enum))
+	}
+	if len(schema.XEnumDescriptions) > 0 && len(schema.XEnumDescriptions) != len(schema.Enum) {
+		return nil, fatalErrorf("enum %s : %d x-enum-descriptions for %d values", name, len(schema.XEnumDescriptions), len(schema.Enum))
+	}
+	upper := strings.ToUpper(identifier(name))
+	prefix := ""
+	if genOpts.AddEnumPrefix {
+		prefix = upper + "_"
+	}
+	integer := schema.TypeName() == "integer"
+
+	node := Enum{name, nil, schemaComment(schema)}
+	numbers := make(map[int]string)
+	for i, value := range schema.Enum {
+		v := EnumValue{value: value, number: i}
+		if len(schema.XEnumDescriptions) > 0 {
+			v.comment = schema.XEnumDescriptions[i]
+		}
+		switch {
+		case len(schema.XEnumVarnames) > 0:
+			v.name = identifier(prefix + schema.XEnumVarnames[i])
+		case integer:
+			v.name = identifier(upper + "_" + strings.Replace(value, "-", "MINUS_", 1))
+		default:
+			v.name = identifier(prefix + value)
+		}
+		// empty values, common in specs, have no identifier
+		if v.name == "" || v.name == prefix {
+			v.name = upper + "_EMPTY"
+		}
+		if integer {
+			n, err := strconv.Atoi(value)
+			if err != nil {
+				return nil, fatalErrorf("enum %s : %s isn't an integer", name, value)
+			}
+			v.number = n
+		} else if genOpts.EnumUnspecified {
+			v.number = i + 1
+		}
+		if other, found := numbers[v.number]; found {
+			return nil, fatalErrorf("enum %s : values %s and %s have the same number %d", name, other, value, v.number)
 		}
-		node := Enum{name, nil, prefix}
-		for i := range schema.Enum {
-			node.values = append(node.values, schema.Enum[i])
+		numbers[v.number] = value
+		if genOpts.EnumValueOption && !integer && v.name != value {
+			v.options = append(v.options, Option{jsonValueOption, strconv.Quote(value)})
+			importOptions(genOpts)
 		}
-		if parent != nil {
-			parent.nested = append(parent.nested, &node)
+		node.values = append(node.values, v)
+	}
+
+	// proto3 first value must be zero
+	if _, found := numbers[0]; !found {
+		unspecified := EnumValue{name: prefix + "UNSPECIFIED"}
+		if prefix == "" {
+			unspecified.name = upper + "_UNSPECIFIED"
+		}
+		node.values = append([]EnumValue{unspecified}, node.values...)
+	}
+	sort.SliceStable(node.values, func(i, j int) bool {
+		return node.values[i].number == 0 && node.values[j].number != 0
+	})
+
+	names := make(map[string]string)
+	for _, v := range node.values {
+		if other, found := names[v.name]; found {
+			return nil, fatalErrorf("enum %s : values %q and %q give the same name %s, use x-enum-varnames", name, other, v.value, v.name)
+		}
+		names[v.name] = v.value
+	}
+
+	if parent != nil {
+		parent.nested = append(parent.nested, &node)
+	}
+	return &node, nil
+}
+
+// checkEnumScope : enum constants are declared in the scope of their enum, the file or the parent message,
+// they must not collide with types nor constants of other enums of this scope
+func checkEnumScope(types []ProtoType) error {
+	declared := make(map[string]string)
+	for _, t := range types {
+		switch t.(type) {
+		case *Message, *Enum:
+			declared[normalizeName(t.Name())] = "type " + normalizeName(t.Name())
+		}
+	}
+	for _, t := range types {
+		switch n := t.(type) {
+		case *Enum:
+			for _, v := range n.values {
+				if other, found := declared[v.name]; found {
+					return fatalErrorf("enum %s : constant %s is already declared by %s in the same scope, use -add-enum-prefix or x-enum-varnames", n.name, v.name, other)
+				}
+				declared[v.name] = "enum " + normalizeName(n.name)
+			}
+		case *Message:
+			if err := checkEnumScope(n.nested); err != nil {
+				return err
+			}
 		}
-		return &node, nil
 	}
-	return nil, fmt.Errorf("Enum must be string and have non empte Enum Array")
+	return nil
 }
@@ -0,0 +1,77 @@
+package protobuf
+
+import (
+	"bytes"
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	"github.com/Axili39/oastools/oasmodel"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+func TestEnums(t *testing.T) {
+	for _, test := range []struct {
+		result  string
+		genOpts GenerationOptions
+	}{
+		{"tests/enums/enums.proto", GenerationOptions{Imports: map[string]bool{}, AddMsgPrefix: true}},
+		{"tests/enums/enums-options.proto", GenerationOptions{Imports: map[string]bool{}, AddMsgPrefix: true, AddEnumPrefix: true, EnumUnspecified: true, EnumValueOption: true}},
+	} {
+		oa := oasmodel.OpenAPI{}
+		err := oa.Load("tests/enums/enums.yaml")
+		if err != nil {
+			t.Fatalf("error loading spec : %v", err)
+		}
+		output := &bytes.Buffer{}
+		err = Components2Proto(&oa, output, "", test.genOpts, nil)
+		if err != nil {
+			t.Fatalf("error generating %s : %v", test.result, err)
+		}
+		expected, err := ioutil.ReadFile(test.result)
+		if err != nil {
+			t.Fatalf("error loading result file : %v", err)
+		}
+		if string(expected) != output.String() {
+			t.Errorf("Result differ for %s\ngot:\n%s\nexpected:\n%s", test.result, output.String(), string(expected))
+		}
+	}
+}
+
+func TestEnumErrors(t *testing.T) {
+	for _, test := range []struct {
+		schema   oasmodel.Schema
+		expected string
+	}{
+		{oasmodel.Schema{Type: oasmodel.SchemaType{"string"}, Enum: []string{"a-b", "a b"}}, "give the same name a_b"},
+		{oasmodel.Schema{Type: oasmodel.SchemaType{"integer"}, Enum: []string{"1", "x"}}, "x isn't an integer"},
+		{oasmodel.Schema{Type: oasmodel.SchemaType{"integer"}, Enum: []string{"1", "01"}}, "have the same number 1"},
+		{oasmodel.Schema{Type: oasmodel.SchemaType{"string"}, Enum: []string{"a", "b"}, XEnumVarnames: []string{"A"}}, "1 x-enum-varnames for 2 values"},
+	} {
+		_, err := createEnum("foo", &test.schema, nil, GenerationOptions{Imports: map[string]bool{}})
+		if err == nil || !strings.Contains(err.Error(), test.expected) {
+			t.Errorf("expected error %q, got %v", test.expected, err)
+		}
+	}
+}
+
+func TestEnumScope(t *testing.T) {
+	oa := oasmodel.OpenAPI{}
+	err := oa.Load("tests/enums/collision.yaml")
+	if err != nil {
+		t.Fatalf("error loading spec : %v", err)
+	}
+	// constants of a and b share the package scope
+	_, err = CreateFile(&oa, "collision.proto", "", GenerationOptions{Imports: map[string]bool{}}, nil)
+	if err == nil || !strings.Contains(err.Error(), "constant active is already declared by enum a") {
+		t.Errorf("expected collision error, got %v", err)
+	}
+	file, err := CreateFile(&oa, "collision.proto", "", GenerationOptions{Imports: map[string]bool{}, AddEnumPrefix: true}, nil)
+	if err != nil {
+		t.Fatalf("error generating with prefix : %v", err)
+	}
+	_, err = NewFiles([]*descriptorpb.FileDescriptorProto{file})
+	if err != nil {
+		t.Errorf("invalid descriptor : %v", err)
+	}
+}
@@ -0,0 +1,205 @@
+package protobuf
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"sort"
+)
+
+// field numbers allowed by protobuf
+const (
+	maxFieldNumber        = 536870911
+	firstReservedByProto  = 19000
+	lastReservedByProto   = 19999
+	lockFileFormatVersion = 1
+)
+
+// Lock records the field numbers assigned to each message, so that they are kept across generations.
+// It is stored as JSON, eg: spec.protolock.json
+type Lock struct {
+	Version  int                     `json:"version"`
+	Messages map[string]*MessageLock `json:"messages"`
+}
+
+// MessageLock field numbers of a message, removed fields are reserved
+type MessageLock struct {
+	Fields        map[string]int `json:"fields"`
+	Reserved      []int          `json:"reserved,omitempty"`
+	ReservedNames []string       `json:"reservedNames,omitempty"`
+}
+
+// NewLock creates an empty Lock
+func NewLock() *Lock {
+	return &Lock{Version: lockFileFormatVersion, Messages: make(map[string]*MessageLock)}
+}
+
+// LoadLock reads a lock file, an empty Lock is returned if the file doesn't exist
+func LoadLock(filename string) (*Lock, error) {
+	data, err := ioutil.ReadFile(filename)
+	if os.IsNotExist(err) {
+		return NewLock(), nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	lock := NewLock()
+	err = json.Unmarshal(data, lock)
+	if err != nil {
+		return nil, fmt.Errorf("error reading lock file %s : %v", filename, err)
+	}
+	if lock.Messages == nil {
+		lock.Messages = make(map[string]*MessageLock)
+	}
+	return lock, nil
+}
+
+// Clone returns a copy of the lock, to generate without updating it
+func (l *Lock) Clone() *Lock {
+	clone := NewLock()
+	for name, m := range l.Messages {
+		fields := make(map[string]int, len(m.Fields))
+		for f, n := range m.Fields {
+			fields[f] = n
+		}
+		clone.Messages[name] = &MessageLock{
+			Fields:        fields,
+			Reserved:      append([]int(nil), m.Reserved...),
+			ReservedNames: append([]string(nil), m.ReservedNames...),
+		}
+	}
+	return clone
+}
+
+// Save writes the lock file
+func (l *Lock) Save(filename string) error {
+	data, err := json.MarshalIndent(l, "", "  ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(filename, append(data, '\n'), 0644)
+}
+
+// fieldNumbers assigns a number to each field of a message :
+//   - explicit numbers (x-proto-field-number) first, they must not collide
+//   - then numbers recorded in the lock
+//   - new fields get numbers greater than any number used or reserved
+//
+// Without lock, fields are numbered sequentially, skipping explicit numbers.
+// Reserved numbers and names of removed fields are returned.
+func fieldNumbers(message string, fields []string, explicit map[string]int, lock *Lock) (map[string]int, []int, []string, error) {
+	numbers := make(map[string]int, len(fields))
+	owner := make(map[int]string)
+
+	var locked *MessageLock
+	if lock != nil {
+		locked = lock.Messages[message]
+	}
+	if locked == nil {
+		locked = &MessageLock{Fields: map[string]int{}}
+	}
+	reserved := make(map[int]bool)
+	for _, n := range locked.Reserved {
+		reserved[n] = true
+	}
+	lockedOwner := make(map[int]string)
+	for f, n := range locked.Fields {
+		lockedOwner[n] = f
+	}
+
+	for _, f := range fields {
+		n, ok := explicit[f]
+		if !ok {
+			continue
+		}
+		if n < 1 || n > maxFieldNumber || (n >= firstReservedByProto && n <= lastReservedByProto) {
+			return nil, nil, nil, fatalErrorf("%s.%s : invalid field number %d", message, f, n)
+		}
+		if other, found := owner[n]; found {
+			return nil, nil, nil, fatalErrorf("%s.%s : field number %d already used by %s", message, f, n, other)
+		}
+		if reserved[n] {
+			return nil, nil, nil, fatalErrorf("%s.%s : field number %d is reserved", message, f, n)
+		}
+		if other := lockedOwner[n]; other != "" && other != f {
+			return nil, nil, nil, fatalErrorf("%s.%s : field number %d is locked for %s", message, f, n, other)
+		}
+		numbers[f] = n
+		owner[n] = f
+	}
+	for _, f := range fields {
+		if _, ok := numbers[f]; ok {
+			continue
+		}
+		n, ok := locked.Fields[f]
+		if !ok {
+			continue
+		}
+		numbers[f] = n
+		owner[n] = f
+	}
+
+	// new fields, numbers of removed fields are never reused
+	next := 0
+	if lock != nil {
+		for _, n := range locked.Fields {
+			if n > next {
+				next = n
+			}
+		}
+		for n := range reserved {
+			if n > next {
+				next = n
+			}
+		}
+	}
+	for _, f := range fields {
+		if _, ok := numbers[f]; ok {
+			continue
+		}
+		next++
+		for owner[next] != "" || (next >= firstReservedByProto && next <= lastReservedByProto) {
+			next++
+		}
+		numbers[f] = next
+		owner[next] = f
+	}
+
+	if lock == nil {
+		return numbers, nil, nil, nil
+	}
+
+	// removed and renumbered fields are reserved
+	names := make(map[string]bool)
+	for _, name := range locked.ReservedNames {
+		names[name] = true
+	}
+	for f, n := range locked.Fields {
+		current, found := numbers[f]
+		if found && current == n {
+			continue
+		}
+		if owner[n] == "" {
+			reserved[n] = true
+		}
+		if !found {
+			names[f] = true
+		}
+	}
+	for _, f := range fields {
+		delete(names, f)
+	}
+
+	entry := &MessageLock{Fields: numbers}
+	for n := range reserved {
+		entry.Reserved = append(entry.Reserved, n)
+	}
+	sort.Ints(entry.Reserved)
+	for name := range names {
+		entry.ReservedNames = append(entry.ReservedNames, name)
+	}
+	sort.Strings(entry.ReservedNames)
+	lock.Messages[message] = entry
+	return numbers, entry.Reserved, entry.ReservedNames, nil
+}
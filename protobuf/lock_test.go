@@ -0,0 +1,127 @@
+package protobuf
+
+import (
+	"bytes"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Axili39/oastools/oasmodel"
+)
+
+func generateWithLock(t *testing.T, spec string, lock *Lock) (string, error) {
+	t.Helper()
+	oa := oasmodel.OpenAPI{}
+	if _, err := oa.UnMarshal([]byte(spec)); err != nil {
+		t.Fatalf("error loading spec : %v", err)
+	}
+	output := &bytes.Buffer{}
+	err := Components2Proto(&oa, output, "", GenerationOptions{Imports: map[string]bool{}, Lock: lock}, nil)
+	return output.String(), err
+}
+
+const lockSpecV1 = `openapi: 3.0.0
+info:
+  title: lock
+  version: 1.0.0
+components:
+  schemas:
+    Pet:
+      type: object
+      properties:
+        id:
+          type: integer
+        name:
+          type: string
+          x-proto-field-number: 10
+        tag:
+          type: string
+`
+
+// a field is inserted before tag, and tag is removed
+const lockSpecV2 = `openapi: 3.0.0
+info:
+  title: lock
+  version: 1.0.0
+components:
+  schemas:
+    Pet:
+      type: object
+      properties:
+        age:
+          type: integer
+        id:
+          type: integer
+        name:
+          type: string
+          x-proto-field-number: 10
+`
+
+func TestFieldNumbers(t *testing.T) {
+	// explicit numbers without lock
+	proto, err := generateWithLock(t, lockSpecV1, nil)
+	if err != nil {
+		t.Fatalf("error generating : %v", err)
+	}
+	for _, field := range []string{"int32 id = 1;", "string name = 10;", "string tag = 2;"} {
+		if !strings.Contains(proto, field) {
+			t.Errorf("expected %s in :\n%s", field, proto)
+		}
+	}
+
+	// numbers are kept through the lock file
+	filename := filepath.Join(t.TempDir(), "spec.protolock.json")
+	lock, err := LoadLock(filename)
+	if err != nil {
+		t.Fatalf("error loading lock : %v", err)
+	}
+	if _, err = generateWithLock(t, lockSpecV1, lock); err != nil {
+		t.Fatalf("error generating : %v", err)
+	}
+	if err = lock.Save(filename); err != nil {
+		t.Fatalf("error saving lock : %v", err)
+	}
+	lock, err = LoadLock(filename)
+	if err != nil {
+		t.Fatalf("error loading lock : %v", err)
+	}
+	proto, err = generateWithLock(t, lockSpecV2, lock)
+	if err != nil {
+		t.Fatalf("error generating : %v", err)
+	}
+	for _, field := range []string{"int32 age = 11;", "int32 id = 1;", "string name = 10;", "reserved 2;", "reserved \"tag\";"} {
+		if !strings.Contains(proto, field) {
+			t.Errorf("expected %s in :\n%s", field, proto)
+		}
+	}
+	if got := lock.Messages["Pet"].Fields["age"]; got != 11 {
+		t.Errorf("age locked with %d, expected 11", got)
+	}
+}
+
+func TestFieldNumbersCollision(t *testing.T) {
+	// explicit number colliding with another explicit number
+	spec := strings.Replace(lockSpecV1, "        tag:\n          type: string\n", "        tag:\n          type: string\n          x-proto-field-number: 10\n", 1)
+	if _, err := generateWithLock(t, spec, nil); err == nil || !strings.Contains(err.Error(), "already used") {
+		t.Errorf("expected collision error, got %v", err)
+	}
+
+	// explicit number colliding with a locked number
+	lock := NewLock()
+	if _, err := generateWithLock(t, lockSpecV1, lock); err != nil {
+		t.Fatalf("error generating : %v", err)
+	}
+	spec = strings.Replace(lockSpecV1, "        id:\n          type: integer\n", "        id:\n          type: integer\n          x-proto-field-number: 2\n", 1)
+	if _, err := generateWithLock(t, spec, lock); err == nil || !strings.Contains(err.Error(), "locked for tag") {
+		t.Errorf("expected lock collision error, got %v", err)
+	}
+
+	// explicit number of a removed field
+	if _, err := generateWithLock(t, lockSpecV2, lock); err != nil {
+		t.Fatalf("error generating : %v", err)
+	}
+	spec = strings.Replace(lockSpecV2, "        age:\n          type: integer\n", "        age:\n          type: integer\n          x-proto-field-number: 2\n", 1)
+	if _, err := generateWithLock(t, spec, lock); err == nil || !strings.Contains(err.Error(), "reserved") {
+		t.Errorf("expected reserved error, got %v", err)
+	}
+}
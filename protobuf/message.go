@@ -2,44 +2,34 @@ package protobuf
 
 import (
 	"fmt"
-	"io"
-	"os"
+	"regexp"
+	"sort"
+	"strconv"
 	"strings"
+	"unicode"
 
 	"github.com/Axili39/oastools/oasmodel"
 )
 
 // MESSAGE
-/*
-//Option for message fields
+
+// Option for message fields
 type Option struct {
 	name  string
 	value string
 }
-*/
-//MessageMembers Message Field definition
+
+// MessageMembers Message Field definition
 type MessageMembers struct {
 	//repeated bool
 	typedecl ProtoType
 	name     string
 	number   int
 	repeated bool
-	//	Options  []Option
-	comment string
-}
-
-// Declare : Message Member declaration
-func (t *MessageMembers) Declare(w io.Writer, indent string) {
-	fmt.Fprintf(w, "%s", indent)
-	// repeated
-	if t.repeated {
-		fmt.Fprintf(w, "repeated ")
-	}
-	// field decl
-	fmt.Fprintf(w, "%s %s = %d;", normalizeName(t.typedecl.Name()), normalizeName(t.name), t.number)
-	// TODO : options
-	fmt.Fprintf(w, " /* %s */", t.comment)
-	fmt.Fprintf(w, "\n")
+	comment  string
+	optional bool // proto3 explicit presence
+	options  []Option
+	oneof    []MessageMembers // members of a oneof named after the field, the field has no type
 }
 
 // Name : Member Name
@@ -49,25 +39,13 @@ func (t *MessageMembers) Name() string {
 
 // Message structure
 type Message struct {
-	name    string
-	nested  []ProtoType      // Nested definitions
-	body    []MessageMembers // Message Fields
-	comment string
-}
-
-// Declare : ProtoType interface realization
-func (t *Message) Declare(w io.Writer, indent string) {
-	fmt.Fprintf(w, "%s/* Type : %s */\n", indent, t.comment)
-	fmt.Fprintf(w, "%smessage %s {\n", indent, normalizeName(t.name))
-	// nested
-	for n := range t.nested {
-		t.nested[n].Declare(w, indent+"\t")
-	}
-	// body
-	for m := range t.body {
-		t.body[m].Declare(w, indent+"\t")
-	}
-	fmt.Fprintf(w, "%s}\n", indent)
+	name          string
+	nested        []ProtoType      // Nested definitions
+	body          []MessageMembers // Message Fields
+	comment       string
+	key           string // name in lock file : parent key and name
+	reserved      []int
+	reservedNames []string
 }
 
 // Name :  ProtoType interface realization
@@ -79,14 +57,107 @@ func isRepeated(schema *oasmodel.SchemaOrRef) bool {
 	if schema.Schema() == nil {
 		return false
 	}
-	return schema.Schema().Type == "array"
+	return schema.Schema().TypeName() == "array"
 }
 
-func createMessage(name string, schema *oasmodel.Schema, parent *Message, genOpts GenerationOptions) (ProtoType, error) {
-	var err error
+// newMessage creates a message, nested in parent if not nil
+func newMessage(name string, comment string, parent *Message) Message {
+	node := Message{name: name, comment: comment, key: name}
+	if parent != nil {
+		node.key = parent.key + "." + name
+	}
+	return node
+}
+
+// numberFields assigns field numbers of message fields and oneof members, x-proto-field-number and lock file are taken into account
+func (t *Message) numberFields(explicit map[string]int, genOpts GenerationOptions) error {
+	var names []string
+	for _, f := range t.body {
+		if f.oneof == nil {
+			names = append(names, f.name)
+		}
+		for _, m := range f.oneof {
+			names = append(names, m.name)
+		}
+	}
+	key := t.key
+	if genOpts.packageName != "" {
+		key = genOpts.packageName + "." + key
+	}
+	numbers, reserved, reservedNames, err := fieldNumbers(key, names, explicit, genOpts.Lock)
+	if err != nil {
+		return err
+	}
+	for i := range t.body {
+		t.body[i].number = numbers[t.body[i].name]
+		for j := range t.body[i].oneof {
+			t.body[i].oneof[j].number = numbers[t.body[i].oneof[j].name]
+		}
+	}
+	t.reserved = reserved
+	t.reservedNames = reservedNames
+	return nil
+}
+
+// inlineOneOf returns the schema of a property declared as a oneof of its message : an inline and singular oneOf
+func inlineOneOf(prop *oasmodel.SchemaOrRef) *oasmodel.Schema {
+	if prop.Ref != nil || prop.Val == nil || prop.Val.OneOf == nil || prop.Val.TypeName() == "array" {
+		return nil
+	}
+	return prop.Val
+}
+
+// createFields adds a field per property, or a oneof for oneOf properties, then numbers them
+func (t *Message) createFields(keys []string, properties map[string]*oasmodel.SchemaOrRef, required []string, typename func(m string) string, genOpts GenerationOptions) error {
+	explicit := make(map[string]int)
+	for _, m := range keys {
+		prop := properties[m]
+		f := MessageMembers{nil, m, 0, isRepeated(prop), propertyComment(prop), false, nil, nil}
+		var err error
+		if oneof := inlineOneOf(prop); oneof != nil {
+			f.oneof, err = createAlternatives(typename(m), oneof.OneOf, oneof.Discriminator, t, genOpts)
+		} else {
+			f.typedecl, err = CreateType(typename(m), prop, t, genOpts)
+		}
+		if err != nil {
+			return err
+		}
+		if f.oneof == nil {
+			setPresence(&f, prop, isRequired(m, required), genOpts)
+			if _, custom := prop.ProtoOptions()["json_name"]; !custom && jsonName(normalizeName(m)) != m {
+				f.options = append(f.options, Option{"json_name", strconv.Quote(m)})
+			}
+			f.options = append(f.options, fieldOptions(prop)...)
+			f.options = append(f.options, validateOptions(&f, prop, isRequired(m, required), genOpts)...)
+			if n := prop.FieldNumber(); n != 0 {
+				explicit[m] = n
+			}
+		}
+		t.body = append(t.body, f)
+	}
+
+	// oneof members share the scope of message fields
+	used := make(map[string]bool)
+	for _, f := range t.body {
+		used[f.name] = true
+	}
+	for i, f := range t.body {
+		if f.oneof == nil {
+			continue
+		}
+		uniqueNames(f.oneof, used)
+		oneof := inlineOneOf(properties[f.name])
+		for j := range f.oneof {
+			if n := oneof.OneOf[j].FieldNumber(); n != 0 {
+				explicit[t.body[i].oneof[j].name] = n
+			}
+		}
+	}
+	return t.numberFields(explicit, genOpts)
+}
 
-	node := Message{name, nil, nil, schema.Description}
-	num := 0
+func createMessage(name string, schema *oasmodel.Schema, parent *Message, genOpts GenerationOptions) (ProtoType, error) {
+	node := newMessage(name, schemaComment(schema), parent)
 	// sorting Properties Name
 	var keys []string
 	if len(schema.XPropertiesOrder) > 0 {
@@ -94,26 +165,22 @@ func createMessage(name string, schema *oasmodel.Schema, parent *Message, genOpt
 	} else {
 		keys = keysorder(schema.Properties)
 	}
-
-	// Add each Properties as message Member
 	for _, m := range keys {
-		num++
-		prop := schema.Properties[m]
-		if prop == nil {
-			fmt.Fprintln(os.Stderr, "bad property name : ", m)
-			os.Exit(1)
-		}
-		f := MessageMembers{nil, m, num, isRepeated(prop), prop.Description()}
-		if genOpts.AddMsgPrefix {
-			f.typedecl, err = CreateType(name+"_"+m, prop, &node, genOpts)
-		} else {
-			f.typedecl, err = CreateType(m+"_", prop, &node, genOpts)
+		if schema.Properties[m] == nil {
+			return nil, fmt.Errorf("%s : bad property name %s", name, m)
 		}
+	}
 
-		if err != nil {
-			return nil, err
+	// Add each Properties as message Member
+	typename := func(m string) string {
+		if genOpts.AddMsgPrefix {
+			return name + "_" + m
 		}
-		node.body = append(node.body, f)
+		return m + "_"
+	}
+	err := node.createFields(keys, schema.Properties, schema.Required, typename, genOpts)
+	if err != nil {
+		return nil, err
 	}
 	// if has parent insert as nested message
 	if parent != nil {
@@ -126,9 +193,9 @@ func createMessage(name string, schema *oasmodel.Schema, parent *Message, genOpt
 func createMessageArray(name string, schema *oasmodel.Schema, genOpts GenerationOptions) (ProtoType, error) {
 	var err error
 
-	node := Message{name + "Array", nil, nil, schema.Description}
+	node := newMessage(name+"Array", schemaComment(schema), nil)
 
-	f := MessageMembers{nil, "Items", 1, true, schema.Items.Description()}
+	f := MessageMembers{nil, "Items", 1, true, propertyComment(schema.Items), false, nil, nil}
 	f.typedecl, err = CreateType(name, schema.Items, &node, genOpts)
 	if err != nil {
 		return nil, err
@@ -138,34 +205,63 @@ func createMessageArray(name string, schema *oasmodel.Schema, genOpts Generation
 	return &node, nil
 }
 
-// Array : array of Prototype
-type Oneof struct {
-	name    string
-	members []MessageMembers
-}
-
-// Declare : ProtoType interface realization
-func (t *Oneof) Declare(w io.Writer, indent string) {
-	fmt.Fprintf(w, "%smessage %s {\n", indent, normalizeName(t.name))
-	fmt.Fprintf(w, "%s\toneof select {\n", indent)
-	// body
-	for m := range t.members {
-		t.members[m].Declare(w, indent+"\t\t")
+// createOneOf : message holding a oneof select, for oneOf which can't be declared in their message
+func createOneOf(name string, schema *oasmodel.Schema, parent *Message, genOpts GenerationOptions) (ProtoType, error) {
+	node := newMessage(name, schemaComment(schema), parent)
+	members, err := createAlternatives(name, schema.OneOf, schema.Discriminator, &node, genOpts)
+	if err != nil {
+		return nil, err
 	}
-	fmt.Fprintf(w, "\t%s}\n%s}\n", indent, indent)
+	uniqueNames(members, map[string]bool{"select": true})
+	node.body = []MessageMembers{{name: "select", oneof: members}}
+	explicit := make(map[string]int)
+	for i := range members {
+		if n := schema.OneOf[i].FieldNumber(); n != 0 {
+			explicit[members[i].name] = n
+		}
+	}
+	if err := node.numberFields(explicit, genOpts); err != nil {
+		return nil, err
+	}
+	// if has parent insert as nested message
+	if parent != nil {
+		parent.nested = append(parent.nested, &node)
+	}
+	return &node, nil
 }
 
-// Name :  ProtoType interface realization
-func (t *Oneof) Name() string {
-	return t.name
+// createAnyOf : message with an optional field per alternative, several may be set
+func createAnyOf(name string, schema *oasmodel.Schema, parent *Message, genOpts GenerationOptions) (ProtoType, error) {
+	node := newMessage(name, schemaComment(schema), parent)
+	members, err := createAlternatives(name, schema.AnyOf, schema.Discriminator, &node, genOpts)
+	if err != nil {
+		return nil, err
+	}
+	uniqueNames(members, map[string]bool{})
+	explicit := make(map[string]int)
+	for i := range members {
+		members[i].optional = isScalarField(&members[i], schema.AnyOf[i])
+		if n := schema.AnyOf[i].FieldNumber(); n != 0 {
+			explicit[members[i].name] = n
+		}
+	}
+	node.body = members
+	if err := node.numberFields(explicit, genOpts); err != nil {
+		return nil, err
+	}
+	// if has parent insert as nested message
+	if parent != nil {
+		parent.nested = append(parent.nested, &node)
+	}
+	return &node, nil
 }
 
-func createOneOf(name string, oneof []*oasmodel.SchemaOrRef, parent *Message, genOpts GenerationOptions) (ProtoType, error) {
-	node := Oneof{name, nil}
-	num := 0
-	for _, prop := range oneof {
-		num++
-		t, err := CreateType("YYY", prop, parent, genOpts)
+// createAlternatives : a field per oneOf or anyOf alternative, named after the discriminator mapping or the type name.
+// Inline alternatives are named after name and their position.
+func createAlternatives(name string, alternatives []*oasmodel.SchemaOrRef, discriminator *oasmodel.Discriminator, parent *Message, genOpts GenerationOptions) ([]MessageMembers, error) {
+	var members []MessageMembers
+	for i, prop := range alternatives {
+		t, err := CreateType(fmt.Sprintf("%sOption%d", name, i+1), prop, parent, genOpts)
 		if err != nil {
 			return nil, err
 		}
@@ -175,36 +271,147 @@ func createOneOf(name string, oneof []*oasmodel.SchemaOrRef, parent *Message, ge
 		if index >= 0 {
 			fieldname = fieldname[index+1:]
 		}
-		f := MessageMembers{t, fieldname + "Value", num, isRepeated(prop), prop.Description()}
-		node.members = append(node.members, f)
+		fieldname += "Value"
+		if value := discriminatorValue(discriminator, prop); value != "" {
+			fieldname = identifier(value)
+		}
+		f := MessageMembers{t, fieldname, 0, isRepeated(prop), propertyComment(prop), false, nil, nil}
+		f.options = fieldOptions(prop)
+		members = append(members, f)
+	}
+	return members, nil
+}
+
+// fieldOptions : deprecated and x-proto-options of a property
+func fieldOptions(prop *oasmodel.SchemaOrRef) []Option {
+	var options []Option
+	if prop.Ref == nil && prop.Val != nil && prop.Val.Deprecated {
+		options = append(options, Option{"deprecated", "true"})
+	}
+	custom := prop.ProtoOptions()
+	names := make([]string, 0, len(custom))
+	for n := range custom {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	for _, n := range names {
+		options = append(options, Option{n, optionValue(custom[n])})
+	}
+	return options
+}
+
+// jsonName : lowerCamelCase name protoc derives from a field name
+func jsonName(name string) string {
+	var b strings.Builder
+	upper := false
+	for _, r := range name {
+		if r == '_' {
+			upper = true
+			continue
+		}
+		if upper {
+			r = unicode.ToUpper(r)
+			upper = false
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+var enumConstantRe = regexp.MustCompile(`^[A-Z][A-Z0-9_]*$`)
+
+// optionValue gives the proto constant of an option value, strings are quoted unless they are enum constants
+func optionValue(value interface{}) string {
+	switch v := value.(type) {
+	case string:
+		if enumConstantRe.MatchString(v) {
+			return v
+		}
+		return strconv.Quote(v)
+	case nil:
+		return `""`
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// uniqueNames renames members whose name is already used, by appending their position
+func uniqueNames(members []MessageMembers, used map[string]bool) {
+	for i := range members {
+		name := members[i].name
+		if used[name] {
+			name = fmt.Sprintf("%s%d", name, i+1)
+		}
+		for used[name] {
+			name += "_"
+		}
+		members[i].name = name
+		used[name] = true
 	}
-	return &node, nil
 }
 
 func createAllOf(name string, allOf []*oasmodel.SchemaOrRef, parent *Message, genOpts GenerationOptions) (ProtoType, error) {
-	node := Message{name, nil, nil, ""}
-	num := 0
+	node := newMessage(name, "", parent)
 
-	// parse all allOf members
+	// properties of all allOf members
+	var keys, required []string
+	properties := make(map[string]*oasmodel.SchemaOrRef)
 	for _, val := range allOf {
 		current := val.Schema()
-		var keys []string
+		required = append(required, current.Required...)
+		var currentKeys []string
 		if len(current.XPropertiesOrder) > 0 {
-			keys = current.XPropertiesOrder
+			currentKeys = current.XPropertiesOrder
 		} else {
-			keys = keysorder(current.Properties)
-		}
-		for _, m := range keys {
-			num++
-			prop := current.Properties[m]
-			f := MessageMembers{nil, m, num, isRepeated(prop), prop.Description()}
-			t, err := CreateType(name+"_"+m, prop, &node, genOpts)
-			if err != nil {
-				return nil, err
+			currentKeys = keysorder(current.Properties)
+		}
+		for _, m := range currentKeys {
+			if current.Properties[m] == nil {
+				return nil, fmt.Errorf("%s : bad property name %s", name, m)
 			}
-			f.typedecl = t
-			node.body = append(node.body, f)
+			if properties[m] == nil {
+				keys = append(keys, m)
+			}
+			properties[m] = current.Properties[m]
 		}
 	}
+	err := node.createFields(keys, properties, required, func(m string) string { return name + "_" + m }, genOpts)
+	if err != nil {
+		return nil, err
+	}
 	return &node, nil
 }
+
+// discriminatorValue gives the discriminator value selecting an alternative : its mapping key, or the schema name by default
+func discriminatorValue(discriminator *oasmodel.Discriminator, alternative *oasmodel.SchemaOrRef) string {
+	if discriminator == nil || alternative.Ref == nil {
+		return ""
+	}
+	values := make([]string, 0, len(discriminator.Mapping))
+	for value := range discriminator.Mapping {
+		values = append(values, value)
+	}
+	sort.Strings(values)
+	ref := alternative.Ref
+	for _, value := range values {
+		target := discriminator.Mapping[value]
+		if ref.Ref == target || ref.RefName == target || strings.HasSuffix(ref.Ref, "/"+target) {
+			return value
+		}
+	}
+	return ref.RefName
+}
+
+// identifier replaces characters not allowed in proto identifiers
+func identifier(name string) string {
+	id := []rune(name)
+	for i, r := range id {
+		if !(r == '_' || unicode.IsLetter(r) && r < unicode.MaxASCII || unicode.IsDigit(r) && r < unicode.MaxASCII) {
+			id[i] = '_'
+		}
+	}
+	if len(id) > 0 && unicode.IsDigit(id[0]) {
+		return "_" + string(id)
+	}
+	return string(id)
+}
@@ -0,0 +1,571 @@
+package protobuf
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/Axili39/oastools/oasmodel"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+// componentsPrefix : reference to a component schema
+const componentsPrefix = "#/components/schemas/"
+
+// scalarSchemas : schema of each scalar kind, reverse of the default type mapping
+var scalarSchemas = map[protoreflect.Kind]oasmodel.Schema{
+	protoreflect.DoubleKind:   {Type: oasmodel.SchemaType{"number"}, Format: "double"},
+	protoreflect.FloatKind:    {Type: oasmodel.SchemaType{"number"}, Format: "float"},
+	protoreflect.Int32Kind:    {Type: oasmodel.SchemaType{"integer"}, Format: "int32"},
+	protoreflect.Int64Kind:    {Type: oasmodel.SchemaType{"integer"}, Format: "int64"},
+	protoreflect.Uint32Kind:   {Type: oasmodel.SchemaType{"integer"}, Format: "uint32"},
+	protoreflect.Uint64Kind:   {Type: oasmodel.SchemaType{"integer"}, Format: "uint64"},
+	protoreflect.Sint32Kind:   {Type: oasmodel.SchemaType{"integer"}, Format: "sint32"},
+	protoreflect.Sint64Kind:   {Type: oasmodel.SchemaType{"integer"}, Format: "sint64"},
+	protoreflect.Fixed32Kind:  {Type: oasmodel.SchemaType{"integer"}, Format: "fixed32"},
+	protoreflect.Fixed64Kind:  {Type: oasmodel.SchemaType{"integer"}, Format: "fixed64"},
+	protoreflect.Sfixed32Kind: {Type: oasmodel.SchemaType{"integer"}, Format: "sfixed32"},
+	protoreflect.Sfixed64Kind: {Type: oasmodel.SchemaType{"integer"}, Format: "sfixed64"},
+	protoreflect.BoolKind:     {Type: oasmodel.SchemaType{"boolean"}},
+	protoreflect.StringKind:   {Type: oasmodel.SchemaType{"string"}},
+	protoreflect.BytesKind:    {Type: oasmodel.SchemaType{"string"}, Format: "byte"},
+}
+
+// wellKnownSchemas : schema of well-known types, wrappers are nullable scalars
+var wellKnownSchemas = map[protoreflect.FullName]oasmodel.Schema{
+	"google.protobuf.Timestamp":   {Type: oasmodel.SchemaType{"string"}, Format: "date-time"},
+	"google.protobuf.Duration":    {Type: oasmodel.SchemaType{"string"}, Format: "duration"},
+	"google.protobuf.FieldMask":   {Type: oasmodel.SchemaType{"string"}},
+	"google.protobuf.Struct":      {Type: oasmodel.SchemaType{"object"}},
+	"google.protobuf.Any":         {Type: oasmodel.SchemaType{"object"}},
+	"google.protobuf.Empty":       {Type: oasmodel.SchemaType{"object"}},
+	"google.protobuf.Value":       {},
+	"google.protobuf.ListValue":   {Type: oasmodel.SchemaType{"array"}, Items: &oasmodel.SchemaOrRef{Val: &oasmodel.Schema{}}},
+	"google.protobuf.DoubleValue": {Type: oasmodel.SchemaType{"number"}, Format: "double", Nullable: true},
+	"google.protobuf.FloatValue":  {Type: oasmodel.SchemaType{"number"}, Format: "float", Nullable: true},
+	"google.protobuf.Int64Value":  {Type: oasmodel.SchemaType{"integer"}, Format: "int64", Nullable: true},
+	"google.protobuf.UInt64Value": {Type: oasmodel.SchemaType{"integer"}, Format: "uint64", Nullable: true},
+	"google.protobuf.Int32Value":  {Type: oasmodel.SchemaType{"integer"}, Format: "int32", Nullable: true},
+	"google.protobuf.UInt32Value": {Type: oasmodel.SchemaType{"integer"}, Format: "uint32", Nullable: true},
+	"google.protobuf.BoolValue":   {Type: oasmodel.SchemaType{"boolean"}, Nullable: true},
+	"google.protobuf.StringValue": {Type: oasmodel.SchemaType{"string"}, Nullable: true},
+	"google.protobuf.BytesValue":  {Type: oasmodel.SchemaType{"string"}, Format: "byte", Nullable: true},
+}
+
+// reverser converts declared types to component schemas
+type reverser struct {
+	names  map[protoreflect.FullName]string // component name of types not inlined
+	arrays map[protoreflect.FullName]bool   // messages wrapping an array component
+}
+
+// Proto2OpenAPI converts messages and enums of files to components of an OpenAPI document, services are ignored.
+// Types of the files named in roots, all but well-known types files if nil, are converted with the types they use.
+// Nested types used by a single field of their parent are inlined, others are named Parent_Nested.
+// Properties keep the order and numbers of fields with x-properties-order and x-proto-field-number,
+// so that oa2proto -no-msg-prefix generates the same messages.
+func Proto2OpenAPI(files []*descriptorpb.FileDescriptorProto, roots []string) (*oasmodel.OpenAPI, error) {
+	registry, err := NewFiles(files)
+	if err != nil {
+		return nil, err
+	}
+	if roots == nil {
+		for _, f := range files {
+			if !strings.HasPrefix(f.GetName(), "google/protobuf/") {
+				roots = append(roots, f.GetName())
+			}
+		}
+	}
+	if len(roots) == 0 {
+		return nil, fmt.Errorf("no file to convert")
+	}
+
+	// types of roots, then types they use
+	var declared []protoreflect.Descriptor
+	seen := make(map[protoreflect.FullName]bool)
+	uses := make(map[protoreflect.FullName][]protoreflect.FieldDescriptor)
+	add := func(d protoreflect.Descriptor) {
+		if !seen[d.FullName()] {
+			seen[d.FullName()] = true
+			declared = append(declared, d)
+		}
+	}
+	for _, root := range roots {
+		fd, err := registry.FindFileByPath(root)
+		if err != nil {
+			return nil, err
+		}
+		addTypes(fd.Messages(), fd.Enums(), add)
+	}
+	for i := 0; i < len(declared); i++ {
+		md, ok := declared[i].(protoreflect.MessageDescriptor)
+		if !ok {
+			continue
+		}
+		addTypes(md.Messages(), md.Enums(), add)
+		fields := md.Fields()
+		for j := 0; j < fields.Len(); j++ {
+			f := fields.Get(j)
+			target := referencedType(f)
+			if target == nil {
+				continue
+			}
+			if target.IsPlaceholder() {
+				return nil, fmt.Errorf("%s : type %s not found, add its import path with -I", f.FullName(), strings.TrimPrefix(string(target.FullName()), "*."))
+			}
+			if _, ok := wellKnownSchemas[target.FullName()]; ok {
+				continue
+			}
+			uses[target.FullName()] = append(uses[target.FullName()], f)
+			add(target)
+		}
+	}
+
+	// top level types are named first, nested types used once are inlined in their parent
+	sort.SliceStable(declared, func(i, j int) bool {
+		di, dj := depth(declared[i]), depth(declared[j])
+		if di != dj {
+			return di < dj
+		}
+		return declared[i].FullName() < declared[j].FullName()
+	})
+	r := &reverser{names: make(map[protoreflect.FullName]string), arrays: make(map[protoreflect.FullName]bool)}
+	taken := make(map[string]bool)
+	var components []protoreflect.Descriptor
+	for _, d := range declared {
+		if parent, ok := d.Parent().(protoreflect.MessageDescriptor); ok {
+			f := uses[d.FullName()]
+			if len(f) == 1 && f[0].ContainingMessage().FullName() == parent.FullName() {
+				continue
+			}
+		}
+		components = append(components, d)
+		taken[componentName(d)] = true
+	}
+	for _, d := range components {
+		name := componentName(d)
+		if md, ok := d.(protoreflect.MessageDescriptor); ok && isArrayMessage(md) && !taken[strings.TrimSuffix(name, "Array")] {
+			name = strings.TrimSuffix(name, "Array")
+			r.arrays[d.FullName()] = true
+			taken[name] = true
+		} else if r.used(name) {
+			name = strings.Replace(string(d.FullName()), ".", "_", -1)
+		}
+		r.names[d.FullName()] = name
+	}
+
+	oa := &oasmodel.OpenAPI{
+		Openapi:    "3.0.0",
+		Paths:      map[string]oasmodel.PathItem{},
+		Components: oasmodel.Components{Schemas: make(map[string]*oasmodel.SchemaOrRef)},
+	}
+	if first, err := registry.FindFileByPath(roots[0]); err == nil && first.Package() != "" {
+		oa.Info = oasmodel.Info{Title: string(first.Package()), Version: "1.0.0", XPackage: string(first.Package())}
+	} else {
+		oa.Info = oasmodel.Info{Title: strings.TrimSuffix(roots[0][strings.LastIndex(roots[0], "/")+1:], ".proto"), Version: "1.0.0"}
+	}
+	for _, d := range components {
+		var schema *oasmodel.Schema
+		switch d := d.(type) {
+		case protoreflect.MessageDescriptor:
+			schema = r.messageSchema(d)
+		case protoreflect.EnumDescriptor:
+			schema = enumSchema(d)
+		}
+		oa.Components.Schemas[r.names[d.FullName()]] = &oasmodel.SchemaOrRef{Val: schema}
+	}
+	return oa, nil
+}
+
+// addTypes adds messages and enums, map entries are part of their field
+func addTypes(messages protoreflect.MessageDescriptors, enums protoreflect.EnumDescriptors, add func(protoreflect.Descriptor)) {
+	for i := 0; i < messages.Len(); i++ {
+		if !messages.Get(i).IsMapEntry() {
+			add(messages.Get(i))
+		}
+	}
+	for i := 0; i < enums.Len(); i++ {
+		add(enums.Get(i))
+	}
+}
+
+// referencedType returns the message or enum of a field or of its map values, nil for scalars
+func referencedType(f protoreflect.FieldDescriptor) protoreflect.Descriptor {
+	if f.IsMap() {
+		f = f.MapValue()
+	}
+	switch f.Kind() {
+	case protoreflect.MessageKind, protoreflect.GroupKind:
+		return f.Message()
+	case protoreflect.EnumKind:
+		return f.Enum()
+	}
+	// types not found have no kind
+	if f.Message() != nil {
+		return f.Message()
+	}
+	return nil
+}
+
+func depth(d protoreflect.Descriptor) int {
+	n := 0
+	for p := d.Parent(); p != nil; p = p.Parent() {
+		n++
+	}
+	return n
+}
+
+// componentName : name of a type in its package, with _ between nested names
+func componentName(d protoreflect.Descriptor) string {
+	name := strings.TrimPrefix(string(d.FullName()), string(d.ParentFile().Package())+".")
+	return strings.Replace(name, ".", "_", -1)
+}
+
+// used returns true if a component is already named name
+func (r *reverser) used(name string) bool {
+	for _, n := range r.names {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}
+
+// isArrayMessage returns true for top level XArray messages generated from array components
+func isArrayMessage(md protoreflect.MessageDescriptor) bool {
+	if _, ok := md.Parent().(protoreflect.FileDescriptor); !ok || !strings.HasSuffix(string(md.Name()), "Array") {
+		return false
+	}
+	fields := md.Fields()
+	return fields.Len() == 1 && md.Oneofs().Len() == 0 && fields.Get(0).Name() == "Items" && fields.Get(0).Number() == 1 &&
+		fields.Get(0).IsList()
+}
+
+// selectOneof returns the oneof of messages generated from oneOf components
+func selectOneof(md protoreflect.MessageDescriptor) protoreflect.OneofDescriptor {
+	oneofs := md.Oneofs()
+	if oneofs.Len() != 1 || oneofs.Get(0).Name() != "select" || oneofs.Get(0).IsSynthetic() {
+		return nil
+	}
+	if oneofs.Get(0).Fields().Len() != md.Fields().Len() {
+		return nil
+	}
+	return oneofs.Get(0)
+}
+
+// description returns leading comments of a declaration
+func description(d protoreflect.Descriptor) string {
+	comments := d.ParentFile().SourceLocations().ByDescriptor(d).LeadingComments
+	lines := strings.Split(comments, "\n")
+	for i, l := range lines {
+		lines[i] = strings.TrimPrefix(l, " ")
+	}
+	return strings.TrimSpace(strings.Join(lines, "\n"))
+}
+
+// withoutDeprecated removes the Deprecated. line added to comments of deprecated fields
+func withoutDeprecated(comment string) string {
+	lines := strings.Split(comment, "\n")
+	for i, l := range lines {
+		if l == "Deprecated." {
+			return strings.TrimSpace(strings.Join(append(lines[:i:i], lines[i+1:]...), "\n"))
+		}
+	}
+	return comment
+}
+
+func (r *reverser) ref(d protoreflect.Descriptor) *oasmodel.SchemaOrRef {
+	name := r.names[d.FullName()]
+	return &oasmodel.SchemaOrRef{Ref: &oasmodel.Ref{Ref: componentsPrefix + name, RefName: name}}
+}
+
+func (r *reverser) messageSchema(md protoreflect.MessageDescriptor) *oasmodel.Schema {
+	schema := &oasmodel.Schema{Description: description(md)}
+	if r.arrays[md.FullName()] {
+		schema.Type = oasmodel.SchemaType{"array"}
+		schema.Items = r.typeSchema(md.Fields().Get(0))
+		setDescription(schema.Items, description(md.Fields().Get(0)))
+		return schema
+	}
+	if o := selectOneof(md); o != nil {
+		schema.OneOf, schema.Discriminator = r.alternatives(o)
+		return schema
+	}
+	schema.Type = oasmodel.SchemaType{"object"}
+	schema.Properties = make(map[string]*oasmodel.SchemaOrRef)
+	fields := md.Fields()
+	for i := 0; i < fields.Len(); i++ {
+		f := fields.Get(i)
+		if o := f.ContainingOneof(); o != nil && !o.IsSynthetic() {
+			name := string(o.Name())
+			if schema.Properties[name] != nil {
+				continue
+			}
+			prop := &oasmodel.Schema{Description: description(o)}
+			prop.OneOf, prop.Discriminator = r.alternatives(o)
+			schema.Properties[name] = &oasmodel.SchemaOrRef{Val: prop}
+			schema.XPropertiesOrder = append(schema.XPropertiesOrder, name)
+			continue
+		}
+		name := string(f.Name())
+		deprecated, options := specOptions(f)
+		if f.JSONName() != jsonName(name) {
+			// the property is the JSON name when it gives back the field name, json_name is recorded otherwise
+			if normalizeName(f.JSONName()) == name {
+				name = f.JSONName()
+			} else {
+				options["json_name"] = f.JSONName()
+			}
+		}
+		prop := r.fieldSchema(f)
+		setNumber(prop, int(f.Number()))
+		comment := description(f)
+		if deprecated {
+			comment = withoutDeprecated(comment)
+		}
+		setDescription(prop, comment)
+		if prop.Ref != nil {
+			if deprecated {
+				options["deprecated"] = true
+			}
+			if len(options) > 0 {
+				prop.Ref.XProtoOptions = options
+			}
+		} else {
+			prop.Val.Deprecated = deprecated
+			if len(options) > 0 {
+				prop.Val.XProtoOptions = options
+			}
+		}
+		if f.HasOptionalKeyword() && prop.Val != nil {
+			prop.Val.Nullable = true
+		}
+		if isRequiredField(f) {
+			schema.Required = append(schema.Required, name)
+		}
+		schema.Properties[name] = prop
+		schema.XPropertiesOrder = append(schema.XPropertiesOrder, name)
+	}
+	return schema
+}
+
+// alternatives converts oneof members, a discriminator maps member names which aren't generated from their type
+func (r *reverser) alternatives(o protoreflect.OneofDescriptor) ([]*oasmodel.SchemaOrRef, *oasmodel.Discriminator) {
+	var alternatives []*oasmodel.SchemaOrRef
+	named := false
+	fields := o.Fields()
+	for i := 0; i < fields.Len(); i++ {
+		f := fields.Get(i)
+		alternative := r.typeSchema(f)
+		setNumber(alternative, int(f.Number()))
+		setDescription(alternative, description(f))
+		if alternative.Ref != nil && string(f.Name()) != alternative.Ref.RefName+"Value" {
+			named = true
+		}
+		alternatives = append(alternatives, alternative)
+	}
+	if !named {
+		return alternatives, nil
+	}
+	// with a discriminator, members not mapped are named after their type
+	discriminator := &oasmodel.Discriminator{PropertyName: string(o.Name()), Mapping: make(map[string]string)}
+	for i, alternative := range alternatives {
+		name := string(fields.Get(i).Name())
+		if alternative.Ref != nil && name != alternative.Ref.RefName {
+			discriminator.Mapping[name] = alternative.Ref.Ref
+		}
+	}
+	return alternatives, discriminator
+}
+
+// fieldSchema : schema of a field, maps are objects with additional properties
+func (r *reverser) fieldSchema(f protoreflect.FieldDescriptor) *oasmodel.SchemaOrRef {
+	if f.IsMap() {
+		return &oasmodel.SchemaOrRef{Val: &oasmodel.Schema{
+			Type:                 oasmodel.SchemaType{"object"},
+			AdditionalProperties: &oasmodel.AdditionalProperties{Schema: r.typeSchema(f.MapValue())},
+		}}
+	}
+	item := r.typeSchema(f)
+	if f.IsList() {
+		return &oasmodel.SchemaOrRef{Val: &oasmodel.Schema{Type: oasmodel.SchemaType{"array"}, Items: item}}
+	}
+	return item
+}
+
+// typeSchema : schema of the type of a field, a reference for components
+func (r *reverser) typeSchema(f protoreflect.FieldDescriptor) *oasmodel.SchemaOrRef {
+	switch f.Kind() {
+	case protoreflect.MessageKind, protoreflect.GroupKind:
+		md := f.Message()
+		if schema, ok := wellKnownSchemas[md.FullName()]; ok {
+			return &oasmodel.SchemaOrRef{Val: &schema}
+		}
+		if _, ok := r.names[md.FullName()]; ok {
+			return r.ref(md)
+		}
+		return &oasmodel.SchemaOrRef{Val: r.messageSchema(md)}
+	case protoreflect.EnumKind:
+		ed := f.Enum()
+		if _, ok := r.names[ed.FullName()]; ok {
+			return r.ref(ed)
+		}
+		return &oasmodel.SchemaOrRef{Val: enumSchema(ed)}
+	}
+	schema := scalarSchemas[f.Kind()]
+	return &oasmodel.SchemaOrRef{Val: &schema}
+}
+
+func setNumber(s *oasmodel.SchemaOrRef, number int) {
+	if s.Ref != nil {
+		s.Ref.XProtoFieldNumber = number
+	} else {
+		s.Val.XProtoFieldNumber = number
+	}
+}
+
+func setDescription(s *oasmodel.SchemaOrRef, description string) {
+	if description == "" {
+		return
+	}
+	if s.Ref != nil {
+		s.Ref.Description = description
+	} else {
+		s.Val.Description = description
+	}
+}
+
+// enumSchema : enums numbered from 0 in order are string enums, others are integer enums
+func enumSchema(ed protoreflect.EnumDescriptor) *oasmodel.Schema {
+	schema := &oasmodel.Schema{Description: description(ed)}
+	values := ed.Values()
+	sequential := true
+	for i := 0; i < values.Len(); i++ {
+		if values.Get(i).Number() != protoreflect.EnumNumber(i) {
+			sequential = false
+		}
+	}
+	var names, descriptions []string
+	renamed, described := !sequential, false
+	for i := 0; i < values.Len(); i++ {
+		v := values.Get(i)
+		name := string(v.Name())
+		value := strconv.Itoa(int(v.Number()))
+		if sequential {
+			value = name
+			if jsonValue, ok := enumValueOption(v); ok {
+				value = jsonValue
+				renamed = renamed || value != name
+			}
+		}
+		names = append(names, name)
+		schema.Enum = append(schema.Enum, value)
+		descriptions = append(descriptions, description(v))
+		described = described || descriptions[i] != ""
+	}
+	if sequential {
+		schema.Type = oasmodel.SchemaType{"string"}
+	} else {
+		schema.Type = oasmodel.SchemaType{"integer"}
+	}
+	if renamed {
+		schema.XEnumVarnames = names
+	}
+	if described {
+		schema.XEnumDescriptions = descriptions
+	}
+	return schema
+}
+
+// enumValueOption returns the spec value recorded by the (json_value) option
+func enumValueOption(v protoreflect.EnumValueDescriptor) (string, bool) {
+	opts, ok := v.Options().(*descriptorpb.EnumValueOptions)
+	if !ok || opts == nil {
+		return "", false
+	}
+	for _, u := range opts.UninterpretedOption {
+		if uninterpretedName(u) == jsonValueOption && u.StringValue != nil {
+			return string(u.StringValue), true
+		}
+	}
+	return "", false
+}
+
+func uninterpretedName(u *descriptorpb.UninterpretedOption) string {
+	var parts []string
+	for _, p := range u.Name {
+		if p.GetIsExtension() {
+			parts = append(parts, "("+p.GetNamePart()+")")
+		} else {
+			parts = append(parts, p.GetNamePart())
+		}
+	}
+	return strings.Join(parts, ".")
+}
+
+// isRequiredField returns true for proto2 required fields and fields with the (required) option
+func isRequiredField(f protoreflect.FieldDescriptor) bool {
+	if f.Cardinality() == protoreflect.Required {
+		return true
+	}
+	opts, ok := f.Options().(*descriptorpb.FieldOptions)
+	if !ok || opts == nil {
+		return false
+	}
+	for _, u := range opts.UninterpretedOption {
+		if uninterpretedName(u) == requiredOption && u.GetIdentifierValue() == "true" {
+			return true
+		}
+	}
+	return false
+}
+
+// specOptions returns deprecated and options kept as x-proto-options,
+// options generated from other keywords and aggregate values are dropped
+func specOptions(f protoreflect.FieldDescriptor) (bool, map[string]interface{}) {
+	options := make(map[string]interface{})
+	opts, ok := f.Options().(*descriptorpb.FieldOptions)
+	if !ok || opts == nil {
+		return false, options
+	}
+	opts.ProtoReflect().Range(func(fd protoreflect.FieldDescriptor, v protoreflect.Value) bool {
+		switch {
+		case fd.Name() == "deprecated" || fd.Name() == "uninterpreted_option" || fd.IsExtension():
+		case fd.Kind() == protoreflect.EnumKind:
+			if value := fd.Enum().Values().ByNumber(v.Enum()); value != nil {
+				options[string(fd.Name())] = string(value.Name())
+			}
+		default:
+			options[string(fd.Name())] = v.Interface()
+		}
+		return true
+	})
+	for _, u := range opts.UninterpretedOption {
+		name := uninterpretedName(u)
+		switch {
+		case name == requiredOption || u.AggregateValue != nil:
+		case u.IdentifierValue != nil:
+			switch u.GetIdentifierValue() {
+			case "true", "false":
+				options[name] = u.GetIdentifierValue() == "true"
+			default:
+				options[name] = u.GetIdentifierValue()
+			}
+		case u.StringValue != nil:
+			options[name] = string(u.StringValue)
+		case u.PositiveIntValue != nil:
+			if u.GetPositiveIntValue() <= math.MaxInt64 {
+				options[name] = int64(u.GetPositiveIntValue())
+			} else {
+				options[name] = u.GetPositiveIntValue()
+			}
+		case u.NegativeIntValue != nil:
+			options[name] = u.GetNegativeIntValue()
+		case u.DoubleValue != nil:
+			options[name] = u.GetDoubleValue()
+		}
+	}
+	return opts.GetDeprecated(), options
+}
@@ -0,0 +1,107 @@
+package protobuf
+
+import (
+	"bytes"
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	"github.com/Axili39/oastools/oasmodel"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+func TestProto2OpenAPI(t *testing.T) {
+	// generated comments with constraints, anyOf and imports aren't converted back as is
+	for _, name := range []string{"addprop", "allof", "array", "basictypes", "child", "enum", "formats", "maps", "message", "nestedoneof", "oneof", "options"} {
+		match := "tests/" + name + ".proto"
+		expected, err := ioutil.ReadFile(match)
+		if err != nil {
+			t.Fatal(err)
+		}
+		file, err := ParseProto(name+".proto", bytes.NewReader(expected))
+		if err != nil {
+			t.Fatalf("error parsing %s : %v", match, err)
+		}
+		oa, err := Proto2OpenAPI([]*descriptorpb.FileDescriptorProto{file}, nil)
+		if err != nil {
+			t.Errorf("error converting %s : %v", match, err)
+			continue
+		}
+		output := &bytes.Buffer{}
+		err = Components2Proto(oa, output, "", GenerationOptions{Imports: map[string]bool{}}, nil)
+		if err != nil {
+			t.Errorf("error generating %s : %v", match, err)
+		}
+		if output.String() != string(expected) {
+			t.Errorf("Result differ for %s \ngot:\n%s\nexpected:\n%s", match, output.String(), string(expected))
+		}
+	}
+}
+
+func TestProto2OpenAPIImports(t *testing.T) {
+	files, err := ParseProtoFiles([]string{"tests/tree/proto/api.proto"}, []string{"tests/tree/proto"})
+	if err != nil {
+		t.Fatalf("error parsing : %v", err)
+	}
+	oa, err := Proto2OpenAPI(files, []string{"api.proto"})
+	if err != nil {
+		t.Fatalf("error converting : %v", err)
+	}
+	if oa.Info.XPackage != "pets.v1" {
+		t.Errorf("bad package %s", oa.Info.XPackage)
+	}
+	// imported types are converted when used
+	for _, name := range []string{"pet", "person", "status", "GetPetRequest"} {
+		if oa.Components.Schemas[name] == nil {
+			t.Errorf("component %s not found", name)
+		}
+	}
+	pet := oa.Components.Schemas["pet"].Val
+	if len(pet.Required) != 1 || pet.Required[0] != "name" || pet.Properties["owner"].Ref == nil {
+		t.Errorf("bad pet %+v", pet)
+	}
+
+	_, err = Proto2OpenAPI(files[:1], nil)
+	if err == nil {
+		t.Errorf("missing imports must fail")
+	}
+	_, err = Proto2OpenAPI(nil, nil)
+	if err == nil {
+		t.Errorf("no file must fail")
+	}
+}
+
+func TestProto2OpenAPIEnumNumbers(t *testing.T) {
+	source := `syntax = "proto3";
+
+enum level {
+	LEVEL_UNSPECIFIED = 0;
+	LEVEL_LOW = 1;
+	LEVEL_HIGH = 5;
+}
+`
+	file, err := ParseProto("level.proto", strings.NewReader(source))
+	if err != nil {
+		t.Fatalf("error parsing : %v", err)
+	}
+	oa, err := Proto2OpenAPI([]*descriptorpb.FileDescriptorProto{file}, nil)
+	if err != nil {
+		t.Fatalf("error converting : %v", err)
+	}
+	// integer enums have number values
+	spec, err := oa.Marshal(oasmodel.FormatYAML)
+	if err != nil {
+		t.Fatalf("error marshalling : %v", err)
+	}
+	if !strings.Contains(string(spec), "- 5\n") || strings.Contains(string(spec), `"5"`) {
+		t.Errorf("bad enum values :\n%s", spec)
+	}
+	output := &bytes.Buffer{}
+	err = Components2Proto(oa, output, "", GenerationOptions{Imports: map[string]bool{}}, nil)
+	if err != nil {
+		t.Fatalf("error generating : %v", err)
+	}
+	if output.String() != source {
+		t.Errorf("Result differ \ngot:\n%s\nexpected:\n%s", output.String(), source)
+	}
+}
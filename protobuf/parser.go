@@ -0,0 +1,879 @@
+package protobuf
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"math"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+// token kinds of .proto files
+const (
+	tokenEOF = iota
+	tokenIdent
+	tokenNumber
+	tokenString
+	tokenSymbol
+)
+
+type token struct {
+	kind     int
+	text     string // value of strings, decimal value of integers
+	line     int    // 0-based, as source code info spans
+	col      int
+	comments string // leading comments, as protoc records them
+}
+
+// parseError stops the parser, it is recovered by ParseProto
+type parseError struct {
+	err error
+}
+
+// ParseProto parses a .proto file to the descriptor of file name, as protoc does before linking it :
+// type names are kept as written, leading comments are recorded in the source code info.
+// Descriptors are built with NewFiles.
+func ParseProto(name string, r io.Reader) (file *descriptorpb.FileDescriptorProto, err error) {
+	src, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	tokens, err := tokenize(string(src))
+	if err != nil {
+		return nil, fmt.Errorf("%s:%v", name, err)
+	}
+	p := &parser{name: name, tokens: tokens}
+	defer func() {
+		if r := recover(); r != nil {
+			e, ok := r.(parseError)
+			if !ok {
+				panic(r)
+			}
+			file, err = nil, e.err
+		}
+	}()
+	return p.parseFile(), nil
+}
+
+// ParseProtoFiles parses filenames and the files they import, found in importPaths as protoc does.
+// Files are named relative to their import path, well-known types and imports not found are left to NewFiles.
+// Files named by filenames come first, in order, followed by imports.
+func ParseProtoFiles(filenames []string, importPaths []string) ([]*descriptorpb.FileDescriptorProto, error) {
+	if len(importPaths) == 0 {
+		importPaths = []string{"."}
+	}
+	var files []*descriptorpb.FileDescriptorProto
+	parsed := make(map[string]bool)
+	parse := func(name string, filename string) error {
+		if parsed[name] {
+			return nil
+		}
+		parsed[name] = true
+		f, err := os.Open(filename)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		file, err := ParseProto(name, f)
+		if err != nil {
+			return err
+		}
+		files = append(files, file)
+		return nil
+	}
+	for _, filename := range filenames {
+		if err := parse(importName(filename, importPaths), filename); err != nil {
+			return nil, err
+		}
+	}
+	for i := 0; i < len(files); i++ {
+		for _, dep := range files[i].GetDependency() {
+			if _, err := protoregistry.GlobalFiles.FindFileByPath(dep); err == nil {
+				continue
+			}
+			for _, dir := range importPaths {
+				candidate := filepath.Join(dir, filepath.FromSlash(dep))
+				if _, err := os.Stat(candidate); err == nil {
+					if err := parse(dep, candidate); err != nil {
+						return nil, err
+					}
+					break
+				}
+			}
+		}
+	}
+	return files, nil
+}
+
+// importName : name of a file relative to the first import path holding it, or as given
+func importName(filename string, importPaths []string) string {
+	abs, err := filepath.Abs(filename)
+	if err != nil {
+		return filepath.ToSlash(filename)
+	}
+	for _, dir := range importPaths {
+		dir, err := filepath.Abs(dir)
+		if err != nil {
+			continue
+		}
+		if rel, err := filepath.Rel(dir, abs); err == nil && !strings.HasPrefix(rel, "..") {
+			return filepath.ToSlash(rel)
+		}
+	}
+	return filepath.ToSlash(filename)
+}
+
+// tokenize splits src in tokens, comments are attached to the token following them unless a blank line separates them
+func tokenize(src string) ([]token, error) {
+	var tokens []token
+	line, col := 0, 0
+	var comments []string
+	commentEnd := -1 // line of the last comment
+	lastLine := -1   // line of the last token, comments on it are trailing comments
+	advance := func(n int) {
+		for _, r := range src[:n] {
+			if r == '\n' {
+				line++
+				col = 0
+			} else {
+				col++
+			}
+		}
+		src = src[n:]
+	}
+	addComment := func(startLine int, text []string) {
+		if startLine == lastLine {
+			return
+		}
+		if len(comments) > 0 && startLine > commentEnd+1 {
+			comments = nil
+		}
+		comments = append(comments, text...)
+		commentEnd = line
+	}
+	for len(src) > 0 {
+		c := src[0]
+		switch {
+		case c == ' ' || c == '\t' || c == '\r' || c == '\n' || c == '\f' || c == '\v':
+			advance(1)
+			continue
+		case strings.HasPrefix(src, "//"):
+			end := strings.IndexByte(src, '\n')
+			if end < 0 {
+				end = len(src)
+			}
+			startLine := line
+			text := strings.TrimRight(src[2:end], "\r")
+			advance(end)
+			addComment(startLine, []string{text})
+			continue
+		case strings.HasPrefix(src, "/*"):
+			end := strings.Index(src, "*/")
+			if end < 0 {
+				return nil, fmt.Errorf("%d:%d: unterminated comment", line+1, col+1)
+			}
+			startLine := line
+			var text []string
+			for _, l := range strings.Split(strings.TrimRight(src[2:end], " \t\r\n"), "\n") {
+				l = strings.TrimLeft(l, " \t\r")
+				if strings.HasPrefix(l, "*") {
+					l = l[1:]
+				}
+				text = append(text, l)
+			}
+			advance(end + 2)
+			addComment(startLine, text)
+			continue
+		}
+
+		t := token{line: line, col: col}
+		if len(comments) > 0 && line <= commentEnd+1 {
+			t.comments = strings.Join(comments, "\n") + "\n"
+		}
+		comments = nil
+		var n int
+		switch {
+		case isLetter(c):
+			for n = 1; n < len(src) && (isLetter(src[n]) || isDigit(src[n])); n++ {
+			}
+			t.kind, t.text = tokenIdent, src[:n]
+		case isDigit(c) || c == '.' && len(src) > 1 && isDigit(src[1]):
+			for n = 1; n < len(src); n++ {
+				exponent := (src[n-1] == 'e' || src[n-1] == 'E') && !strings.HasPrefix(src, "0x") && !strings.HasPrefix(src, "0X")
+				if !(isLetter(src[n]) || isDigit(src[n]) || src[n] == '.' || exponent && (src[n] == '+' || src[n] == '-')) {
+					break
+				}
+			}
+			number, ok := protoNumber(src[:n])
+			if !ok {
+				return nil, fmt.Errorf("%d:%d: bad number %s", line+1, col+1, src[:n])
+			}
+			t.kind, t.text = tokenNumber, number
+		case c == '"' || c == '\'':
+			value, length, err := unquote(src)
+			if err != nil {
+				return nil, fmt.Errorf("%d:%d: %v", line+1, col+1, err)
+			}
+			n = length
+			t.kind, t.text = tokenString, value
+		default:
+			n = 1
+			t.kind, t.text = tokenSymbol, src[:1]
+		}
+		tokens = append(tokens, t)
+		advance(n)
+		lastLine = line
+	}
+	return append(tokens, token{kind: tokenEOF, line: line, col: col}), nil
+}
+
+// protoNumber gives the decimal form of decimal, octal and hexadecimal integers, floats are kept as is.
+// Go literals which aren't protobuf ones are rejected : _ separators, 0b and 0o prefixes, hexadecimal floats.
+func protoNumber(text string) (string, bool) {
+	lower := strings.ToLower(text)
+	if strings.Contains(text, "_") || strings.HasPrefix(lower, "0b") || strings.HasPrefix(lower, "0o") {
+		return "", false
+	}
+	if i, err := strconv.ParseUint(text, 0, 64); err == nil {
+		return strconv.FormatUint(i, 10), true
+	}
+	if strings.HasPrefix(lower, "0x") {
+		return "", false
+	}
+	_, err := strconv.ParseFloat(text, 64)
+	return text, err == nil
+}
+
+func isLetter(c byte) bool {
+	return c == '_' || 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z'
+}
+
+func isDigit(c byte) bool {
+	return '0' <= c && c <= '9'
+}
+
+// unquote decodes the string literal starting src, with its length
+func unquote(src string) (string, int, error) {
+	quote := src[0]
+	var b strings.Builder
+	for i := 1; i < len(src); i++ {
+		c := src[i]
+		switch {
+		case c == quote:
+			return b.String(), i + 1, nil
+		case c == '\n':
+			return "", 0, fmt.Errorf("unterminated string")
+		case c != '\\':
+			b.WriteByte(c)
+			continue
+		}
+		i++
+		if i >= len(src) {
+			break
+		}
+		switch e := src[i]; e {
+		case 'a':
+			b.WriteByte('\a')
+		case 'b':
+			b.WriteByte('\b')
+		case 'f':
+			b.WriteByte('\f')
+		case 'n':
+			b.WriteByte('\n')
+		case 'r':
+			b.WriteByte('\r')
+		case 't':
+			b.WriteByte('\t')
+		case 'v':
+			b.WriteByte('\v')
+		case 'x', 'X':
+			n := 0
+			for n < 2 && i+1+n < len(src) && strings.IndexByte("0123456789abcdefABCDEF", src[i+1+n]) >= 0 {
+				n++
+			}
+			v, err := strconv.ParseUint(src[i+1:i+1+n], 16, 8)
+			if err != nil {
+				return "", 0, fmt.Errorf("bad escape \\x")
+			}
+			b.WriteByte(byte(v))
+			i += n
+		case 'u', 'U':
+			n := 4
+			if e == 'U' {
+				n = 8
+			}
+			if i+n >= len(src) {
+				return "", 0, fmt.Errorf("bad escape \\%c", e)
+			}
+			v, err := strconv.ParseUint(src[i+1:i+1+n], 16, 32)
+			if err != nil {
+				return "", 0, fmt.Errorf("bad escape \\%c", e)
+			}
+			b.WriteRune(rune(v))
+			i += n
+		default:
+			if '0' <= e && e <= '7' {
+				n := 1
+				for n < 3 && i+n < len(src) && '0' <= src[i+n] && src[i+n] <= '7' {
+					n++
+				}
+				v, _ := strconv.ParseUint(src[i:i+n], 8, 8)
+				b.WriteByte(byte(v))
+				i += n - 1
+				continue
+			}
+			b.WriteByte(e)
+		}
+	}
+	return "", 0, fmt.Errorf("unterminated string")
+}
+
+// parser : recursive descent parser of proto2 and proto3 files, groups aren't supported
+type parser struct {
+	name   string
+	tokens []token
+	pos    int
+	file   *descriptorpb.FileDescriptorProto
+}
+
+func (p *parser) failf(t token, format string, args ...interface{}) {
+	panic(parseError{fmt.Errorf("%s:%d:%d: %s", p.name, t.line+1, t.col+1, fmt.Sprintf(format, args...))})
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *parser) next() token {
+	t := p.tokens[p.pos]
+	if t.kind != tokenEOF {
+		p.pos++
+	}
+	return t
+}
+
+// accept consumes the next token if it is the symbol or keyword text
+func (p *parser) accept(text string) bool {
+	t := p.peek()
+	if (t.kind == tokenSymbol || t.kind == tokenIdent) && t.text == text {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *parser) expect(text string) token {
+	t := p.peek()
+	if !p.accept(text) {
+		p.failf(t, "expected %q, found %q", text, t.text)
+	}
+	return t
+}
+
+func (p *parser) ident() string {
+	t := p.next()
+	if t.kind != tokenIdent {
+		p.failf(t, "expected identifier, found %q", t.text)
+	}
+	return t.text
+}
+
+// fullIdent : dotted identifier, with its leading dot if any
+func (p *parser) fullIdent() string {
+	var b strings.Builder
+	if p.accept(".") {
+		b.WriteString(".")
+	}
+	b.WriteString(p.ident())
+	for p.accept(".") {
+		b.WriteString("." + p.ident())
+	}
+	return b.String()
+}
+
+func (p *parser) stringLiteral() string {
+	t := p.next()
+	if t.kind != tokenString {
+		p.failf(t, "expected string, found %q", t.text)
+	}
+	value := t.text
+	// adjacent strings are concatenated
+	for p.peek().kind == tokenString {
+		value += p.next().text
+	}
+	return value
+}
+
+func (p *parser) integer() int64 {
+	negative := p.accept("-")
+	t := p.next()
+	n, err := strconv.ParseInt(t.text, 10, 64)
+	if t.kind != tokenNumber || err != nil {
+		p.failf(t, "expected integer, found %q", t.text)
+	}
+	if negative {
+		return -n
+	}
+	return n
+}
+
+// comment records the leading comments of the declaration starting at t
+func (p *parser) comment(at []int32, t token) {
+	if t.comments == "" || at == nil {
+		return
+	}
+	p.file.SourceCodeInfo.Location = append(p.file.SourceCodeInfo.Location, &descriptorpb.SourceCodeInfo_Location{
+		Path:            at,
+		Span:            []int32{int32(t.line), int32(t.col), int32(t.col + len(t.text))},
+		LeadingComments: proto.String(t.comments),
+	})
+}
+
+func (p *parser) parseFile() *descriptorpb.FileDescriptorProto {
+	p.file = &descriptorpb.FileDescriptorProto{Name: proto.String(p.name), SourceCodeInfo: &descriptorpb.SourceCodeInfo{}}
+	file := p.file
+	for p.peek().kind != tokenEOF {
+		t := p.peek()
+		switch {
+		case p.accept("syntax"):
+			p.expect("=")
+			file.Syntax = proto.String(p.stringLiteral())
+			if s := file.GetSyntax(); s != "proto2" && s != "proto3" {
+				p.failf(t, "unknown syntax %q", s)
+			}
+			p.expect(";")
+		case p.accept("package"):
+			file.Package = proto.String(p.fullIdent())
+			p.expect(";")
+		case p.accept("import"):
+			index := int32(len(file.Dependency))
+			if p.accept("public") {
+				file.PublicDependency = append(file.PublicDependency, index)
+			} else if p.accept("weak") {
+				file.WeakDependency = append(file.WeakDependency, index)
+			}
+			file.Dependency = append(file.Dependency, p.stringLiteral())
+			p.expect(";")
+		case p.accept("option"):
+			if file.Options == nil {
+				file.Options = &descriptorpb.FileOptions{}
+			}
+			setOption(file.Options, p.option())
+			p.expect(";")
+		case p.accept("message"):
+			file.MessageType = append(file.MessageType, p.message(t, path(nil, fileMessages, len(file.MessageType))))
+		case p.accept("enum"):
+			file.EnumType = append(file.EnumType, p.enum(t, path(nil, fileEnums, len(file.EnumType))))
+		case p.accept("service"):
+			file.Service = append(file.Service, p.service(t, path(nil, fileServices, len(file.Service))))
+		case p.accept("extend"):
+			file.Extension = append(file.Extension, p.extend(nil, fileExtensions, len(file.Extension))...)
+		case p.accept(";"):
+		default:
+			p.failf(t, "unexpected %q", t.text)
+		}
+	}
+	if file.GetSyntax() == "" {
+		file.Syntax = proto.String("proto2")
+	}
+	return file
+}
+
+// option : name = value, after the option keyword or in brackets
+func (p *parser) option() Option {
+	var name strings.Builder
+	for {
+		if p.accept("(") {
+			name.WriteString("(" + p.fullIdent() + ")")
+			p.expect(")")
+		} else {
+			name.WriteString(p.ident())
+		}
+		if !p.accept(".") {
+			break
+		}
+		name.WriteString(".")
+	}
+	p.expect("=")
+	return Option{name.String(), p.constant()}
+}
+
+// constant : option value as written in .proto files, strings quoted, aggregates in braces
+func (p *parser) constant() string {
+	t := p.peek()
+	switch {
+	case t.kind == tokenString:
+		return strconv.Quote(p.stringLiteral())
+	case p.accept("-"):
+		n := p.next()
+		if n.kind != tokenNumber && n.text != "inf" && n.text != "nan" {
+			p.failf(n, "expected number, found %q", n.text)
+		}
+		return "-" + n.text
+	case p.accept("{"):
+		return "{" + p.aggregate() + "}"
+	case t.kind == tokenNumber || t.kind == tokenIdent:
+		return p.next().text
+	}
+	p.failf(t, "expected constant, found %q", t.text)
+	return ""
+}
+
+// aggregate : text format of an aggregate option value, up to its closing brace
+func (p *parser) aggregate() string {
+	var b strings.Builder
+	depth := 0
+	for {
+		t := p.next()
+		switch {
+		case t.kind == tokenEOF:
+			p.failf(t, "unterminated aggregate value")
+		case t.text == "}" && t.kind == tokenSymbol && depth == 0:
+			return b.String()
+		case t.kind == tokenSymbol && (t.text == "{" || t.text == "["):
+			depth++
+		case t.kind == tokenSymbol && (t.text == "}" || t.text == "]"):
+			depth--
+		}
+		text := t.text
+		if t.kind == tokenString {
+			text = strconv.Quote(text)
+		}
+		if b.Len() > 0 && !(t.kind == tokenSymbol && strings.Contains(":,]}", text)) && !strings.HasSuffix(b.String(), "{") && !strings.HasSuffix(b.String(), "[") {
+			b.WriteString(" ")
+		}
+		b.WriteString(text)
+	}
+}
+
+// fieldOptions : options in brackets, json_name and default are set on the field
+func (p *parser) fieldOptions(field *descriptorpb.FieldDescriptorProto) {
+	if !p.accept("[") {
+		return
+	}
+	for {
+		o := p.option()
+		switch {
+		case field == nil:
+			// options of extension ranges are ignored
+		case o.name == "json_name" || o.name == "default":
+			value := o.value
+			if s, err := strconv.Unquote(value); err == nil {
+				value = s
+			}
+			if o.name == "json_name" {
+				field.JsonName = proto.String(value)
+			} else {
+				field.DefaultValue = proto.String(value)
+			}
+		default:
+			if field.Options == nil {
+				field.Options = &descriptorpb.FieldOptions{}
+			}
+			setOption(field.Options, o)
+		}
+		if !p.accept(",") {
+			break
+		}
+	}
+	p.expect("]")
+}
+
+func (p *parser) message(start token, at []int32) *descriptorpb.DescriptorProto {
+	p.comment(at, start)
+	m := &descriptorpb.DescriptorProto{Name: proto.String(p.ident())}
+	p.expect("{")
+	var optionals []*descriptorpb.FieldDescriptorProto
+	for !p.accept("}") {
+		t := p.peek()
+		switch {
+		case p.accept("message"):
+			m.NestedType = append(m.NestedType, p.message(t, path(at, messageNested, len(m.NestedType))))
+		case p.accept("enum"):
+			m.EnumType = append(m.EnumType, p.enum(t, path(at, messageEnums, len(m.EnumType))))
+		case p.accept("option"):
+			if m.Options == nil {
+				m.Options = &descriptorpb.MessageOptions{}
+			}
+			setOption(m.Options, p.option())
+			p.expect(";")
+		case p.accept("oneof"):
+			index := int32(len(m.OneofDecl))
+			p.comment(path(at, messageOneofs, len(m.OneofDecl)), t)
+			oneof := &descriptorpb.OneofDescriptorProto{Name: proto.String(p.ident())}
+			m.OneofDecl = append(m.OneofDecl, oneof)
+			p.expect("{")
+			for !p.accept("}") {
+				if p.accept("option") {
+					if oneof.Options == nil {
+						oneof.Options = &descriptorpb.OneofOptions{}
+					}
+					setOption(oneof.Options, p.option())
+					p.expect(";")
+					continue
+				}
+				field := p.field(m, path(at, messageFields, len(m.Field)))
+				field.OneofIndex = proto.Int32(index)
+				m.Field = append(m.Field, field)
+			}
+		case p.accept("reserved"):
+			p.reserved(m)
+		case p.accept("extensions"):
+			for _, r := range p.ranges(maxFieldNumber) {
+				m.ExtensionRange = append(m.ExtensionRange, &descriptorpb.DescriptorProto_ExtensionRange{Start: proto.Int32(r[0]), End: proto.Int32(r[1] + 1)})
+			}
+			p.fieldOptions(nil)
+			p.expect(";")
+		case p.accept("extend"):
+			m.Extension = append(m.Extension, p.extend(at, messageExtensions, len(m.Extension))...)
+		case p.accept(";"):
+		default:
+			field := p.field(m, path(at, messageFields, len(m.Field)))
+			if field.GetProto3Optional() {
+				optionals = append(optionals, field)
+			}
+			m.Field = append(m.Field, field)
+		}
+	}
+	// proto3 optional fields have a synthetic oneof, declared after the others
+	for _, field := range optionals {
+		field.OneofIndex = proto.Int32(int32(len(m.OneofDecl)))
+		m.OneofDecl = append(m.OneofDecl, &descriptorpb.OneofDescriptorProto{Name: proto.String("_" + field.GetName())})
+	}
+	return m
+}
+
+// field : [label] type name = number [options]; map fields declare their entry message in parent
+func (p *parser) field(parent *descriptorpb.DescriptorProto, at []int32) *descriptorpb.FieldDescriptorProto {
+	start := p.peek()
+	p.comment(at, start)
+	field := &descriptorpb.FieldDescriptorProto{Label: descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum()}
+	switch {
+	case p.accept("repeated"):
+		field.Label = descriptorpb.FieldDescriptorProto_LABEL_REPEATED.Enum()
+	case p.accept("required"):
+		field.Label = descriptorpb.FieldDescriptorProto_LABEL_REQUIRED.Enum()
+	case p.accept("optional"):
+		if p.file.GetSyntax() == "proto3" {
+			field.Proto3Optional = proto.Bool(true)
+		}
+	}
+	if p.peek().text == "map" && p.tokens[p.pos+1].text == "<" {
+		if parent == nil {
+			p.failf(start, "map fields can't extend messages")
+		}
+		p.next()
+		p.next()
+		key := &descriptorpb.FieldDescriptorProto{Name: proto.String("key"), Number: proto.Int32(1), Label: descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum()}
+		setFieldType(key, p.fullIdent())
+		p.expect(",")
+		value := &descriptorpb.FieldDescriptorProto{Name: proto.String("value"), Number: proto.Int32(2), Label: descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum()}
+		setFieldType(value, p.fullIdent())
+		p.expect(">")
+		field.Name = proto.String(p.ident())
+		entry := &descriptorpb.DescriptorProto{
+			Name:    proto.String(camelCase(field.GetName()) + "Entry"),
+			Field:   []*descriptorpb.FieldDescriptorProto{key, value},
+			Options: &descriptorpb.MessageOptions{MapEntry: proto.Bool(true)},
+		}
+		parent.NestedType = append(parent.NestedType, entry)
+		field.Label = descriptorpb.FieldDescriptorProto_LABEL_REPEATED.Enum()
+		field.TypeName = entry.Name
+	} else {
+		if p.peek().text == "group" {
+			p.failf(p.peek(), "groups aren't supported")
+		}
+		setFieldType(field, p.fullIdent())
+		field.Name = proto.String(p.ident())
+	}
+	p.expect("=")
+	number := p.integer()
+	if number <= 0 || number > maxFieldNumber {
+		p.failf(start, "bad field number %d", number)
+	}
+	field.Number = proto.Int32(int32(number))
+	p.fieldOptions(field)
+	p.expect(";")
+	return field
+}
+
+// setFieldType : scalars have a type, messages and enums a name resolved when the file is built
+func setFieldType(field *descriptorpb.FieldDescriptorProto, typename string) {
+	if t, ok := scalarTypes[typename]; ok {
+		field.Type = t.Enum()
+		return
+	}
+	field.TypeName = proto.String(typename)
+}
+
+// reserved : field numbers or names of a message
+func (p *parser) reserved(m *descriptorpb.DescriptorProto) {
+	if p.peek().kind == tokenString {
+		for {
+			m.ReservedName = append(m.ReservedName, p.stringLiteral())
+			if !p.accept(",") {
+				break
+			}
+		}
+	} else {
+		for _, r := range p.ranges(maxFieldNumber) {
+			m.ReservedRange = append(m.ReservedRange, &descriptorpb.DescriptorProto_ReservedRange{Start: proto.Int32(r[0]), End: proto.Int32(r[1] + 1)})
+		}
+	}
+	p.expect(";")
+}
+
+// ranges : comma separated numbers or inclusive ranges n to m, max is the value of "max"
+func (p *parser) ranges(max int64) [][2]int32 {
+	var ranges [][2]int32
+	for {
+		start := p.integer()
+		end := start
+		if p.accept("to") {
+			if p.accept("max") {
+				end = max
+			} else {
+				end = p.integer()
+			}
+		}
+		ranges = append(ranges, [2]int32{int32(start), int32(end)})
+		if !p.accept(",") {
+			break
+		}
+	}
+	return ranges
+}
+
+func (p *parser) enum(start token, at []int32) *descriptorpb.EnumDescriptorProto {
+	p.comment(at, start)
+	e := &descriptorpb.EnumDescriptorProto{Name: proto.String(p.ident())}
+	p.expect("{")
+	for !p.accept("}") {
+		t := p.peek()
+		switch {
+		case p.accept("option"):
+			if e.Options == nil {
+				e.Options = &descriptorpb.EnumOptions{}
+			}
+			setOption(e.Options, p.option())
+			p.expect(";")
+		case p.accept("reserved"):
+			if p.peek().kind == tokenString {
+				for {
+					e.ReservedName = append(e.ReservedName, p.stringLiteral())
+					if !p.accept(",") {
+						break
+					}
+				}
+			} else {
+				for _, r := range p.ranges(math.MaxInt32) {
+					e.ReservedRange = append(e.ReservedRange, &descriptorpb.EnumDescriptorProto_EnumReservedRange{Start: proto.Int32(r[0]), End: proto.Int32(r[1])})
+				}
+			}
+			p.expect(";")
+		case p.accept(";"):
+		default:
+			p.comment(path(at, enumValues, len(e.Value)), t)
+			value := &descriptorpb.EnumValueDescriptorProto{Name: proto.String(p.ident())}
+			p.expect("=")
+			n := p.integer()
+			if n < math.MinInt32 || n > math.MaxInt32 {
+				p.failf(t, "bad enum value %d", n)
+			}
+			value.Number = proto.Int32(int32(n))
+			if p.accept("[") {
+				for {
+					if value.Options == nil {
+						value.Options = &descriptorpb.EnumValueOptions{}
+					}
+					setOption(value.Options, p.option())
+					if !p.accept(",") {
+						break
+					}
+				}
+				p.expect("]")
+			}
+			p.expect(";")
+			e.Value = append(e.Value, value)
+		}
+	}
+	return e
+}
+
+func (p *parser) service(start token, at []int32) *descriptorpb.ServiceDescriptorProto {
+	p.comment(at, start)
+	s := &descriptorpb.ServiceDescriptorProto{Name: proto.String(p.ident())}
+	p.expect("{")
+	for !p.accept("}") {
+		t := p.peek()
+		switch {
+		case p.accept("option"):
+			if s.Options == nil {
+				s.Options = &descriptorpb.ServiceOptions{}
+			}
+			setOption(s.Options, p.option())
+			p.expect(";")
+		case p.accept("rpc"):
+			p.comment(path(at, serviceMethods, len(s.Method)), t)
+			method := &descriptorpb.MethodDescriptorProto{Name: proto.String(p.ident())}
+			p.expect("(")
+			if p.peek().text == "stream" && p.tokens[p.pos+1].text != ")" {
+				p.next()
+				method.ClientStreaming = proto.Bool(true)
+			}
+			method.InputType = proto.String(p.fullIdent())
+			p.expect(")")
+			p.expect("returns")
+			p.expect("(")
+			if p.peek().text == "stream" && p.tokens[p.pos+1].text != ")" {
+				p.next()
+				method.ServerStreaming = proto.Bool(true)
+			}
+			method.OutputType = proto.String(p.fullIdent())
+			p.expect(")")
+			if p.accept("{") {
+				for !p.accept("}") {
+					if p.accept(";") {
+						continue
+					}
+					p.expect("option")
+					if method.Options == nil {
+						method.Options = &descriptorpb.MethodOptions{}
+					}
+					setOption(method.Options, p.option())
+					p.expect(";")
+				}
+			} else {
+				p.expect(";")
+			}
+			s.Method = append(s.Method, method)
+		case p.accept(";"):
+		default:
+			p.failf(t, "unexpected %q", t.text)
+		}
+	}
+	return s
+}
+
+// extend : fields extending a message, declared in the kind list of parent after offset extensions
+func (p *parser) extend(parent []int32, kind int32, offset int) []*descriptorpb.FieldDescriptorProto {
+	extendee := p.fullIdent()
+	p.expect("{")
+	var fields []*descriptorpb.FieldDescriptorProto
+	for !p.accept("}") {
+		if p.accept(";") {
+			continue
+		}
+		field := p.field(nil, path(parent, kind, offset+len(fields)))
+		field.Extendee = proto.String(extendee)
+		fields = append(fields, field)
+	}
+	return fields
+}
@@ -0,0 +1,67 @@
+package protobuf
+
+import (
+	"bytes"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+func TestParseProto(t *testing.T) {
+	matches, _ := filepath.Glob("tests/*.proto")
+	more, _ := filepath.Glob("tests/*/*.proto")
+	matches = append(matches, more...)
+	for _, match := range matches {
+		expected, err := ioutil.ReadFile(match)
+		if err != nil {
+			t.Fatal(err)
+		}
+		file, err := ParseProto(filepath.Base(match), bytes.NewReader(expected))
+		if err != nil {
+			t.Errorf("error parsing %s : %v", match, err)
+			continue
+		}
+		// the printer is canonical, goldens are printed back as is
+		output := &bytes.Buffer{}
+		err = Print(output, file)
+		if err != nil {
+			t.Errorf("error printing %s : %v", match, err)
+		}
+		if output.String() != string(expected) {
+			t.Errorf("Result differ for %s \ngot:\n%s\nexpected:\n%s", match, output.String(), string(expected))
+		}
+		_, err = NewFiles([]*descriptorpb.FileDescriptorProto{file})
+		if err != nil {
+			t.Errorf("%s : invalid descriptor : %v", match, err)
+		}
+	}
+}
+
+func TestParseProtoFiles(t *testing.T) {
+	files, err := ParseProtoFiles([]string{"tests/tree/proto/api.proto"}, []string{"tests/tree/proto"})
+	if err != nil {
+		t.Fatalf("error parsing : %v", err)
+	}
+	var names []string
+	for _, f := range files {
+		names = append(names, f.GetName())
+	}
+	if len(names) != 4 || names[0] != "api.proto" {
+		t.Errorf("bad files %v", names)
+	}
+	_, err = NewFiles(files)
+	if err != nil {
+		t.Errorf("invalid descriptors : %v", err)
+	}
+
+	for _, src := range []string{`syntax = "proto3"; message a { int32 b = 1 }`, `syntax = "proto3"; message a { string b = 1 [json_name = ] ; }`, `message a { "b" }`,
+		`syntax = "proto3"; message a { int32 b = 1_0; }`, `syntax = "proto3"; message a { int32 b = 0b1; }`,
+		`syntax = "proto3"; message a { double b = 1 [default = 0x1p-2]; }`} {
+		_, err := ParseProto("bad.proto", bytes.NewBufferString(src))
+		if err == nil {
+			t.Errorf("parsing %s must fail", src)
+		}
+	}
+}
@@ -0,0 +1,135 @@
+package protobuf
+
+import (
+	"fmt"
+
+	"github.com/Axili39/oastools/oasmodel"
+)
+
+// Presence : how unset singular fields are distinguished from zero values
+type Presence string
+
+// Presence modes
+const (
+	PresenceNone     Presence = ""         // plain proto3 fields, unset is the zero value
+	PresenceOptional Presence = "optional" // optional for non required or nullable scalars
+	PresenceWrappers Presence = "wrappers" // google.protobuf wrapper types for nullable scalars
+)
+
+// ParsePresence gives the Presence of its name, "" or "none" for PresenceNone
+func ParsePresence(name string) (Presence, error) {
+	switch Presence(name) {
+	case PresenceNone, "none":
+		return PresenceNone, nil
+	case PresenceOptional, PresenceWrappers:
+		return Presence(name), nil
+	}
+	return PresenceNone, fmt.Errorf("unknown presence %s, expected none, optional or wrappers", name)
+}
+
+// requiredOption : custom field option recording required properties
+const requiredOption = "(required)"
+
+// wrapper types of scalars, from google/protobuf/wrappers.proto
+var wrapperTypes = map[string]string{
+	"double": "google.protobuf.DoubleValue",
+	"float":  "google.protobuf.FloatValue",
+	"int64":  "google.protobuf.Int64Value",
+	"uint64": "google.protobuf.UInt64Value",
+	"int32":  "google.protobuf.Int32Value",
+	"uint32": "google.protobuf.UInt32Value",
+	"bool":   "google.protobuf.BoolValue",
+	"string": "google.protobuf.StringValue",
+	"bytes":  "google.protobuf.BytesValue",
+}
+
+func isScalar(t ProtoType) bool {
+	switch t.(type) {
+	case *Enum:
+		return true
+	case *TypeName:
+		switch t.Name() {
+		case "double", "float", "int32", "int64", "uint32", "uint64", "sint32", "sint64",
+			"fixed32", "fixed64", "sfixed32", "sfixed64", "bool", "string", "bytes":
+			return true
+		}
+	}
+	return false
+}
+
+// isScalarField returns true for singular scalar and enum fields, which have no presence by default
+func isScalarField(f *MessageMembers, prop *oasmodel.SchemaOrRef) bool {
+	schema := prop.Schema()
+	// enums defined by reference are only named
+	return !f.repeated && (schema != nil && isEnum(schema) || isScalar(f.typedecl))
+}
+
+func isRequired(name string, required []string) bool {
+	for _, r := range required {
+		if r == name {
+			return true
+		}
+	}
+	return false
+}
+
+// setPresence applies the presence mode and the required option to a field
+func setPresence(f *MessageMembers, prop *oasmodel.SchemaOrRef, required bool, genOpts GenerationOptions) {
+	if required && genOpts.RequiredOption {
+		f.options = append(f.options, Option{requiredOption, "true"})
+		importOptions(genOpts)
+	}
+	if !isScalarField(f, prop) {
+		return
+	}
+	nullable := prop.Schema() != nil && prop.Schema().IsNullable()
+	switch genOpts.Presence {
+	case PresenceOptional:
+		f.optional = nullable || !required
+	case PresenceWrappers:
+		if wrapper, ok := wrapperTypes[f.typedecl.Name()]; ok && nullable {
+			f.typedecl = &TypeName{name: wrapper}
+			genOpts.Imports["google/protobuf/wrappers"] = true
+		}
+	}
+}
+
+// Extension : custom options definition
+type Extension struct {
+	extendee string
+	fields   []MessageMembers
+}
+
+// Name :  ProtoType interface realization
+func (t *Extension) Name() string {
+	return t.extendee
+}
+
+// importOptions records the import of the file declaring custom options, in trees
+func importOptions(genOpts GenerationOptions) {
+	if genOpts.optionsFile != "" {
+		genOpts.Imports[genOpts.optionsFile] = true
+	}
+}
+
+// createOptions declares the custom options enabled, numbers are in the range for internal use
+func createOptions(genOpts GenerationOptions) []ProtoType {
+	var extensions []ProtoType
+	if genOpts.RequiredOption {
+		extensions = append(extensions, &Extension{"google.protobuf.FieldOptions", []MessageMembers{
+			{&TypeName{name: "bool"}, "required", 50000, false, "property required by the OpenAPI schema", false, nil, nil},
+		}})
+	}
+	if genOpts.EnumValueOption {
+		extensions = append(extensions, &Extension{"google.protobuf.EnumValueOptions", []MessageMembers{
+			{&TypeName{name: "string"}, "json_value", 50000, false, "enum value in the OpenAPI schema", false, nil, nil},
+		}})
+	}
+	if len(extensions) > 0 && genOpts.optionsFile != "" {
+		return nil
+	}
+	if len(extensions) > 0 {
+		genOpts.Imports["google/protobuf/descriptor"] = true
+	}
+	return extensions
+}
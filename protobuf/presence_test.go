@@ -0,0 +1,86 @@
+package protobuf
+
+import (
+	"bytes"
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	"github.com/Axili39/oastools/oasmodel"
+)
+
+func TestPresence(t *testing.T) {
+	for _, test := range []struct {
+		result  string
+		genOpts GenerationOptions
+	}{
+		{"tests/presence/presence-optional.proto", GenerationOptions{Imports: map[string]bool{}, Presence: PresenceOptional, RequiredOption: true}},
+		{"tests/presence/presence-wrappers.proto", GenerationOptions{Imports: map[string]bool{}, Presence: PresenceWrappers}},
+	} {
+		oa := oasmodel.OpenAPI{}
+		err := oa.Load("tests/presence/presence.yaml")
+		if err != nil {
+			t.Fatalf("error loading spec : %v", err)
+		}
+		output := &bytes.Buffer{}
+		err = Components2Proto(&oa, output, "", test.genOpts, nil)
+		if err != nil {
+			t.Fatalf("error generating %s : %v", test.result, err)
+		}
+		expected, err := ioutil.ReadFile(test.result)
+		if err != nil {
+			t.Fatalf("error loading result file : %v", err)
+		}
+		if string(expected) != output.String() {
+			t.Errorf("Result differ for %s\ngot:\n%s\nexpected:\n%s", test.result, output.String(), string(expected))
+		}
+	}
+
+	if _, err := ParsePresence("maybe"); err == nil {
+		t.Errorf("expected error for unknown presence")
+	}
+}
+
+func TestRequestPresence(t *testing.T) {
+	oa := oasmodel.OpenAPI{}
+	_, err := oa.UnMarshal([]byte(`openapi: 3.0.0
+info:
+  title: presence
+  version: 1.0.0
+paths:
+  /pets/{id}:
+    put:
+      operationId: updatePet
+      parameters:
+        - {name: id, in: path, schema: {type: integer}}
+        - {name: force, in: query, required: true, schema: {type: boolean}}
+        - {name: trace, in: query, schema: {type: string}}
+      requestBody:
+        required: true
+        content:
+          application/json:
+            schema: {type: string}
+      responses:
+        "204":
+          description: updated
+`))
+	if err != nil {
+		t.Fatalf("error loading spec : %v", err)
+	}
+	output := &bytes.Buffer{}
+	err = Components2Proto(&oa, output, "", GenerationOptions{Imports: map[string]bool{}, Services: true, Presence: PresenceOptional, RequiredOption: true}, nil)
+	if err != nil {
+		t.Fatalf("error generating services : %v", err)
+	}
+	// required parameters and body are neither optional nor unset
+	for _, expected := range []string{
+		"int32 id = 1 [(required) = true];",
+		"bool force = 2 [(required) = true];",
+		"optional string trace = 3;",
+		"string body = 4 [(required) = true];",
+	} {
+		if !strings.Contains(output.String(), expected) {
+			t.Errorf("expected %s in :\n%s", expected, output.String())
+		}
+	}
+}
@@ -0,0 +1,339 @@
+package protobuf
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+// Print writes a file descriptor as a .proto file in canonical format : syntax, package, imports, options,
+// then extensions, enums, messages and services separated by blank lines. Declarations are indented with tabs,
+// and preceded by the leading comments of the source code info.
+func Print(w io.Writer, file *descriptorpb.FileDescriptorProto) error {
+	p := newPrinter(file, false)
+	p.file(file)
+	_, err := w.Write(p.buf.Bytes())
+	return err
+}
+
+// setSpans records the position of commented declarations in the printed file, as required by protodesc
+func setSpans(file *descriptorpb.FileDescriptorProto) {
+	p := newPrinter(file, true)
+	p.file(file)
+}
+
+type printer struct {
+	buf       bytes.Buffer
+	locations map[string]*descriptorpb.SourceCodeInfo_Location
+	spans     bool // update spans of locations
+	line      int  // current line, from 0
+	last      int  // length of the last line printed
+}
+
+func newPrinter(file *descriptorpb.FileDescriptorProto, spans bool) *printer {
+	p := printer{locations: make(map[string]*descriptorpb.SourceCodeInfo_Location), spans: spans}
+	for _, loc := range file.GetSourceCodeInfo().GetLocation() {
+		p.locations[fmt.Sprint(loc.Path)] = loc
+	}
+	return &p
+}
+
+// println writes an indented line
+func (p *printer) println(indent int, format string, args ...interface{}) {
+	line := strings.Repeat("\t", indent) + fmt.Sprintf(format, args...)
+	p.buf.WriteString(line + "\n")
+	p.line++
+	p.last = len(line)
+}
+
+// declaration prints the leading comments of the declaration at path at, then the declaration
+func (p *printer) declaration(at []int32, indent int, declare func()) {
+	loc := p.locations[fmt.Sprint(at)]
+	if loc != nil {
+		for _, line := range strings.Split(strings.TrimSuffix(loc.GetLeadingComments(), "\n"), "\n") {
+			p.println(indent, "//%s", line)
+		}
+	}
+	start := p.line
+	declare()
+	if loc == nil || !p.spans {
+		return
+	}
+	if end := p.line - 1; end != start {
+		loc.Span = []int32{int32(start), int32(indent), int32(end), int32(p.last)}
+	} else {
+		loc.Span = []int32{int32(start), int32(indent), int32(p.last)}
+	}
+}
+
+func (p *printer) file(file *descriptorpb.FileDescriptorProto) {
+	syntax := file.GetSyntax()
+	if syntax == "" {
+		syntax = "proto2"
+	}
+	p.println(0, "syntax = %q;", syntax)
+	if file.GetPackage() != "" {
+		p.println(0, "")
+		p.println(0, "package %s;", file.GetPackage())
+	}
+	if len(file.Dependency) > 0 {
+		p.println(0, "")
+		for _, dependency := range file.Dependency {
+			p.println(0, "import %q;", dependency)
+		}
+	}
+	if options := optionList(file.Options); len(options) > 0 {
+		p.println(0, "")
+		for _, o := range options {
+			p.println(0, "option %s;", o)
+		}
+	}
+
+	scope := ""
+	if file.GetPackage() != "" {
+		scope = "." + file.GetPackage()
+	}
+	// extensions of the same message are declared in a single block
+	for i := 0; i < len(file.Extension); {
+		extendee := file.Extension[i].GetExtendee()
+		p.println(0, "")
+		p.println(0, "extend %s {", relativeName(extendee, scope))
+		for ; i < len(file.Extension) && file.Extension[i].GetExtendee() == extendee; i++ {
+			p.field(nil, file.Extension[i], path(nil, fileExtensions, i), 1, scope)
+		}
+		p.println(0, "}")
+	}
+	for i, e := range file.EnumType {
+		p.println(0, "")
+		p.enum(e, path(nil, fileEnums, i), 0)
+	}
+	for i, m := range file.MessageType {
+		p.println(0, "")
+		p.message(m, path(nil, fileMessages, i), 0, scope)
+	}
+	for i, s := range file.Service {
+		p.println(0, "")
+		p.service(s, path(nil, fileServices, i), 0, scope)
+	}
+}
+
+func (p *printer) message(m *descriptorpb.DescriptorProto, at []int32, indent int, scope string) {
+	scope += "." + m.GetName()
+	p.declaration(at, indent, func() {
+		p.println(indent, "message %s {", m.GetName())
+		for i, e := range m.EnumType {
+			p.enum(e, path(at, messageEnums, i), indent+1)
+		}
+		for i, n := range m.NestedType {
+			if !n.GetOptions().GetMapEntry() {
+				p.message(n, path(at, messageNested, i), indent+1, scope)
+			}
+		}
+		printed := make(map[int32]bool)
+		for i, f := range m.Field {
+			if f.OneofIndex == nil || f.GetProto3Optional() {
+				p.field(m, f, path(at, messageFields, i), indent+1, scope)
+				continue
+			}
+			oneof := f.GetOneofIndex()
+			if printed[oneof] {
+				continue
+			}
+			printed[oneof] = true
+			p.declaration(path(at, messageOneofs, int(oneof)), indent+1, func() {
+				p.println(indent+1, "oneof %s {", m.OneofDecl[oneof].GetName())
+				for j, member := range m.Field {
+					if member.OneofIndex != nil && member.GetOneofIndex() == oneof {
+						p.field(m, member, path(at, messageFields, j), indent+2, scope)
+					}
+				}
+				p.println(indent+1, "}")
+			})
+		}
+		if len(m.ReservedRange) > 0 {
+			ranges := make([]string, len(m.ReservedRange))
+			for i, r := range m.ReservedRange {
+				ranges[i] = strconv.Itoa(int(r.GetStart()))
+				if r.GetEnd() > r.GetStart()+1 {
+					ranges[i] += " to " + strconv.Itoa(int(r.GetEnd()-1))
+				}
+			}
+			p.println(indent+1, "reserved %s;", strings.Join(ranges, ", "))
+		}
+		if len(m.ReservedName) > 0 {
+			names := make([]string, len(m.ReservedName))
+			for i, name := range m.ReservedName {
+				names[i] = strconv.Quote(name)
+			}
+			p.println(indent+1, "reserved %s;", strings.Join(names, ", "))
+		}
+		p.println(indent, "}")
+	})
+}
+
+// field : type names are relative to scope, the full name of the message declaring the field
+func (p *printer) field(parent *descriptorpb.DescriptorProto, f *descriptorpb.FieldDescriptorProto, at []int32, indent int, scope string) {
+	p.declaration(at, indent, func() {
+		label := ""
+		typename := fieldType(f, scope)
+		if entry := mapEntryOf(parent, f); entry != nil {
+			typename = "map<" + fieldType(entry.Field[0], scope) + ", " + fieldType(entry.Field[1], scope) + ">"
+		} else if f.GetLabel() == descriptorpb.FieldDescriptorProto_LABEL_REPEATED {
+			label = "repeated "
+		} else if f.GetProto3Optional() {
+			label = "optional "
+		}
+		var options []string
+		if f.JsonName != nil {
+			options = append(options, "json_name = "+strconv.Quote(f.GetJsonName()))
+		}
+		options = append(options, optionList(f.Options)...)
+		p.println(indent, "%s%s %s = %d%s;", label, typename, f.GetName(), f.GetNumber(), optionsString(options))
+	})
+}
+
+// fieldType : type name relative to scope, or scalar name
+func fieldType(f *descriptorpb.FieldDescriptorProto, scope string) string {
+	if f.TypeName != nil {
+		return relativeName(f.GetTypeName(), scope)
+	}
+	return strings.ToLower(strings.TrimPrefix(f.GetType().String(), "TYPE_"))
+}
+
+// relativeName shortens a fully-qualified name for declarations in scope : .foo.bar.baz gives baz in .foo.bar
+func relativeName(name string, scope string) string {
+	if !strings.HasPrefix(name, ".") {
+		return name
+	}
+	for s := scope; s != ""; s = s[:strings.LastIndex(s, ".")] {
+		if strings.HasPrefix(name, s+".") {
+			return name[len(s)+1:]
+		}
+	}
+	return name[1:]
+}
+
+// mapEntryOf gives the entry message of a map field, nil for other fields
+func mapEntryOf(parent *descriptorpb.DescriptorProto, f *descriptorpb.FieldDescriptorProto) *descriptorpb.DescriptorProto {
+	if parent == nil || f.TypeName == nil || f.GetLabel() != descriptorpb.FieldDescriptorProto_LABEL_REPEATED {
+		return nil
+	}
+	name := f.GetTypeName()
+	name = name[strings.LastIndex(name, ".")+1:]
+	for _, n := range parent.NestedType {
+		if n.GetName() == name && n.GetOptions().GetMapEntry() && len(n.Field) == 2 {
+			return n
+		}
+	}
+	return nil
+}
+
+func (p *printer) enum(e *descriptorpb.EnumDescriptorProto, at []int32, indent int) {
+	p.declaration(at, indent, func() {
+		p.println(indent, "enum %s {", e.GetName())
+		for i, v := range e.Value {
+			p.declaration(path(at, enumValues, i), indent+1, func() {
+				p.println(indent+1, "%s = %d%s;", v.GetName(), v.GetNumber(), optionsString(optionList(v.Options)))
+			})
+		}
+		p.println(indent, "}")
+	})
+}
+
+func (p *printer) service(s *descriptorpb.ServiceDescriptorProto, at []int32, indent int, scope string) {
+	p.declaration(at, indent, func() {
+		p.println(indent, "service %s {", s.GetName())
+		for i, m := range s.Method {
+			p.declaration(path(at, serviceMethods, i), indent+1, func() {
+				rpc := fmt.Sprintf("rpc %s(%s) returns (%s)", m.GetName(), relativeName(m.GetInputType(), scope), relativeName(m.GetOutputType(), scope))
+				options := optionList(m.Options)
+				if len(options) == 0 {
+					p.println(indent+1, "%s;", rpc)
+					return
+				}
+				p.println(indent+1, "%s {", rpc)
+				for _, o := range options {
+					p.println(indent+2, "option %s;", o)
+				}
+				p.println(indent+1, "}")
+			})
+		}
+		p.println(indent, "}")
+	})
+}
+
+// optionsString gives the options of a field or enum value between brackets, "" without options
+func optionsString(options []string) string {
+	if len(options) == 0 {
+		return ""
+	}
+	return " [" + strings.Join(options, ", ") + "]"
+}
+
+// optionList gives the options set, as name = value, in field number order then uninterpreted options
+func optionList(options proto.Message) []string {
+	m := options.ProtoReflect()
+	if !m.IsValid() {
+		return nil
+	}
+	var list []string
+	fields := m.Descriptor().Fields()
+	for i := 0; i < fields.Len(); i++ {
+		fd := fields.Get(i)
+		if fd.Name() == "uninterpreted_option" || !m.Has(fd) || fd.Cardinality() == protoreflect.Repeated {
+			continue
+		}
+		list = append(list, string(fd.Name())+" = "+formatValue(fd, m.Get(fd)))
+	}
+	uninterpreted := m.Get(fields.ByName("uninterpreted_option")).List()
+	for i := 0; i < uninterpreted.Len(); i++ {
+		list = append(list, uninterpretedString(uninterpreted.Get(i).Message().Interface().(*descriptorpb.UninterpretedOption)))
+	}
+	return list
+}
+
+func formatValue(fd protoreflect.FieldDescriptor, v protoreflect.Value) string {
+	switch fd.Kind() {
+	case protoreflect.StringKind:
+		return strconv.Quote(v.String())
+	case protoreflect.EnumKind:
+		if ev := fd.Enum().Values().ByNumber(v.Enum()); ev != nil {
+			return string(ev.Name())
+		}
+		return strconv.Itoa(int(v.Enum()))
+	default:
+		return fmt.Sprint(v.Interface())
+	}
+}
+
+func uninterpretedString(u *descriptorpb.UninterpretedOption) string {
+	parts := make([]string, len(u.Name))
+	for i, part := range u.Name {
+		parts[i] = part.GetNamePart()
+		if part.GetIsExtension() {
+			parts[i] = "(" + parts[i] + ")"
+		}
+	}
+	var value string
+	switch {
+	case u.IdentifierValue != nil:
+		value = u.GetIdentifierValue()
+	case u.PositiveIntValue != nil:
+		value = strconv.FormatUint(u.GetPositiveIntValue(), 10)
+	case u.NegativeIntValue != nil:
+		value = strconv.FormatInt(u.GetNegativeIntValue(), 10)
+	case u.DoubleValue != nil:
+		value = strconv.FormatFloat(u.GetDoubleValue(), 'g', -1, 64)
+	case u.StringValue != nil:
+		value = strconv.Quote(string(u.StringValue))
+	default:
+		value = "{" + u.GetAggregateValue() + "}"
+	}
+	return strings.Join(parts, ".") + " = " + value
+}
@@ -1,6 +1,7 @@
 package protobuf
 
 import (
+	"errors"
 	"fmt"
 	"io"
 	"log"
@@ -8,11 +9,11 @@ import (
 	"strings"
 
 	"github.com/Axili39/oastools/oasmodel"
+	"google.golang.org/protobuf/types/descriptorpb"
 )
 
-// ProtoType Field Type protocol buffer interface
+// ProtoType Field Type protocol buffer interface, messages, enums, extensions and services are declared in file descriptors
 type ProtoType interface {
-	Declare(w io.Writer, indent string)
 	Name() string
 }
 
@@ -29,10 +30,42 @@ type GenerationOptions struct {
 	AddMsgPrefix  bool
 	PackageNames  map[string]string
 	Imports       map[string]bool
+	Services      bool   // generate a service per tag, with an rpc per operation
+	ServiceName   string // generate a single service with this name
+	// HTTPAnnotations adds google.api.http options to rpcs
+	HTTPAnnotations bool
+	// TypeMapping gives proto types of formats, DefaultTypeMapping() if nil
+	TypeMapping TypeMapping
+	// Lock keeps field numbers across generations, updated by the generation
+	Lock *Lock
+	// Presence of singular fields : PresenceNone, PresenceOptional or PresenceWrappers
+	Presence Presence
+	// RequiredOption marks required properties with the (required) field option
+	RequiredOption bool
+	// ValidateRules adds protoc-gen-validate or protovalidate rules from schema constraints
+	ValidateRules ValidateRules
+	// EnumUnspecified adds <ENUM>_UNSPECIFIED = 0 to string enums
+	EnumUnspecified bool
+	// EnumValueOption records spec values of renamed enum constants with the (json_value) option
+	EnumValueOption bool
+	// Files gives the .proto of documents by absolute path, external references are imported from them
+	Files map[string]ProtoFile
+
+	packageName string // package of the generated file in trees, qualifies lock keys
+	optionsFile string // custom options are imported from this file instead of being declared, in trees
+}
+
+// fatalError : the generation must fail, other errors only skip the component
+type fatalError struct {
+	msg string
+}
+
+func (e *fatalError) Error() string {
+	return e.msg
 }
 
-// Declare : ProtoType interface realization
-func (t *Map) Declare(w io.Writer, indent string) {
+func fatalErrorf(format string, args ...interface{}) error {
+	return &fatalError{fmt.Sprintf(format, args...)}
 }
 
 // Name :  ProtoType interface realization
@@ -45,7 +78,7 @@ func normalizeName(name string) string {
 }
 
 func createAdditionalProperties(name string, schema *oasmodel.Schema, parent *Message, genOpts GenerationOptions) (ProtoType, error) {
-	if schema.Type != "object" {
+	if schema.TypeName() != "object" {
 		return nil, fmt.Errorf("Schema %s with Additional Properties must be an object", name)
 	}
 
@@ -66,7 +99,13 @@ func CreateType(name string, schemaOrRef *oasmodel.SchemaOrRef, parent *Message,
 	schema := schemaOrRef.Schema()
 	// In case of Ref, we need to get the corresponding type name
 	if schemaOrRef.Ref != nil {
-		if schemaOrRef.Ref.External != "" {
+		// types of documents in the tree are imported, their scalars are inlined
+		file, inTree := genOpts.Files[schemaOrRef.Ref.Document]
+		if inTree && schemaOrRef.Ref.External != "" && schema != nil && isNamedType(schema) {
+			genOpts.Imports[strings.TrimSuffix(file.Path, ".proto")] = true
+			return createTypename(file.Package + "." + schemaOrRef.Ref.RefName)
+		}
+		if schemaOrRef.Ref.External != "" && !inTree {
 			packageName := schemaOrRef.Ref.External
 
 			// rename package
@@ -78,45 +117,60 @@ func CreateType(name string, schemaOrRef *oasmodel.SchemaOrRef, parent *Message,
 			// store package in imports
 			genOpts.Imports[schemaOrRef.Ref.External] = true
 
-			return createTypename(packageName+"."+schemaOrRef.Ref.RefName, "")
+			return createTypename(packageName + "." + schemaOrRef.Ref.RefName)
 		}
 		if schema == nil {
 			return nil, fmt.Errorf("bad ref")
 		}
-		if schema.OneOf != nil || schema.AllOf != nil || schema.Type == "object" && schema.AdditionalProperties == nil || (schema.Type == "string" && len(schema.Enum) > 0) {
+		if isFreeForm(schema) {
+			return createScalar(&oasmodel.Schema{Type: oasmodel.SchemaType{"object"}}, genOpts)
+		}
+		if isNamedType(schema) && schemaOrRef.Ref.External == "" {
 			// in case of Ref, reference type name only for messages :
-			return createTypename(schemaOrRef.Ref.RefName, "")
+			return &TypeName{name: schemaOrRef.Ref.RefName, local: true}, nil
 		}
 	}
 	// case Oneof
 	if schema.OneOf != nil {
-		return createOneOf(name, schema.OneOf, parent, genOpts)
+		return createOneOf(name, schema, parent, genOpts)
+	}
+	// case AnyOf
+	if schema.AnyOf != nil {
+		return createAnyOf(name, schema, parent, genOpts)
 	}
 	// case AllOf
 	if schema.AllOf != nil {
 		return createAllOf(name, schema.AllOf, parent, genOpts)
 	}
 	// Case AdditionalProperties
-	if schema.AdditionalProperties != nil {
+	if schema.AdditionalProperties != nil && !(parent != nil && isFreeForm(schema)) {
 		return createAdditionalProperties(name, schema, parent, genOpts)
 	}
-	// case Object
-	if schema.Type == "object" {
+	// case Object, free-form objects are declared as messages only at first level
+	if schema.TypeName() == "object" && parent != nil && isFreeForm(schema) {
+		return createScalar(schema, genOpts)
+	}
+	if schema.TypeName() == "object" {
 		return createMessage(name, schema, parent, genOpts)
 	}
 	// case Array
-	if schema.Type == "array" {
+	if schema.TypeName() == "array" {
 		if parent == nil {
 			return createMessageArray(name, schema, genOpts)
 		}
 		return CreateType(name, schema.Items, parent, genOpts)
 	}
 	// Enums
-	if schema.Type == "string" && len(schema.Enum) > 0 {
+	if isEnum(schema) {
 		return createEnum(name, schema, parent, genOpts)
 	}
 
-	return createTypename(schema.Type, schema.Format)
+	return createScalar(schema, genOpts)
+}
+
+// isNamedType returns true for schemas declared as messages or enums
+func isNamedType(schema *oasmodel.Schema) bool {
+	return schema.OneOf != nil || schema.AnyOf != nil || schema.AllOf != nil || schema.TypeName() == "object" && schema.AdditionalProperties == nil || isEnum(schema)
 }
 
 func keysorder(m map[string]*oasmodel.SchemaOrRef) []string {
@@ -130,20 +184,32 @@ func keysorder(m map[string]*oasmodel.SchemaOrRef) []string {
 	return keys
 }
 
-// Components2Proto : generate proto file from Parsed OpenAPI definition
-func Components2Proto(oa *oasmodel.OpenAPI, f io.Writer, packageName string, genOpts GenerationOptions, filternodes []string, options ...string) error {
+// CreateTypes : convert OpenAPI components, and operations if services are enabled, to messages, enums and services
+func CreateTypes(oa *oasmodel.OpenAPI, genOpts GenerationOptions, filternodes []string) ([]ProtoType, []ProtoType, error) {
 	var items []string
 	if filternodes == nil {
-		oa.ResolveRefs()
+		err := oa.ResolveRefs()
+		if err != nil {
+			return nil, nil, err
+		}
 		items = keysorder(oa.Components.Schemas)
 	} else {
-		items = keysorder(oa.ResolveRefsWithFilter(filternodes))
+		filtered, err := oa.ResolveRefsWithFilter(filternodes)
+		if err != nil {
+			return nil, nil, err
+		}
+		items = keysorder(filtered)
 	}
 	nodeList := make([]ProtoType, 0, 10)
+	nodeList = append(nodeList, createOptions(genOpts)...)
 	// create first level Nodes
 	for _, k := range items {
 		v := oa.Components.Schemas[k]
 		node, err := CreateType(k, v, nil, genOpts)
+		var fatal *fatalError
+		if errors.As(err, &fatal) {
+			return nil, nil, fmt.Errorf("%s : %v", k, err)
+		}
 		if err != nil {
 			log.Println("error : ", err)
 			continue
@@ -151,18 +217,51 @@ func Components2Proto(oa *oasmodel.OpenAPI, f io.Writer, packageName string, gen
 		nodeList = append(nodeList, node)
 	}
 
-	fmt.Fprintf(f, "syntax = \"proto3\";\n")
-	if packageName != "" {
-		fmt.Fprintln(f, "package ", packageName, ";")
+	// services and their Request / Response messages
+	var services []ProtoType
+	if genOpts.Services || genOpts.ServiceName != "" {
+		// paths may reference components out of the filter
+		if filternodes != nil {
+			err := oa.ResolveRefs()
+			if err != nil {
+				return nil, nil, err
+			}
+		}
+		defined := make(map[string]bool)
+		for _, k := range items {
+			defined[k] = true
+		}
+		messages, list, err := createServices(oa, genOpts, defined)
+		if err != nil {
+			return nil, nil, err
+		}
+		if genOpts.HTTPAnnotations {
+			genOpts.Imports["google/api/annotations"] = true
+		}
+		nodeList = append(nodeList, messages...)
+		services = list
 	}
-	for _, opt := range options {
-		fmt.Fprintln(f, "option ", opt, ";")
+	if err := checkEnumScope(nodeList); err != nil {
+		return nil, nil, err
 	}
-	for packageFile, _ := range genOpts.Imports {
-		fmt.Fprintf(f, "import \"%s.proto\";\n", packageFile)
+	return nodeList, services, nil
+}
+
+// Components2Proto : generate proto file from Parsed OpenAPI definition
+func Components2Proto(oa *oasmodel.OpenAPI, f io.Writer, packageName string, genOpts GenerationOptions, filternodes []string, options ...string) error {
+	file, err := CreateFile(oa, "", packageName, genOpts, filternodes, options...)
+	if err != nil {
+		return err
 	}
-	for n := range nodeList {
-		nodeList[n].Declare(f, "")
+	return Print(f, file)
+}
+
+// CreateFile : convert OpenAPI components, and operations if services are enabled, to the descriptor of file name.
+// The descriptor may be printed with Print, or built with protodesc once its imports are available.
+func CreateFile(oa *oasmodel.OpenAPI, name string, packageName string, genOpts GenerationOptions, filternodes []string, options ...string) (*descriptorpb.FileDescriptorProto, error) {
+	nodeList, services, err := CreateTypes(oa, genOpts, filternodes)
+	if err != nil {
+		return nil, err
 	}
-	return nil
+	return newFile(name, packageName, genOpts.Imports, options, nodeList, services)
 }
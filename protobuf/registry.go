@@ -0,0 +1,244 @@
+package protobuf
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/Axili39/oastools/oasmodel"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/dynamicpb"
+
+	// well-known types used by DefaultTypeMapping and presence wrappers
+	_ "google.golang.org/protobuf/types/known/durationpb"
+	_ "google.golang.org/protobuf/types/known/structpb"
+	_ "google.golang.org/protobuf/types/known/timestamppb"
+	_ "google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+// resolver finds descriptors in files, then in the well-known types linked in the binary
+type resolver struct {
+	files *protoregistry.Files
+}
+
+func (r resolver) FindFileByPath(path string) (protoreflect.FileDescriptor, error) {
+	fd, err := r.files.FindFileByPath(path)
+	if errors.Is(err, protoregistry.NotFound) {
+		return protoregistry.GlobalFiles.FindFileByPath(path)
+	}
+	return fd, err
+}
+
+func (r resolver) FindDescriptorByName(name protoreflect.FullName) (protoreflect.Descriptor, error) {
+	d, err := r.files.FindDescriptorByName(name)
+	if errors.Is(err, protoregistry.NotFound) {
+		return protoregistry.GlobalFiles.FindDescriptorByName(name)
+	}
+	return d, err
+}
+
+// NewFiles builds descriptors in-process, without protoc, and registers them in a new registry.
+// Imports are resolved from files then from the well-known types, other imports (eg: validation rules, annotations) are placeholders.
+// Relative type names, as parsed by ParseProto, are qualified in files.
+func NewFiles(files []*descriptorpb.FileDescriptorProto) (*protoregistry.Files, error) {
+	qualifyNames(files)
+	registry := new(protoregistry.Files)
+	byName := make(map[string]*descriptorpb.FileDescriptorProto)
+	for _, f := range files {
+		byName[f.GetName()] = f
+	}
+	visiting := make(map[string]bool)
+	var register func(f *descriptorpb.FileDescriptorProto) error
+	register = func(f *descriptorpb.FileDescriptorProto) error {
+		if _, err := registry.FindFileByPath(f.GetName()); err == nil {
+			return nil
+		}
+		if visiting[f.GetName()] {
+			return fmt.Errorf("import cycle : %s", f.GetName())
+		}
+		visiting[f.GetName()] = true
+		// dependencies are registered first
+		for _, dep := range f.GetDependency() {
+			if d, ok := byName[dep]; ok {
+				if err := register(d); err != nil {
+					return err
+				}
+			}
+		}
+		fd, err := protodesc.FileOptions{AllowUnresolvable: true}.New(f, resolver{registry})
+		if err != nil {
+			return fmt.Errorf("%s : %v", f.GetName(), err)
+		}
+		return registry.RegisterFile(fd)
+	}
+	for _, f := range files {
+		if err := register(f); err != nil {
+			return nil, err
+		}
+	}
+	return registry, nil
+}
+
+// qualifyNames replaces relative type names by fully-qualified names, looked up from the innermost scope as protoc does.
+// Symbols which aren't types are skipped, so that a field may be named after its type.
+func qualifyNames(files []*descriptorpb.FileDescriptorProto) {
+	types := make(map[string]descriptorpb.FieldDescriptorProto_Type)
+	var collect func(scope string, messages []*descriptorpb.DescriptorProto, enums []*descriptorpb.EnumDescriptorProto)
+	collect = func(scope string, messages []*descriptorpb.DescriptorProto, enums []*descriptorpb.EnumDescriptorProto) {
+		for _, e := range enums {
+			types[scope+"."+e.GetName()] = descriptorpb.FieldDescriptorProto_TYPE_ENUM
+		}
+		for _, m := range messages {
+			types[scope+"."+m.GetName()] = descriptorpb.FieldDescriptorProto_TYPE_MESSAGE
+			collect(scope+"."+m.GetName(), m.NestedType, m.EnumType)
+		}
+	}
+	for _, f := range files {
+		collect(packageScope(f), f.MessageType, f.EnumType)
+	}
+	lookup := func(scope string, name string) (string, descriptorpb.FieldDescriptorProto_Type) {
+		if strings.HasPrefix(name, ".") {
+			return name, types[name]
+		}
+		for s := scope; ; s = s[:strings.LastIndex(s, ".")] {
+			candidate := s + "." + name
+			if t, ok := types[candidate]; ok {
+				return candidate, t
+			}
+			if d, err := protoregistry.GlobalFiles.FindDescriptorByName(protoreflect.FullName(candidate[1:])); err == nil {
+				switch d.(type) {
+				case protoreflect.MessageDescriptor:
+					return candidate, descriptorpb.FieldDescriptorProto_TYPE_MESSAGE
+				case protoreflect.EnumDescriptor:
+					return candidate, descriptorpb.FieldDescriptorProto_TYPE_ENUM
+				}
+			}
+			if s == "" {
+				return name, 0
+			}
+		}
+	}
+	resolve := func(scope string, field *descriptorpb.FieldDescriptorProto) {
+		if field.TypeName != nil {
+			name, t := lookup(scope, field.GetTypeName())
+			field.TypeName = proto.String(name)
+			if field.Type == nil && t != 0 {
+				field.Type = t.Enum()
+			}
+		}
+		if field.Extendee != nil {
+			name, _ := lookup(scope, field.GetExtendee())
+			field.Extendee = proto.String(name)
+		}
+	}
+	var walk func(scope string, m *descriptorpb.DescriptorProto)
+	walk = func(scope string, m *descriptorpb.DescriptorProto) {
+		for _, field := range m.Field {
+			resolve(scope, field)
+		}
+		for _, field := range m.Extension {
+			resolve(scope, field)
+		}
+		for _, nested := range m.NestedType {
+			walk(scope+"."+nested.GetName(), nested)
+		}
+	}
+	for _, f := range files {
+		scope := packageScope(f)
+		for _, m := range f.MessageType {
+			walk(scope+"."+m.GetName(), m)
+		}
+		for _, field := range f.Extension {
+			resolve(scope, field)
+		}
+		for _, s := range f.Service {
+			for _, method := range s.Method {
+				input, _ := lookup(scope, method.GetInputType())
+				output, _ := lookup(scope, method.GetOutputType())
+				method.InputType, method.OutputType = proto.String(input), proto.String(output)
+			}
+		}
+	}
+}
+
+// packageScope : package of a file with a leading dot, "" without package
+func packageScope(f *descriptorpb.FileDescriptorProto) string {
+	if f.GetPackage() == "" {
+		return ""
+	}
+	return "." + f.GetPackage()
+}
+
+// MessageType builds in-process the message type of a component of filename, documents it references are followed.
+// Messages are dynamicpb messages, no generated code is needed.
+func MessageType(filename string, component string, genOpts GenerationOptions) (protoreflect.MessageType, error) {
+	files, err := CreateFiles(filename, "", genOpts)
+	if err != nil {
+		return nil, err
+	}
+	return messageType(files, files[0].GetPackage(), component)
+}
+
+// ComponentType builds in-process the message type of a component of a single document, external references aren't followed
+func ComponentType(oa *oasmodel.OpenAPI, component string, genOpts GenerationOptions) (protoreflect.MessageType, error) {
+	if genOpts.Imports == nil {
+		genOpts.Imports = make(map[string]bool)
+	}
+	file, err := CreateFile(oa, "component.proto", "", genOpts, []string{component})
+	if err != nil {
+		return nil, err
+	}
+	return messageType([]*descriptorpb.FileDescriptorProto{file}, "", component)
+}
+
+// messageType builds files and looks up the message of component in package packageName
+func messageType(files []*descriptorpb.FileDescriptorProto, packageName string, component string) (protoreflect.MessageType, error) {
+	registry, err := NewFiles(files)
+	if err != nil {
+		return nil, err
+	}
+	name := protoreflect.FullName(normalizeName(component))
+	if packageName != "" {
+		name = protoreflect.FullName(packageName).Append(protoreflect.Name(normalizeName(component)))
+	}
+	d, err := registry.FindDescriptorByName(name)
+	if err != nil {
+		return nil, fmt.Errorf("component %s : %v", component, err)
+	}
+	md, ok := d.(protoreflect.MessageDescriptor)
+	if !ok {
+		return nil, fmt.Errorf("component %s isn't a message", component)
+	}
+	if err := checkResolved(md, make(map[protoreflect.FullName]bool)); err != nil {
+		return nil, fmt.Errorf("component %s : %v", component, err)
+	}
+	return dynamicpb.NewMessageType(md), nil
+}
+
+// checkResolved : types of imports which aren't available in-process can't be instantiated
+func checkResolved(md protoreflect.MessageDescriptor, seen map[protoreflect.FullName]bool) error {
+	if seen[md.FullName()] {
+		return nil
+	}
+	seen[md.FullName()] = true
+	fields := md.Fields()
+	for i := 0; i < fields.Len(); i++ {
+		f := fields.Get(i)
+		if e := f.Enum(); e != nil && e.IsPlaceholder() {
+			return fmt.Errorf("type %s of field %s not found", e.FullName(), f.FullName())
+		}
+		if m := f.Message(); m != nil {
+			if m.IsPlaceholder() {
+				return fmt.Errorf("type %s of field %s not found", m.FullName(), f.FullName())
+			}
+			if err := checkResolved(m, seen); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
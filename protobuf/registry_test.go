@@ -0,0 +1,74 @@
+package protobuf
+
+import (
+	"testing"
+
+	"github.com/Axili39/oastools/oasmodel"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// roundTrip converts json to binary and back with a dynamic message type
+func roundTrip(t *testing.T, mt protoreflect.MessageType, input string) {
+	t.Helper()
+	obj := mt.New().Interface()
+	err := protojson.Unmarshal([]byte(input), obj)
+	if err != nil {
+		t.Fatalf("error decoding json : %v", err)
+	}
+	data, err := proto.Marshal(obj)
+	if err != nil {
+		t.Fatalf("error encoding binary : %v", err)
+	}
+	decoded := mt.New().Interface()
+	err = proto.Unmarshal(data, decoded)
+	if err != nil {
+		t.Fatalf("error decoding binary : %v", err)
+	}
+	if !proto.Equal(obj, decoded) {
+		t.Errorf("round trip changed %v to %v", obj, decoded)
+	}
+}
+
+func TestMessageType(t *testing.T) {
+	mt, err := MessageType("tests/tree/api.yaml", "pet", GenerationOptions{Imports: map[string]bool{}, RequiredOption: true})
+	if err != nil {
+		t.Fatalf("error building pet : %v", err)
+	}
+	if name := mt.Descriptor().FullName(); name != "pets.v1.pet" {
+		t.Errorf("bad message name %s", name)
+	}
+	roundTrip(t, mt, `{"name": "rex", "owner": {"name": "bob", "status": {"code": 2, "updated": "2021-05-10T03:31:11Z"}}}`)
+
+	_, err = MessageType("tests/tree/api.yaml", "unknown", GenerationOptions{Imports: map[string]bool{}})
+	if err == nil {
+		t.Errorf("unknown component must fail")
+	}
+}
+
+func TestComponentType(t *testing.T) {
+	oa := oasmodel.OpenAPI{}
+	err := oa.Load("tests/maps.yaml")
+	if err != nil {
+		t.Fatalf("error loading spec : %v", err)
+	}
+	mt, err := ComponentType(&oa, "catalog", GenerationOptions{})
+	if err != nil {
+		t.Fatalf("error building catalog : %v", err)
+	}
+	roundTrip(t, mt, `{"name": "shop", "counts": {"fruits": 3}, "labels": {"a": {"text": "apple"}}, "item_prices": {"a": 1.5}}`)
+
+	oa = oasmodel.OpenAPI{}
+	err = oa.Load("tests/polymorphism.yaml")
+	if err != nil {
+		t.Fatalf("error loading spec : %v", err)
+	}
+	mt, err = ComponentType(&oa, "pet", GenerationOptions{})
+	if err != nil {
+		t.Fatalf("error building pet : %v", err)
+	}
+	if mt.Descriptor().Oneofs().Len() == 0 {
+		t.Errorf("pet must have a oneof")
+	}
+}
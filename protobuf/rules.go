@@ -0,0 +1,179 @@
+package protobuf
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/Axili39/oastools/oasmodel"
+)
+
+// ValidateRules : validation annotations generated from schema constraints
+type ValidateRules string
+
+// Validation annotations
+const (
+	ValidateNone ValidateRules = ""    // constraints are only documented
+	ValidatePGV  ValidateRules = "pgv" // protoc-gen-validate (validate.rules)
+	ValidateBuf  ValidateRules = "buf" // protovalidate (buf.validate.field)
+)
+
+// ParseValidateRules gives the ValidateRules of its name, "" or "none" for ValidateNone
+func ParseValidateRules(name string) (ValidateRules, error) {
+	switch ValidateRules(name) {
+	case ValidateNone, "none":
+		return ValidateNone, nil
+	case ValidatePGV, ValidateBuf:
+		return ValidateRules(name), nil
+	}
+	return ValidateNone, fmt.Errorf("unknown validate rules %s, expected none, pgv or buf", name)
+}
+
+// extension gives the field option holding the rules, the import is recorded
+func (v ValidateRules) extension(genOpts GenerationOptions) string {
+	if v == ValidateBuf {
+		genOpts.Imports["buf/validate/validate"] = true
+		return "(buf.validate.field)"
+	}
+	genOpts.Imports["validate/validate"] = true
+	return "(validate.rules)"
+}
+
+// string formats checked by both validators
+var formatRules = map[string]string{
+	"email":         "email",
+	"hostname":      "hostname",
+	"ipv4":          "ipv4",
+	"ipv6":          "ipv6",
+	"uri":           "uri",
+	"uri-reference": "uri_ref",
+	"uuid":          "uuid",
+}
+
+// validateOptions : rules of a field from the constraints of its property
+func validateOptions(f *MessageMembers, prop *oasmodel.SchemaOrRef, required bool, genOpts GenerationOptions) []Option {
+	schema := prop.Schema()
+	if genOpts.ValidateRules == ValidateNone || schema == nil {
+		return nil
+	}
+	var options []Option
+	if f.repeated {
+		var rules []string
+		if schema.MinItems != 0 {
+			rules = append(rules, fmt.Sprintf("min_items: %d", schema.MinItems))
+		}
+		if schema.MaxItems != 0 {
+			rules = append(rules, fmt.Sprintf("max_items: %d", schema.MaxItems))
+		}
+		if schema.UniqueItems {
+			rules = append(rules, "unique: true")
+		}
+		if schema.Items != nil && schema.Items.Schema() != nil {
+			if category, items := typeRules(f.typedecl, schema.Items.Schema()); len(items) > 0 {
+				rules = append(rules, fmt.Sprintf("items: {%s: {%s}}", category, strings.Join(items, ", ")))
+			}
+		}
+		if len(rules) > 0 {
+			options = append(options, Option{genOpts.ValidateRules.extension(genOpts) + ".repeated", "{" + strings.Join(rules, ", ") + "}"})
+		}
+		return options
+	}
+
+	if category, rules := typeRules(f.typedecl, schema); len(rules) > 0 {
+		options = append(options, Option{genOpts.ValidateRules.extension(genOpts) + "." + category, "{" + strings.Join(rules, ", ") + "}"})
+	}
+	// proto3 scalars have no presence, only messages are required
+	if required && !isScalarField(f, prop) {
+		if genOpts.ValidateRules == ValidateBuf {
+			options = append(options, Option{genOpts.ValidateRules.extension(genOpts) + ".required", "true"})
+		} else {
+			options = append(options, Option{genOpts.ValidateRules.extension(genOpts) + ".message", "{required: true}"})
+		}
+	}
+	return options
+}
+
+// typeRules gives the rules of a scalar or enum value, and their category : the proto type
+func typeRules(t ProtoType, schema *oasmodel.Schema) (string, []string) {
+	if isEnum(schema) {
+		return "enum", []string{"defined_only: true"}
+	}
+	if _, ok := t.(*TypeName); !ok {
+		return "", nil
+	}
+	category := t.Name()
+	// wrappers are checked with the rules of their value
+	for scalar, wrapper := range wrapperTypes {
+		if wrapper == category {
+			category = scalar
+		}
+	}
+	var rules []string
+	switch category {
+	case "string", "bytes":
+		minLength, maxLength := schema.MinLength, schema.MaxLength
+		if category == "bytes" && schema.Format == "byte" {
+			// lengths of the spec count base64 characters, 4 per 3 bytes padding included
+			minLength, maxLength = (minLength+3)/4*3-2, maxLength/4*3
+		}
+		if minLength > 0 {
+			rules = append(rules, fmt.Sprintf("min_len: %d", minLength))
+		}
+		if schema.MaxLength != 0 {
+			rules = append(rules, fmt.Sprintf("max_len: %d", maxLength))
+		}
+		if category == "string" && schema.Pattern != "" {
+			rules = append(rules, "pattern: "+strconv.Quote(schema.Pattern))
+		}
+		if rule, ok := formatRules[schema.Format]; ok && category == "string" {
+			rules = append(rules, rule+": true")
+		}
+	case "double", "float":
+		if value, exclusive, ok := schema.LowerBound(); ok {
+			rules = append(rules, bound("gt", exclusive, value))
+		}
+		if value, exclusive, ok := schema.UpperBound(); ok {
+			rules = append(rules, bound("lt", exclusive, value))
+		}
+	case "int32", "int64", "uint32", "uint64", "sint32", "sint64", "fixed32", "fixed64", "sfixed32", "sfixed64":
+		unsigned := strings.HasPrefix(category, "uint") || strings.HasPrefix(category, "fixed")
+		if value, exclusive, ok := schema.LowerBound(); ok {
+			if rule, ok := integerBound("gt", exclusive, value, unsigned); ok {
+				rules = append(rules, rule)
+			}
+		}
+		if value, exclusive, ok := schema.UpperBound(); ok {
+			if rule, ok := integerBound("lt", exclusive, value, unsigned); ok {
+				rules = append(rules, rule)
+			}
+		}
+	}
+	return category, rules
+}
+
+func bound(rule string, exclusive bool, value float64) string {
+	if !exclusive {
+		rule += "e"
+	}
+	return rule + ": " + strconv.FormatFloat(value, 'f', -1, 64)
+}
+
+// integerBound : rules of integer types take integers, fractional bounds are rounded inward to inclusive ones.
+// Negative bounds of unsigned types are dropped.
+func integerBound(rule string, exclusive bool, value float64, unsigned bool) (string, bool) {
+	rounded := math.Floor(value)
+	if rule == "gt" {
+		rounded = math.Ceil(value)
+	}
+	if rounded != value {
+		exclusive = false
+	}
+	if unsigned && rounded < 0 {
+		return "", false
+	}
+	if !exclusive {
+		rule += "e"
+	}
+	return fmt.Sprintf("%s: %d", rule, int64(rounded)), true
+}
@@ -0,0 +1,42 @@
+package protobuf
+
+import (
+	"bytes"
+	"io/ioutil"
+	"testing"
+
+	"github.com/Axili39/oastools/oasmodel"
+)
+
+func TestValidateRules(t *testing.T) {
+	for _, test := range []struct {
+		result  string
+		genOpts GenerationOptions
+	}{
+		{"tests/rules/rules-pgv.proto", GenerationOptions{Imports: map[string]bool{}, ValidateRules: ValidatePGV}},
+		{"tests/rules/rules-buf.proto", GenerationOptions{Imports: map[string]bool{}, ValidateRules: ValidateBuf}},
+		{"tests/rules/rules-wrappers.proto", GenerationOptions{Imports: map[string]bool{}, ValidateRules: ValidatePGV, Presence: PresenceWrappers}},
+	} {
+		oa := oasmodel.OpenAPI{}
+		err := oa.Load("tests/rules/rules.yaml")
+		if err != nil {
+			t.Fatalf("error loading spec : %v", err)
+		}
+		output := &bytes.Buffer{}
+		err = Components2Proto(&oa, output, "", test.genOpts, nil)
+		if err != nil {
+			t.Fatalf("error generating %s : %v", test.result, err)
+		}
+		expected, err := ioutil.ReadFile(test.result)
+		if err != nil {
+			t.Fatalf("error loading result file : %v", err)
+		}
+		if string(expected) != output.String() {
+			t.Errorf("Result differ for %s\ngot:\n%s\nexpected:\n%s", test.result, output.String(), string(expected))
+		}
+	}
+
+	if _, err := ParseValidateRules("strict"); err == nil {
+		t.Errorf("expected error for unknown validate rules")
+	}
+}
@@ -0,0 +1,381 @@
+package protobuf
+
+import (
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/Axili39/oastools/oasmodel"
+)
+
+// Service : gRPC service, one per tag or per spec
+type Service struct {
+	name    string
+	rpcs    []RPC
+	comment string
+}
+
+// RPC : service method, synthesised from an operation
+type RPC struct {
+	name     string
+	request  string
+	response string
+	comment  string
+	http     *HTTPRule // google.api.http annotation, nil if not generated
+}
+
+// HTTPRule : http mapping of a rpc for grpc-gateway
+type HTTPRule struct {
+	method string // lower case http method
+	path   string // path template
+	body   string // request field mapped to the request body, "" if none
+}
+
+// Name :  ProtoType interface realization
+func (t *Service) Name() string {
+	return t.name
+}
+
+// String gives the content of the google.api.http option, methods unknown to HttpRule use custom
+func (r *HTTPRule) String() string {
+	var rule string
+	switch r.method {
+	case "get", "put", "post", "delete", "patch":
+		rule = fmt.Sprintf("%s: %q", r.method, r.path)
+	default:
+		rule = fmt.Sprintf("custom: { kind: %q path: %q }", strings.ToUpper(r.method), r.path)
+	}
+	if r.body != "" {
+		rule += fmt.Sprintf(" body: %q", r.body)
+	}
+	return rule
+}
+
+// pathTemplate converts an OpenAPI path into a google.api.http path template, variables are request field names
+func pathTemplate(path string) string {
+	var b strings.Builder
+	for {
+		start := strings.Index(path, "{")
+		end := strings.Index(path, "}")
+		if start < 0 || end < start {
+			b.WriteString(path)
+			return b.String()
+		}
+		b.WriteString(path[:start+1])
+		b.WriteString(normalizeName(path[start+1 : end]))
+		b.WriteString("}")
+		path = path[end+1:]
+	}
+}
+
+// Name :  ProtoType interface realization
+func (t *RPC) Name() string {
+	return t.name
+}
+
+// camelCase converts an identifier to CamelCase : list_pets, list-pets and listPets give ListPets
+func camelCase(s string) string {
+	var b strings.Builder
+	upper := true
+	for _, r := range s {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			upper = true
+			continue
+		}
+		if upper {
+			r = unicode.ToUpper(r)
+			upper = false
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// operationName returns the rpc name of an operation, derived from method and path without operationId
+func operationName(path string, method string, op *oasmodel.Operation) string {
+	if op.OperationID != "" {
+		return camelCase(op.OperationID)
+	}
+	return camelCase(method + " " + strings.NewReplacer("{", "", "}", "").Replace(path))
+}
+
+// serviceName returns the name of the service of an operation
+func serviceName(oa *oasmodel.OpenAPI, op *oasmodel.Operation, genOpts GenerationOptions) string {
+	if genOpts.ServiceName != "" {
+		return genOpts.ServiceName
+	}
+	if len(op.Tags) > 0 {
+		return camelCase(op.Tags[0]) + "Service"
+	}
+	if title := camelCase(oa.Info.Title); title != "" {
+		return title + "Service"
+	}
+	return "APIService"
+}
+
+// createServices : create a service per tag with one rpc per operation, and their Request / Response messages.
+// defined holds the names of the messages already generated, to detect collisions.
+func createServices(oa *oasmodel.OpenAPI, genOpts GenerationOptions, defined map[string]bool) ([]ProtoType, []ProtoType, error) {
+	paths := make([]string, 0, len(oa.Paths))
+	for path := range oa.Paths {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	var messages []ProtoType
+	services := make(map[string]*Service)
+	for _, path := range paths {
+		item := oa.Paths[path]
+		for _, mo := range item.Operations() {
+			op := mo.Operation
+			name := operationName(path, mo.Method, op)
+			for _, suffix := range []string{"Request", "Response"} {
+				if defined[name+suffix] {
+					return nil, nil, fmt.Errorf("%s %s : message %s already defined", mo.Method, path, name+suffix)
+				}
+				defined[name+suffix] = true
+			}
+
+			request, body, err := createRequest(name+"Request", item.Parameters, op, genOpts)
+			if err != nil {
+				return nil, nil, fmt.Errorf("%s %s : %v", mo.Method, path, err)
+			}
+			response, err := createResponse(name+"Response", op, genOpts)
+			if err != nil {
+				return nil, nil, fmt.Errorf("%s %s : %v", mo.Method, path, err)
+			}
+			messages = append(messages, request, response)
+
+			sname := serviceName(oa, op, genOpts)
+			service, ok := services[sname]
+			if !ok {
+				service = &Service{name: sname, comment: tagDescription(oa, op, genOpts)}
+				services[sname] = service
+			}
+			for _, r := range service.rpcs {
+				if r.name == name {
+					return nil, nil, fmt.Errorf("%s %s : rpc %s already defined in %s", mo.Method, path, name, sname)
+				}
+			}
+			rpc := RPC{name, request.Name(), response.Name(), operationComment(op), nil}
+			if genOpts.HTTPAnnotations {
+				// google.api.http forbids a body on get and delete, the body field is bound to query parameters
+				if body != "" && (mo.Method == "get" || mo.Method == "delete") {
+					log.Printf("%s %s : request body isn't mapped, %s has no body", mo.Method, path, mo.Method)
+					body = ""
+				}
+				rpc.http = &HTTPRule{mo.Method, pathTemplate(path), body}
+			}
+			service.rpcs = append(service.rpcs, rpc)
+		}
+	}
+
+	names := make([]string, 0, len(services))
+	for name := range services {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	list := make([]ProtoType, 0, len(names))
+	for _, name := range names {
+		list = append(list, services[name])
+	}
+	return messages, list, nil
+}
+
+// operationComment documents a rpc : summary, description and deprecation
+func operationComment(op *oasmodel.Operation) string {
+	var lines []string
+	if op.Summary != "" {
+		lines = append(lines, op.Summary)
+	}
+	if op.Description != "" && op.Description != op.Summary {
+		lines = append(lines, op.Description)
+	}
+	if op.Deprecated {
+		lines = append(lines, "Deprecated.")
+	}
+	return strings.Join(lines, "\n")
+}
+
+// tagDescription returns the description of the tag of a service
+func tagDescription(oa *oasmodel.OpenAPI, op *oasmodel.Operation, genOpts GenerationOptions) string {
+	if genOpts.ServiceName != "" || len(op.Tags) == 0 {
+		return oa.Info.Description
+	}
+	for _, tag := range oa.Tags {
+		if tag.Name == op.Tags[0] {
+			return tag.Description
+		}
+	}
+	return ""
+}
+
+// createRequest : message holding path, query and header parameters, then the request body.
+// The name of the body field is returned, "" without request body.
+func createRequest(name string, common []oasmodel.ParameterOrRef, op *oasmodel.Operation, genOpts GenerationOptions) (ProtoType, string, error) {
+	schema := oasmodel.Schema{Type: oasmodel.SchemaType{"object"}, Properties: make(map[string]*oasmodel.SchemaOrRef), Description: op.Summary}
+
+	// operation parameters override path item parameters with the same name and location
+	var params []*oasmodel.Parameter
+	for _, list := range [][]oasmodel.ParameterOrRef{common, derefParameters(op.Parameters)} {
+		for i := range list {
+			p := list[i].Parameter()
+			if p == nil {
+				return nil, "", fmt.Errorf("unresolved parameter")
+			}
+			replaced := false
+			for j := range params {
+				if params[j].Name == p.Name && params[j].IN == p.IN {
+					params[j] = p
+					replaced = true
+				}
+			}
+			if !replaced {
+				params = append(params, p)
+			}
+		}
+	}
+	// parameters are identified by name and location, path parameters keep their name for the http path template
+	pathNames := make(map[string]bool)
+	for _, p := range params {
+		if p.IN == "path" {
+			pathNames[p.Name] = true
+		}
+	}
+	for _, p := range params {
+		if p.IN == "cookie" {
+			continue
+		}
+		prop := parameterSchema(p)
+		if prop == nil {
+			return nil, "", fmt.Errorf("parameter %s without schema", p.Name)
+		}
+		name := p.Name
+		if schema.Properties[name] != nil || p.IN != "path" && pathNames[name] {
+			name += "_" + p.IN
+		}
+		for schema.Properties[name] != nil {
+			name += "_"
+		}
+		schema.Properties[name] = prop
+		schema.XPropertiesOrder = append(schema.XPropertiesOrder, name)
+		// path parameters are always required
+		if p.Required || p.IN == "path" {
+			schema.Required = append(schema.Required, name)
+		}
+	}
+
+	bodyField := ""
+	if op.RequestBody != nil {
+		body := op.RequestBody.RequestBody()
+		if body == nil {
+			return nil, "", fmt.Errorf("unresolved request body")
+		}
+		if prop := contentSchema(body.Content); prop != nil {
+			bodyField = addBody(&schema, prop)
+			if body.Required {
+				schema.Required = append(schema.Required, bodyField)
+			}
+		}
+	}
+	message, err := createMessage(name, &schema, nil, genOpts)
+	return message, bodyField, err
+}
+
+// createResponse : message of the first 2xx response, inline objects are used as is, other schemas become a body field
+func createResponse(name string, op *oasmodel.Operation, genOpts GenerationOptions) (ProtoType, error) {
+	schema := oasmodel.Schema{Type: oasmodel.SchemaType{"object"}, Description: op.Summary}
+
+	codes := make([]string, 0, len(op.Responses))
+	for code := range op.Responses {
+		if strings.HasPrefix(code, "2") {
+			codes = append(codes, code)
+		}
+	}
+	sort.Strings(codes)
+	if len(codes) > 0 {
+		response := op.Responses[codes[0]].Response()
+		if response == nil {
+			return nil, fmt.Errorf("unresolved response %s", codes[0])
+		}
+		content := make(map[string]oasmodel.MediaType)
+		for mime, media := range response.Content {
+			if m := media.MediaType(); m != nil {
+				content[mime] = *m
+			}
+		}
+		if prop := contentSchema(content); prop != nil {
+			s := prop.Schema()
+			if prop.Ref == nil && s != nil && s.TypeName() == "object" && s.AdditionalProperties == nil && s.OneOf == nil && s.AnyOf == nil && s.AllOf == nil {
+				inline := *s
+				if inline.Description == "" {
+					inline.Description = op.Summary
+				}
+				return createMessage(name, &inline, nil, genOpts)
+			}
+			addBody(&schema, prop)
+		}
+	}
+	return createMessage(name, &schema, nil, genOpts)
+}
+
+// addBody adds the body field after parameters, renamed if a parameter is called body
+func addBody(schema *oasmodel.Schema, prop *oasmodel.SchemaOrRef) string {
+	name := "body"
+	for schema.Properties[name] != nil {
+		name += "_"
+	}
+	if schema.Properties == nil {
+		schema.Properties = make(map[string]*oasmodel.SchemaOrRef)
+	}
+	schema.Properties[name] = prop
+	schema.XPropertiesOrder = append(schema.XPropertiesOrder, name)
+	return name
+}
+
+func derefParameters(params []*oasmodel.ParameterOrRef) []oasmodel.ParameterOrRef {
+	list := make([]oasmodel.ParameterOrRef, 0, len(params))
+	for _, p := range params {
+		if p != nil {
+			list = append(list, *p)
+		}
+	}
+	return list
+}
+
+// parameterSchema returns the schema of a parameter, carrying the parameter description and deprecation
+func parameterSchema(p *oasmodel.Parameter) *oasmodel.SchemaOrRef {
+	prop := p.Schema
+	if prop == nil {
+		prop = contentSchema(p.Content)
+	}
+	if prop == nil || prop.Val == nil || (prop.Val.Description != "" || p.Description == "") && !p.Deprecated {
+		return prop
+	}
+	described := *prop.Val
+	if described.Description == "" {
+		described.Description = p.Description
+	}
+	described.Deprecated = described.Deprecated || p.Deprecated
+	return &oasmodel.SchemaOrRef{Val: &described}
+}
+
+// contentSchema returns the schema of the JSON media type, or of the first media type
+func contentSchema(content map[string]oasmodel.MediaType) *oasmodel.SchemaOrRef {
+	if media, ok := content["application/json"]; ok {
+		return media.Schema
+	}
+	mimes := make([]string, 0, len(content))
+	for mime := range content {
+		mimes = append(mimes, mime)
+	}
+	sort.Strings(mimes)
+	for _, mime := range mimes {
+		if content[mime].Schema != nil {
+			return content[mime].Schema
+		}
+	}
+	return nil
+}
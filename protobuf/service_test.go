@@ -0,0 +1,114 @@
+package protobuf
+
+import (
+	"bytes"
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	"github.com/Axili39/oastools/oasmodel"
+)
+
+func TestServices(t *testing.T) {
+	oa := oasmodel.OpenAPI{}
+	err := oa.Load("tests/services/petstore.yaml")
+	if err != nil {
+		t.Fatalf("error loading spec : %v", err)
+	}
+	output := &bytes.Buffer{}
+	err = Components2Proto(&oa, output, "", GenerationOptions{Imports: map[string]bool{}, Services: true}, nil)
+	if err != nil {
+		t.Fatalf("error generating services : %v", err)
+	}
+	expected, err := ioutil.ReadFile("tests/services/petstore.proto")
+	if err != nil {
+		t.Fatalf("error loading result file : %v", err)
+	}
+	if string(expected) != output.String() {
+		t.Errorf("Result differ \ngot:\n%s\nexpected:\n%s", output.String(), string(expected))
+	}
+
+	// single service
+	output.Reset()
+	err = Components2Proto(&oa, output, "", GenerationOptions{Imports: map[string]bool{}, ServiceName: "Store"}, nil)
+	if err != nil {
+		t.Fatalf("error generating services : %v", err)
+	}
+	if strings.Count(output.String(), "service ") != 1 || !strings.Contains(output.String(), "service Store {") {
+		t.Errorf("expected a single service :\n%s", output.String())
+	}
+}
+
+func TestServicesCollision(t *testing.T) {
+	oa := oasmodel.OpenAPI{}
+	_, err := oa.UnMarshal([]byte(`openapi: 3.0.0
+info:
+  title: collision
+  version: 1.0.0
+paths:
+  /pets:
+    get:
+      operationId: listPets
+      responses:
+        "200":
+          description: ok
+components:
+  schemas:
+    ListPetsRequest:
+      type: object
+`))
+	if err != nil {
+		t.Fatalf("error unmarshalling : %v", err)
+	}
+	err = Components2Proto(&oa, &bytes.Buffer{}, "", GenerationOptions{Imports: map[string]bool{}, Services: true}, nil)
+	if err == nil || !strings.Contains(err.Error(), "ListPetsRequest already defined") {
+		t.Errorf("collision not detected : %v", err)
+	}
+}
+
+func TestHTTPAnnotations(t *testing.T) {
+	oa := oasmodel.OpenAPI{}
+	err := oa.Load("tests/services/petstore.yaml")
+	if err != nil {
+		t.Fatalf("error loading spec : %v", err)
+	}
+	output := &bytes.Buffer{}
+	err = Components2Proto(&oa, output, "", GenerationOptions{Imports: map[string]bool{}, Services: true, HTTPAnnotations: true}, nil)
+	if err != nil {
+		t.Fatalf("error generating services : %v", err)
+	}
+	expected, err := ioutil.ReadFile("tests/services/petstore-http.proto")
+	if err != nil {
+		t.Fatalf("error loading result file : %v", err)
+	}
+	if string(expected) != output.String() {
+		t.Errorf("Result differ \ngot:\n%s\nexpected:\n%s", output.String(), string(expected))
+	}
+
+	rule := HTTPRule{"head", pathTemplate("/pets/{pet-id}/photos/{photo}"), ""}
+	if rule.String() != `custom: { kind: "HEAD" path: "/pets/{pet_id}/photos/{photo}" }` {
+		t.Errorf("bad custom rule : %s", rule.String())
+	}
+}
+
+func TestParameterLocations(t *testing.T) {
+	oa := oasmodel.OpenAPI{}
+	err := oa.Load("tests/services/parameters.yaml")
+	if err != nil {
+		t.Fatalf("error loading spec : %v", err)
+	}
+	// parameters with the same name in other locations are suffixed, the path parameter keeps its name
+	// the body of a get isn't mapped
+	output := &bytes.Buffer{}
+	err = Components2Proto(&oa, output, "", GenerationOptions{Imports: map[string]bool{}, Services: true, HTTPAnnotations: true}, nil)
+	if err != nil {
+		t.Fatalf("error generating services : %v", err)
+	}
+	expected, err := ioutil.ReadFile("tests/services/parameters.proto")
+	if err != nil {
+		t.Fatalf("error loading result file : %v", err)
+	}
+	if string(expected) != output.String() {
+		t.Errorf("Result differ \ngot:\n%s\nexpected:\n%s", output.String(), string(expected))
+	}
+}
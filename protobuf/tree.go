@@ -0,0 +1,202 @@
+package protobuf
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/Axili39/oastools/oasmodel"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+// ProtoFile : .proto generated from a document of a tree
+type ProtoFile struct {
+	Path    string // import path, relative to the tree, eg: common/types.proto
+	Package string // info.x-package, or the document name
+}
+
+// treeOptionsFile declares the custom options shared by the files of a tree,
+// it has no package so that (required) and (json_value) resolve from every package
+const treeOptionsFile = "oastools/options"
+
+// protoFile : types of a document, before writing
+type protoFile struct {
+	ProtoFile
+	imports  map[string]bool
+	nodes    []ProtoType
+	services []ProtoType
+}
+
+// Spec2ProtoTree loads filename and the documents it references, and writes a .proto per document in directory.
+// Files mirror the documents tree, services are generated for the root document only, packageName overrides its package.
+// Descriptors of the written files are returned, they are named by their path relative to directory.
+func Spec2ProtoTree(filename string, directory string, packageName string, genOpts GenerationOptions, options ...string) ([]*descriptorpb.FileDescriptorProto, error) {
+	files, err := CreateFiles(filename, packageName, genOpts, options...)
+	if err != nil {
+		return nil, err
+	}
+	for _, file := range files {
+		err = writeProtoFile(filepath.Join(directory, filepath.FromSlash(file.GetName())), file)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return files, nil
+}
+
+// CreateFiles loads filename and the documents it references, and converts them to the descriptors written by Spec2ProtoTree.
+// The descriptor of filename comes first.
+func CreateFiles(filename string, packageName string, genOpts GenerationOptions, options ...string) ([]*descriptorpb.FileDescriptorProto, error) {
+	loader := oasmodel.NewLoader()
+	_, err := loader.Load(filename)
+	if err != nil {
+		return nil, err
+	}
+	rootPath, err := filepath.Abs(filename)
+	if err != nil {
+		return nil, err
+	}
+	documents := loader.Documents()
+	sort.Slice(documents, func(i, j int) bool {
+		// root first
+		if documents[i] == rootPath || documents[j] == rootPath {
+			return documents[i] == rootPath
+		}
+		return documents[i] < documents[j]
+	})
+	genOpts.Files = protoFiles(loader, documents)
+	if packageName != "" {
+		root := genOpts.Files[rootPath]
+		root.Package = packageName
+		genOpts.Files[rootPath] = root
+	}
+
+	var files []protoFile
+	for _, path := range documents {
+		file := protoFile{ProtoFile: genOpts.Files[path], imports: make(map[string]bool)}
+		opts := genOpts
+		opts.Imports = file.imports
+		opts.packageName = file.Package
+		opts.optionsFile = treeOptionsFile
+		if path != rootPath {
+			opts.Services = false
+			opts.ServiceName = ""
+		}
+		file.nodes, file.services, err = CreateTypes(loader.Document(path), opts, nil)
+		if err != nil {
+			return nil, fmt.Errorf("%s : %v", file.Path, err)
+		}
+		files = append(files, file)
+	}
+	err = checkImportCycles(files)
+	if err != nil {
+		return nil, err
+	}
+
+	opts := genOpts
+	opts.Imports = make(map[string]bool)
+	if extensions := createOptions(opts); len(extensions) > 0 {
+		files = append(files, protoFile{ProtoFile: ProtoFile{Path: treeOptionsFile + ".proto"}, imports: opts.Imports, nodes: extensions})
+	}
+
+	descriptors := make([]*descriptorpb.FileDescriptorProto, 0, len(files))
+	for _, file := range files {
+		descriptor, err := newFile(file.Path, file.Package, file.imports, options, file.nodes, file.services)
+		if err != nil {
+			return nil, fmt.Errorf("%s : %v", file.Path, err)
+		}
+		descriptors = append(descriptors, descriptor)
+	}
+	return descriptors, nil
+}
+
+// protoFiles gives the .proto of each document, paths are relative to the directory holding all documents
+func protoFiles(loader *oasmodel.Loader, documents []string) map[string]ProtoFile {
+	files := make(map[string]ProtoFile)
+	common := commonDir(documents)
+	for _, path := range documents {
+		name := filepath.Base(path)
+		name = strings.TrimSuffix(name, filepath.Ext(name))
+		file := ProtoFile{Package: identifier(name)}
+		if rel, err := filepath.Rel(common, path); err == nil {
+			file.Path = filepath.ToSlash(strings.TrimSuffix(rel, filepath.Ext(rel))) + ".proto"
+		}
+		if pkg := loader.Document(path).Info.XPackage; pkg != "" {
+			file.Package = pkg
+		}
+		files[path] = file
+	}
+	return files
+}
+
+// commonDir gives the deepest directory holding all paths
+func commonDir(paths []string) string {
+	if len(paths) == 0 {
+		return ""
+	}
+	dir := filepath.Dir(paths[0])
+	for _, path := range paths[1:] {
+		for !strings.HasPrefix(path, dir+string(filepath.Separator)) && dir != filepath.Dir(dir) {
+			dir = filepath.Dir(dir)
+		}
+	}
+	return dir
+}
+
+// checkImportCycles : protoc rejects files importing one another
+func checkImportCycles(files []protoFile) error {
+	imports := make(map[string][]string)
+	for _, f := range files {
+		name := strings.TrimSuffix(f.Path, ".proto")
+		for i := range f.imports {
+			imports[name] = append(imports[name], i)
+		}
+	}
+	for _, list := range imports {
+		sort.Strings(list)
+	}
+	const (
+		visiting = 1
+		done     = 2
+	)
+	state := make(map[string]int)
+	var visit func(chain []string) error
+	visit = func(chain []string) error {
+		file := chain[len(chain)-1]
+		switch state[file] {
+		case visiting:
+			return fmt.Errorf("import cycle : %s.proto", strings.Join(chain, ".proto -> "))
+		case done:
+			return nil
+		}
+		state[file] = visiting
+		for _, i := range imports[file] {
+			if err := visit(append(chain, i)); err != nil {
+				return err
+			}
+		}
+		state[file] = done
+		return nil
+	}
+	for _, f := range files {
+		if err := visit([]string{strings.TrimSuffix(f.Path, ".proto")}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func writeProtoFile(filename string, file *descriptorpb.FileDescriptorProto) error {
+	err := os.MkdirAll(filepath.Dir(filename), 0755)
+	if err != nil {
+		return err
+	}
+	output, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+	defer output.Close()
+	return Print(output, file)
+}
@@ -0,0 +1,44 @@
+package protobuf
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestSpec2ProtoTree(t *testing.T) {
+	directory := t.TempDir()
+	genOpts := GenerationOptions{Imports: map[string]bool{}, Services: true, RequiredOption: true}
+	descriptors, err := Spec2ProtoTree("tests/tree/api.yaml", directory, "", genOpts)
+	if err != nil {
+		t.Fatalf("error generating tree : %v", err)
+	}
+	var files []string
+	for _, d := range descriptors {
+		files = append(files, d.GetName())
+	}
+	expected := []string{"api.proto", "common/types.proto", "shared/status.proto", "oastools/options.proto"}
+	if !reflect.DeepEqual(files, expected) {
+		t.Fatalf("bad files %v, expected %v", files, expected)
+	}
+	for _, file := range files {
+		result, err := ioutil.ReadFile(filepath.Join(directory, file))
+		if err != nil {
+			t.Fatalf("error reading %s : %v", file, err)
+		}
+		golden, err := ioutil.ReadFile(filepath.Join("tests/tree/proto", file))
+		if err != nil {
+			t.Fatalf("error loading result file : %v", err)
+		}
+		if string(golden) != string(result) {
+			t.Errorf("Result differ for %s\ngot:\n%s\nexpected:\n%s", file, string(result), string(golden))
+		}
+	}
+
+	_, err = Spec2ProtoTree("tests/tree/cycle/a.yaml", t.TempDir(), "", GenerationOptions{Imports: map[string]bool{}})
+	if err == nil || !strings.Contains(err.Error(), "import cycle : a.proto -> b.proto -> a.proto") {
+		t.Errorf("import cycle not detected : %v", err)
+	}
+}
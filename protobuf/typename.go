@@ -1,17 +1,18 @@
 package protobuf
 
 import (
-	"io"
+	"fmt"
+	"log"
+	"regexp"
+	"strings"
+
+	"github.com/Axili39/oastools/oasmodel"
 )
 
 // TypeName simple type or reference (by-name)
 type TypeName struct {
-	name string
-}
-
-// Declare : ProtoType interface realization
-func (t *TypeName) Declare(w io.Writer, indent string) {
-	// does't exist in protobuf
+	name  string
+	local bool // component of the generated file, qualified by its package in descriptors
 }
 
 // Name :  ProtoType interface realization
@@ -34,26 +35,118 @@ func (t *TypeName) Name() string {
 //
 //	| "sint32" | "sint64" | "fixed32" | "fixed64" | "sfixed32" | "sfixed64"
 //	| "bool" | "string" | "bytes" | messageType | enumType
-func createTypename(typename, format string) (ProtoType, error) {
-	if typename == "number" {
-		if format == "" {
-			return &TypeName{"double"}, nil
-		}
-		return &TypeName{format}, nil
+//
+// Integer formats may be any of the proto integer types, other formats are mapped by a TypeMapping.
+
+// MappedType : proto type of an OAS type and format, with the file to import
+type MappedType struct {
+	Name   string
+	Import string // imported file without .proto suffix, "" for scalars
+}
+
+// TypeMapping : proto types by OAS "type/format", or "type" when no format is given.
+// The "object" entry is used for free-form objects, the "" entry for schemas without type.
+type TypeMapping map[string]MappedType
+
+var protoScalars = map[string]bool{
+	"double": true, "float": true, "int32": true, "int64": true, "uint32": true, "uint64": true,
+	"sint32": true, "sint64": true, "fixed32": true, "fixed64": true, "sfixed32": true, "sfixed64": true,
+	"bool": true, "string": true, "bytes": true,
+}
+
+var identifierRe = regexp.MustCompile(`^\.?[A-Za-z_][A-Za-z0-9_]*(\.[A-Za-z_][A-Za-z0-9_]*)*$`)
+
+// DefaultTypeMapping gives scalars and well-known types for OAS and JSON Schema formats
+func DefaultTypeMapping() TypeMapping {
+	mapping := TypeMapping{
+		"number":           {"double", ""},
+		"number/float":     {"float", ""},
+		"number/double":    {"double", ""},
+		"integer":          {"int32", ""},
+		"boolean":          {"bool", ""},
+		"string":           {"string", ""},
+		"string/binary":    {"bytes", ""},
+		"string/byte":      {"bytes", ""},
+		"string/date-time": {"google.protobuf.Timestamp", "google/protobuf/timestamp"},
+		"string/duration":  {"google.protobuf.Duration", "google/protobuf/duration"},
+		"object":           {"google.protobuf.Struct", "google/protobuf/struct"},
+		"":                 {"google.protobuf.Value", "google/protobuf/struct"},
 	}
+	for _, format := range []string{"int32", "int64", "uint32", "uint64", "sint32", "sint64", "fixed32", "fixed64", "sfixed32", "sfixed64"} {
+		mapping["integer/"+format] = MappedType{format, ""}
+	}
+	for _, format := range []string{"date", "time", "password", "email", "idn-email", "hostname", "idn-hostname", "ipv4", "ipv6",
+		"uri", "uri-reference", "iri", "iri-reference", "uri-template", "uuid", "json-pointer", "relative-json-pointer", "regex"} {
+		mapping["string/"+format] = MappedType{"string", ""}
+	}
+	return mapping
+}
 
-	if typename == "integer" {
-		if format == "" {
-			return &TypeName{"int32"}, nil
+// Set adds or replaces a mapping from its definition : type/format=proto.Type[:import/file.proto]
+func (m TypeMapping) Set(definition string) error {
+	pair := strings.SplitN(definition, "=", 2)
+	if len(pair) != 2 || pair[0] == "" {
+		return fmt.Errorf("bad type mapping %s, expected type/format=proto.Type[:import/file.proto]", definition)
+	}
+	target := strings.SplitN(pair[1], ":", 2)
+	mapped := MappedType{Name: target[0]}
+	if len(target) == 2 {
+		mapped.Import = strings.TrimSuffix(target[1], ".proto")
+	}
+	if !protoScalars[mapped.Name] && !identifierRe.MatchString(mapped.Name) {
+		return fmt.Errorf("bad type mapping %s : %s is not a proto type", definition, mapped.Name)
+	}
+	m[pair[0]] = mapped
+	return nil
+}
+
+// lookup gives the proto type of an OAS type and format, the import is recorded
+func (m TypeMapping) lookup(typename, format string, genOpts GenerationOptions) (ProtoType, error) {
+	key := typename
+	if format != "" {
+		key += "/" + format
+	}
+	mapped, ok := m[key]
+	if !ok && format == "" && protoScalars[typename] {
+		// proto type used as type
+		mapped, ok = MappedType{typename, ""}, true
+	}
+	if !ok && typename == "string" && format != "" {
+		// formats are open-ended, custom string formats are strings
+		log.Printf("unknown format %s for type string, mapped as type string", format)
+		mapped, ok = m[typename]
+	}
+	if !ok {
+		if typename == "" {
+			return nil, fatalErrorf("schema without type")
 		}
-		return &TypeName{format}, nil
+		return nil, fatalErrorf("unknown format %s for type %s", format, typename)
 	}
-	if typename == "boolean" {
-		return &TypeName{"bool"}, nil
+	if mapped.Import != "" {
+		genOpts.Imports[mapped.Import] = true
 	}
+	return &TypeName{name: mapped.Name}, nil
+}
 
-	if typename == "string" && format == "binary" {
-		return &TypeName{"bytes"}, nil
+// createScalar gives the proto type of a schema which isn't a message, using genOpts.TypeMapping or DefaultTypeMapping
+func createScalar(schema *oasmodel.Schema, genOpts GenerationOptions) (ProtoType, error) {
+	mapping := genOpts.TypeMapping
+	if mapping == nil {
+		mapping = DefaultTypeMapping()
 	}
-	return &TypeName{typename}, nil
+	return mapping.lookup(schema.TypeName(), schema.Format, genOpts)
+}
+
+// isFreeForm returns true for objects accepting any property
+func isFreeForm(schema *oasmodel.Schema) bool {
+	if schema.TypeName() != "object" || len(schema.Properties) > 0 || schema.OneOf != nil || schema.AllOf != nil || schema.AnyOf != nil {
+		return false
+	}
+	additional := schema.AdditionalProperties
+	return additional == nil || (additional.IsBool && additional.BooleanValue)
+}
+
+// createTypename : type referenced by name
+func createTypename(typename string) (ProtoType, error) {
+	return &TypeName{name: typename}, nil
 }
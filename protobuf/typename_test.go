@@ -0,0 +1,91 @@
+package protobuf
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/Axili39/oastools/oasmodel"
+)
+
+func TestTypeMapping(t *testing.T) {
+	spec := `openapi: 3.0.0
+info:
+  title: formats
+  version: 1.0.0
+components:
+  schemas:
+    any:
+      type: object
+    foo:
+      type: object
+      x-properties-order: [timeout, day, data, extra, ref, value]
+      properties:
+        timeout:
+          type: string
+          format: duration
+        day:
+          type: string
+          format: date
+        data:
+          type: object
+        extra:
+          type: object
+          additionalProperties: true
+        ref:
+          $ref: '#/components/schemas/any'
+        value: {}
+`
+	oa := oasmodel.OpenAPI{}
+	if _, err := oa.UnMarshal([]byte(spec)); err != nil {
+		t.Fatalf("error loading spec : %v", err)
+	}
+	mapping := DefaultTypeMapping()
+	if err := mapping.Set("string/date=google.type.Date:google/type/date.proto"); err != nil {
+		t.Fatalf("error setting mapping : %v", err)
+	}
+	genOpts := GenerationOptions{Imports: map[string]bool{}, TypeMapping: mapping}
+	output := &bytes.Buffer{}
+	if err := Components2Proto(&oa, output, "", genOpts, nil); err != nil {
+		t.Fatalf("error generating : %v", err)
+	}
+	for _, expected := range []string{
+		"message any {",
+		"google.protobuf.Duration timeout = 1;",
+		"google.type.Date day = 2;",
+		"google.protobuf.Struct data = 3;",
+		"google.protobuf.Struct extra = 4;",
+		"google.protobuf.Struct ref = 5;",
+		"google.protobuf.Value value = 6;",
+	} {
+		if !strings.Contains(output.String(), expected) {
+			t.Errorf("expected %s in :\n%s", expected, output.String())
+		}
+	}
+	for _, imported := range []string{"google/protobuf/duration", "google/type/date", "google/protobuf/struct"} {
+		if !genOpts.Imports[imported] {
+			t.Errorf("missing import %s", imported)
+		}
+	}
+
+	for _, definition := range []string{"string/date", "string/date=not a type", "=string"} {
+		if err := mapping.Set(definition); err == nil {
+			t.Errorf("expected error for mapping %s", definition)
+		}
+	}
+}
+
+func TestUnknownFormat(t *testing.T) {
+	schema := &oasmodel.SchemaOrRef{Val: &oasmodel.Schema{Type: oasmodel.SchemaType{"integer"}, Format: "int128"}}
+	_, err := CreateType("foo", schema, &Message{}, GenerationOptions{Imports: map[string]bool{}})
+	if err == nil || err.Error() != "unknown format int128 for type integer" {
+		t.Errorf("expected unknown format error, got %v", err)
+	}
+
+	// custom string formats are strings
+	schema = &oasmodel.SchemaOrRef{Val: &oasmodel.Schema{Type: oasmodel.SchemaType{"string"}, Format: "phone"}}
+	typedecl, err := CreateType("foo", schema, &Message{}, GenerationOptions{Imports: map[string]bool{}})
+	if err != nil || typedecl.Name() != "string" {
+		t.Errorf("expected string for custom format, got %v %v", typedecl, err)
+	}
+}
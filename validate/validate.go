@@ -0,0 +1,486 @@
+package validate
+
+/*
+ Validation of instance data (decoded YAML or JSON) against an OpenAPI schema
+*/
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/Axili39/oastools/oasmodel"
+)
+
+// Violation is a constraint of the schema not satisfied by the instance
+type Violation struct {
+	Path    string // JSON Pointer of the faulty value in the instance, "" for the root
+	Keyword string // failing keyword : type, minimum, required...
+	Msg     string
+}
+
+func (v Violation) String() string {
+	path := v.Path
+	if path == "" {
+		path = "/"
+	}
+	return path + ": " + v.Msg
+}
+
+// Validate checks value against schema and returns every violation found.
+// value is the result of decoding YAML or JSON into an interface{} : maps, slices, strings, numbers, booleans or nil.
+// References of schema must have been resolved (see oasmodel.Loader or OpenAPI.ResolveRefs).
+func Validate(schema *oasmodel.SchemaOrRef, value interface{}) []Violation {
+	v := validator{patterns: make(map[string]*regexp.Regexp)}
+	v.validate(schema, normalize(value), "")
+	return v.violations
+}
+
+type validator struct {
+	patterns   map[string]*regexp.Regexp
+	violations []Violation
+}
+
+func (v *validator) report(path string, keyword string, format string, args ...interface{}) {
+	v.violations = append(v.violations, Violation{Path: path, Keyword: keyword, Msg: fmt.Sprintf(format, args...)})
+}
+
+// matches returns true if value is valid against schema, violations are not reported
+func (v *validator) matches(schema *oasmodel.SchemaOrRef, value interface{}, path string) bool {
+	sub := validator{patterns: v.patterns}
+	sub.validate(schema, value, path)
+	return len(sub.violations) == 0
+}
+
+func (v *validator) validate(schemaOrRef *oasmodel.SchemaOrRef, value interface{}, path string) {
+	if schemaOrRef == nil {
+		return
+	}
+	s := schemaOrRef.Schema()
+	if s == nil {
+		v.report(path, "$ref", "unresolved reference %s", schemaOrRef.Ref.Ref)
+		return
+	}
+
+	if !v.validateType(s, value, path) {
+		return
+	}
+	if value == nil && s.IsNullable() {
+		return
+	}
+	v.validateEnum(s, value, path)
+
+	switch val := value.(type) {
+	case float64:
+		v.validateNumber(s, val, path)
+	case string:
+		v.validateString(s, val, path)
+	case []interface{}:
+		v.validateArray(s, val, path)
+	case map[string]interface{}:
+		v.validateObject(s, val, path)
+	}
+	v.validateComposition(s, value, path)
+}
+
+func (v *validator) validateType(s *oasmodel.Schema, value interface{}, path string) bool {
+	if len(s.Type) == 0 {
+		return true
+	}
+	actual := typeOf(value)
+	if actual == "null" && s.IsNullable() {
+		return true
+	}
+	for _, t := range s.Type {
+		if t == actual || (t == "number" && actual == "integer") {
+			return true
+		}
+	}
+	v.report(path, "type", "expected %s, got %s", s.Type, actual)
+	return false
+}
+
+func (v *validator) validateEnum(s *oasmodel.Schema, value interface{}, path string) {
+	if len(s.Enum) > 0 {
+		str := scalarString(value)
+		found := false
+		for _, e := range s.Enum {
+			if e == str {
+				found = true
+				break
+			}
+		}
+		if !found {
+			v.report(path, "enum", "%s is not one of [%s]", str, strings.Join(s.Enum, ", "))
+		}
+	}
+	if s.Const != nil && canonical(normalize(s.Const)) != canonical(value) {
+		v.report(path, "const", "must be %v", s.Const)
+	}
+}
+
+func (v *validator) validateNumber(s *oasmodel.Schema, value float64, path string) {
+	if max, exclusive, ok := s.UpperBound(); ok {
+		if exclusive && value >= max {
+			v.report(path, "exclusiveMaximum", "%v must be lower than %v", value, max)
+		} else if value > max {
+			v.report(path, "maximum", "%v must be lower or equal to %v", value, max)
+		}
+	}
+	if min, exclusive, ok := s.LowerBound(); ok {
+		if exclusive && value <= min {
+			v.report(path, "exclusiveMinimum", "%v must be greater than %v", value, min)
+		} else if value < min {
+			v.report(path, "minimum", "%v must be greater or equal to %v", value, min)
+		}
+	}
+	if s.MultipleOf > 0 {
+		q := value / s.MultipleOf
+		if math.Abs(q-math.Round(q)) > 1e-9 {
+			v.report(path, "multipleOf", "%v is not a multiple of %v", value, s.MultipleOf)
+		}
+	}
+}
+
+func (v *validator) validateString(s *oasmodel.Schema, value string, path string) {
+	length := utf8.RuneCountInString(value)
+	if s.MaxLength > 0 && length > s.MaxLength {
+		v.report(path, "maxLength", "length %d is greater than %d", length, s.MaxLength)
+	}
+	if s.MinLength > 0 && length < s.MinLength {
+		v.report(path, "minLength", "length %d is lower than %d", length, s.MinLength)
+	}
+	if s.Pattern != "" {
+		re, err := v.pattern(s.Pattern)
+		if err != nil {
+			v.report(path, "pattern", "invalid pattern %s : %v", s.Pattern, err)
+		} else if !re.MatchString(value) {
+			v.report(path, "pattern", "%q does not match %s", value, s.Pattern)
+		}
+	}
+}
+
+// pattern compiles regular expressions once
+func (v *validator) pattern(expr string) (*regexp.Regexp, error) {
+	if re, ok := v.patterns[expr]; ok {
+		return re, nil
+	}
+	re, err := regexp.Compile(expr)
+	if err != nil {
+		return nil, err
+	}
+	v.patterns[expr] = re
+	return re, nil
+}
+
+func (v *validator) validateArray(s *oasmodel.Schema, value []interface{}, path string) {
+	if s.MaxItems > 0 && len(value) > s.MaxItems {
+		v.report(path, "maxItems", "%d items, maximum is %d", len(value), s.MaxItems)
+	}
+	if s.MinItems > 0 && len(value) < s.MinItems {
+		v.report(path, "minItems", "%d items, minimum is %d", len(value), s.MinItems)
+	}
+	if s.UniqueItems {
+		seen := make(map[string]int)
+		for i, item := range value {
+			key := canonical(item)
+			if first, found := seen[key]; found {
+				v.report(path+"/"+strconv.Itoa(i), "uniqueItems", "duplicate of item %d", first)
+				continue
+			}
+			seen[key] = i
+		}
+	}
+
+	for i, item := range value {
+		itemPath := path + "/" + strconv.Itoa(i)
+		if i < len(s.PrefixItems) {
+			v.validate(s.PrefixItems[i], item, itemPath)
+		} else {
+			v.validate(s.Items, item, itemPath)
+		}
+	}
+
+	if s.Contains != nil {
+		count := 0
+		for i, item := range value {
+			if v.matches(s.Contains, item, path+"/"+strconv.Itoa(i)) {
+				count++
+			}
+		}
+		min := 1
+		if s.MinContains != nil {
+			min = *s.MinContains
+		}
+		if count < min {
+			v.report(path, "contains", "%d items match contains, minimum is %d", count, min)
+		}
+		if s.MaxContains != nil && count > *s.MaxContains {
+			v.report(path, "maxContains", "%d items match contains, maximum is %d", count, *s.MaxContains)
+		}
+	}
+}
+
+func (v *validator) validateObject(s *oasmodel.Schema, value map[string]interface{}, path string) {
+	for _, name := range s.Required {
+		if _, found := value[name]; !found {
+			v.report(path, "required", "missing required property %q", name)
+		}
+	}
+	if s.MaxProperties > 0 && len(value) > s.MaxProperties {
+		v.report(path, "maxProperties", "%d properties, maximum is %d", len(value), s.MaxProperties)
+	}
+	if s.MinProperties > 0 && len(value) < s.MinProperties {
+		v.report(path, "minProperties", "%d properties, minimum is %d", len(value), s.MinProperties)
+	}
+
+	// properties are checked in a stable order
+	names := make([]string, 0, len(value))
+	for name := range value {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		propPath := path + "/" + oasmodel.EscapeToken(name)
+		if s.PropertyNames != nil {
+			v.validate(s.PropertyNames, name, propPath)
+		}
+		evaluated := false
+		if prop, found := s.Properties[name]; found {
+			v.validate(prop, value[name], propPath)
+			evaluated = true
+		}
+		for expr, prop := range s.PatternProperties {
+			re, err := v.pattern(expr)
+			if err != nil {
+				v.report(propPath, "patternProperties", "invalid pattern %s : %v", expr, err)
+				continue
+			}
+			if re.MatchString(name) {
+				v.validate(prop, value[name], propPath)
+				evaluated = true
+			}
+		}
+		if evaluated || s.AdditionalProperties == nil {
+			continue
+		}
+		ap := s.AdditionalProperties
+		if ap.IsBool && !ap.BooleanValue {
+			v.report(propPath, "additionalProperties", "property %q is not allowed", name)
+		} else if ap.Schema != nil {
+			v.validate(ap.Schema, value[name], propPath)
+		}
+	}
+
+	dependents := make([]string, 0, len(s.DependentRequired))
+	for name := range s.DependentRequired {
+		dependents = append(dependents, name)
+	}
+	sort.Strings(dependents)
+	for _, name := range dependents {
+		if _, found := value[name]; !found {
+			continue
+		}
+		for _, r := range s.DependentRequired[name] {
+			if _, found := value[r]; !found {
+				v.report(path, "dependentRequired", "property %q is required by %q", r, name)
+			}
+		}
+	}
+	dependents = dependents[:0]
+	for name := range s.DependentSchemas {
+		dependents = append(dependents, name)
+	}
+	sort.Strings(dependents)
+	for _, name := range dependents {
+		if _, found := value[name]; found {
+			v.validate(s.DependentSchemas[name], value, path)
+		}
+	}
+}
+
+func (v *validator) validateComposition(s *oasmodel.Schema, value interface{}, path string) {
+	for _, sub := range s.AllOf {
+		v.validate(sub, value, path)
+	}
+
+	// the discriminator only replaces oneOf / anyOf matching
+	discriminated := false
+	if s.Discriminator != nil && (len(s.OneOf) > 0 || len(s.AnyOf) > 0) {
+		if object, ok := value.(map[string]interface{}); ok {
+			discriminated = v.validateDiscriminator(s, object, path)
+		}
+	}
+
+	if len(s.AnyOf) > 0 && !discriminated {
+		found := false
+		for _, sub := range s.AnyOf {
+			if v.matches(sub, value, path) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			v.report(path, "anyOf", "does not match any schema of anyOf")
+		}
+	}
+	if len(s.OneOf) > 0 && !discriminated {
+		count := 0
+		for _, sub := range s.OneOf {
+			if v.matches(sub, value, path) {
+				count++
+			}
+		}
+		if count != 1 {
+			v.report(path, "oneOf", "must match exactly one schema of oneOf, matches %d", count)
+		}
+	}
+
+	if s.Not != nil && v.matches(s.Not, value, path) {
+		v.report(path, "not", "must not match the schema of not")
+	}
+
+	if s.If != nil {
+		if v.matches(s.If, value, path) {
+			v.validate(s.Then, value, path)
+		} else {
+			v.validate(s.Else, value, path)
+		}
+	}
+}
+
+// validateDiscriminator selects the schema of oneOf / anyOf designated by the discriminator property.
+// It returns false when no $ref is designated, inline schemas are then matched as usual.
+func (v *validator) validateDiscriminator(s *oasmodel.Schema, value map[string]interface{}, path string) bool {
+	name := s.Discriminator.PropertyName
+	prop, found := value[name]
+	if !found {
+		v.report(path, "discriminator", "missing discriminator property %q", name)
+		return true
+	}
+	kind := scalarString(prop)
+
+	// mapping gives a $ref or a schema name, default is the schema name
+	target := kind
+	if mapped, ok := s.Discriminator.Mapping[kind]; ok {
+		target = mapped
+	}
+	candidates := append(append([]*oasmodel.SchemaOrRef(nil), s.OneOf...), s.AnyOf...)
+	inline := false
+	for _, c := range candidates {
+		if c.Ref == nil {
+			inline = true
+			continue
+		}
+		if c.Ref.Ref == target || c.Ref.RefName == target || strings.HasSuffix(c.Ref.Ref, "/"+target) {
+			v.validate(c, value, path)
+			return true
+		}
+	}
+	if inline {
+		return false
+	}
+	v.report(path+"/"+oasmodel.EscapeToken(name), "discriminator", "unknown discriminator value %q", kind)
+	return true
+}
+
+// typeOf returns the JSON Schema type of a normalized value
+func typeOf(value interface{}) string {
+	switch val := value.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case float64:
+		if val == math.Trunc(val) && !math.IsInf(val, 0) {
+			return "integer"
+		}
+		return "number"
+	case string:
+		return "string"
+	case []interface{}:
+		return "array"
+	case map[string]interface{}:
+		return "object"
+	}
+	return fmt.Sprintf("%T", value)
+}
+
+// scalarString formats a scalar as written in a specification : enum values, discriminator values
+func scalarString(value interface{}) string {
+	switch val := value.(type) {
+	case float64:
+		return strconv.FormatFloat(val, 'f', -1, 64)
+	case string:
+		return val
+	case nil:
+		return "null"
+	}
+	return fmt.Sprint(value)
+}
+
+// canonical returns a comparable encoding of a normalized value, object keys are sorted
+func canonical(value interface{}) string {
+	buf, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprint(value)
+	}
+	return string(buf)
+}
+
+// normalize converts decoded YAML or JSON values : numbers to float64, maps to map[string]interface{},
+// timestamps decoded by yaml to their text, a date at midnight UTC or RFC 3339
+func normalize(value interface{}) interface{} {
+	switch val := value.(type) {
+	case map[string]interface{}:
+		m := make(map[string]interface{}, len(val))
+		for k, e := range val {
+			m[k] = normalize(e)
+		}
+		return m
+	case map[interface{}]interface{}:
+		m := make(map[string]interface{}, len(val))
+		for k, e := range val {
+			m[fmt.Sprint(k)] = normalize(e)
+		}
+		return m
+	case []interface{}:
+		s := make([]interface{}, len(val))
+		for i, e := range val {
+			s[i] = normalize(e)
+		}
+		return s
+	case int:
+		return float64(val)
+	case int32:
+		return float64(val)
+	case int64:
+		return float64(val)
+	case uint:
+		return float64(val)
+	case uint32:
+		return float64(val)
+	case uint64:
+		return float64(val)
+	case float32:
+		return float64(val)
+	case json.Number:
+		f, err := val.Float64()
+		if err != nil {
+			return val.String()
+		}
+		return f
+	case time.Time:
+		if val.Location() == time.UTC && val.Equal(val.Truncate(24*time.Hour)) {
+			return val.Format("2006-01-02")
+		}
+		return val.Format(time.RFC3339Nano)
+	}
+	return value
+}
@@ -0,0 +1,252 @@
+package validate
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/Axili39/oastools/oasmodel"
+	"gopkg.in/yaml.v3"
+)
+
+const spec = `openapi: 3.0.0
+info:
+    title: validate
+    version: 1.0.0
+paths: {}
+components:
+    schemas:
+        pet:
+            type: object
+            required: [name, kind]
+            properties:
+                name:
+                    type: string
+                    minLength: 2
+                    maxLength: 8
+                    pattern: ^[a-z]+$
+                kind:
+                    type: string
+                    enum: [cat, dog]
+                age:
+                    type: integer
+                    minimum: 0
+                    exclusiveMaximum: true
+                    maximum: 30
+                weight:
+                    type: number
+                    multipleOf: 0.5
+                    nullable: true
+                tags:
+                    type: array
+                    minItems: 1
+                    uniqueItems: true
+                    items:
+                        type: string
+                labels:
+                    type: object
+                    additionalProperties:
+                        type: integer
+        cat:
+            allOf:
+                - $ref: '#/components/schemas/pet'
+                - type: object
+                  required: [lives]
+                  properties:
+                      lives:
+                          type: integer
+                          maximum: 9
+        dog:
+            allOf:
+                - $ref: '#/components/schemas/pet'
+        animal:
+            oneOf:
+                - $ref: '#/components/schemas/cat'
+                - $ref: '#/components/schemas/dog'
+            discriminator:
+                propertyName: kind
+        id:
+            anyOf:
+                - type: integer
+                - type: string
+                  format: uuid
+        point:
+            type: object
+            additionalProperties: false
+            properties:
+                x:
+                    type: number
+        event:
+            type: object
+            properties:
+                day:
+                    type: string
+                    format: date
+                at:
+                    type: string
+                    format: date-time
+        creature:
+            oneOf:
+                - $ref: '#/components/schemas/dog'
+                - type: object
+                  required: [kind, wings]
+                  properties:
+                      wings:
+                          type: integer
+            discriminator:
+                propertyName: kind
+                mapping:
+                    bird: '#/components/schemas/creature/oneOf/1'
+            not:
+                required: [owner]
+        shape:
+            oneOf:
+                - type: object
+                  required: [radius]
+                - type: object
+                  required: [side]
+`
+
+func schemas(t *testing.T) map[string]*oasmodel.SchemaOrRef {
+	var oa oasmodel.OpenAPI
+	if _, err := oa.UnMarshal([]byte(spec)); err != nil {
+		t.Fatalf("error unmarshalling spec : %v", err)
+	}
+	if err := oa.ResolveRefs(); err != nil {
+		t.Fatalf("error resolving refs : %v", err)
+	}
+	return oa.Components.Schemas
+}
+
+func instance(t *testing.T, data string) interface{} {
+	var value interface{}
+	if err := yaml.Unmarshal([]byte(data), &value); err != nil {
+		t.Fatalf("error unmarshalling instance : %v", err)
+	}
+	return value
+}
+
+func TestValidate(t *testing.T) {
+	s := schemas(t)
+	tests := []struct {
+		schema   string
+		data     string
+		expected []Violation
+	}{
+		{"pet", `{name: felix, kind: cat, age: 3, weight: 4.5, tags: [a, b], labels: {x: 1}}`, nil},
+		{"pet", `{name: felix, kind: cat, weight: null}`, nil},
+		{"pet", `{name: F, kind: bird, age: 30, weight: 4.2, tags: [a, a], labels: {x: a}}`, []Violation{
+			{"/age", "exclusiveMaximum", "30 must be lower than 30"},
+			{"/kind", "enum", "bird is not one of [cat, dog]"},
+			{"/labels/x", "type", "expected integer, got string"},
+			{"/name", "minLength", "length 1 is lower than 2"},
+			{"/name", "pattern", `"F" does not match ^[a-z]+$`},
+			{"/tags/1", "uniqueItems", "duplicate of item 0"},
+			{"/weight", "multipleOf", "4.2 is not a multiple of 0.5"},
+		}},
+		{"pet", `{tags: []}`, []Violation{
+			{"", "required", `missing required property "name"`},
+			{"", "required", `missing required property "kind"`},
+			{"/tags", "minItems", "0 items, minimum is 1"},
+		}},
+		{"pet", `[]`, []Violation{{"", "type", "expected object, got array"}}},
+		{"animal", `{name: felix, kind: cat, lives: 7}`, nil},
+		{"animal", `{name: felix, kind: cat, lives: 10}`, []Violation{
+			{"/lives", "maximum", "10 must be lower or equal to 9"},
+		}},
+		{"animal", `{name: rex, kind: dog}`, nil},
+		{"animal", `{name: rex}`, []Violation{{"", "discriminator", `missing discriminator property "kind"`}}},
+		{"animal", `{name: rex, kind: fish}`, []Violation{{"/kind", "discriminator", `unknown discriminator value "fish"`}}},
+		{"creature", `{kind: bird, wings: 2}`, nil},
+		{"creature", `{kind: bird}`, []Violation{{"", "oneOf", "must match exactly one schema of oneOf, matches 0"}}},
+		{"creature", `{name: rex, kind: dog, owner: me}`, []Violation{{"", "not", "must not match the schema of not"}}},
+		{"point", `{x: 1, y: 2}`, []Violation{{"/y", "additionalProperties", `property "y" is not allowed`}}},
+		{"id", `12`, nil},
+		{"id", `1.5`, []Violation{{"", "anyOf", "does not match any schema of anyOf"}}},
+		{"event", `{day: 2020-01-01, at: 2020-01-01T10:20:30.5+02:00}`, nil},
+		{"shape", `{radius: 1}`, nil},
+		{"shape", `{radius: 1, side: 2}`, []Violation{{"", "oneOf", "must match exactly one schema of oneOf, matches 2"}}},
+	}
+	for _, test := range tests {
+		violations := Validate(s[test.schema], instance(t, test.data))
+		if !reflect.DeepEqual(violations, test.expected) {
+			t.Errorf("%s %s :\n got %v\n expected %v", test.schema, test.data, violations, test.expected)
+		}
+	}
+}
+
+func TestValidateOAS31(t *testing.T) {
+	var schema oasmodel.SchemaOrRef
+	err := yaml.Unmarshal([]byte(`
+type: [object, "null"]
+properties:
+    point:
+        type: array
+        prefixItems:
+            - type: number
+            - type: number
+        contains:
+            const: 0
+    limit:
+        type: integer
+        exclusiveMinimum: 0
+patternProperties:
+    ^x-:
+        type: string
+dependentRequired:
+    limit: [point]
+if:
+    required: [limit]
+then:
+    properties:
+        limit:
+            maximum: 10
+`), &schema)
+	if err != nil {
+		t.Fatalf("error unmarshalling schema : %v", err)
+	}
+	if v := Validate(&schema, nil); v != nil {
+		t.Errorf("null must be accepted : %v", v)
+	}
+	if v := Validate(&schema, instance(t, `{point: [1, 0], limit: 5, x-a: b}`)); v != nil {
+		t.Errorf("unexpected violations : %v", v)
+	}
+	expected := []Violation{
+		{"/limit", "exclusiveMinimum", "0 must be greater than 0"},
+		{"/point", "contains", "0 items match contains, minimum is 1"},
+		{"/x-a", "type", "expected string, got integer"},
+		{"", "dependentRequired", `property "point" is required by "limit"`},
+	}
+	v := Validate(&schema, instance(t, `{limit: 0, point: [1, 2], x-a: 1}`))
+	if len(v) != 3 || !reflect.DeepEqual(v, expected[:3]) {
+		t.Errorf("got %v expected %v", v, expected[:3])
+	}
+	v = Validate(&schema, instance(t, `{limit: 12}`))
+	if !reflect.DeepEqual(v, []Violation{expected[3], {"/limit", "maximum", "12 must be lower or equal to 10"}}) {
+		t.Errorf("got %v", v)
+	}
+}
+
+func TestValidateDependentOrder(t *testing.T) {
+	var schema oasmodel.SchemaOrRef
+	err := yaml.Unmarshal([]byte(`
+dependentRequired:
+    c: [z]
+    a: [x]
+    b: [y]
+`), &schema)
+	if err != nil {
+		t.Fatalf("error unmarshalling schema : %v", err)
+	}
+	expected := []Violation{
+		{"", "dependentRequired", `property "x" is required by "a"`},
+		{"", "dependentRequired", `property "y" is required by "b"`},
+		{"", "dependentRequired", `property "z" is required by "c"`},
+	}
+	// violations don't depend on map order
+	for i := 0; i < 10; i++ {
+		v := Validate(&schema, instance(t, `{a: 1, b: 1, c: 1}`))
+		if !reflect.DeepEqual(v, expected) {
+			t.Fatalf("got %v expected %v", v, expected)
+		}
+	}
+}